@@ -0,0 +1,114 @@
+// Package apn encodes and decodes Access Point Names and other domain names
+// using the length-prefixed label format that GTPv1 and GTPv2 IEs use
+// (TS 23.003 section 9.1).  In this format, "internet.example" is encoded
+// as 0x08 "internet" 0x07 "example".
+package apn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaximumLabelLength is the maximum number of octets in a single label
+const MaximumLabelLength = 63
+
+// MaximumAPNLength is the maximum encoded length of an APN (TS 23.003 section 9.1)
+const MaximumAPNLength = 100
+
+// MaximumFQDNLength is the maximum encoded length of an FQDN
+const MaximumFQDNLength = 255
+
+// EncodeLabels converts a dotted name (e.g., "internet.mnc001.mcc001.gprs") to
+// a sequence of length-prefixed labels.  Returns an error if any label is empty
+// or exceeds MaximumLabelLength, or if the encoded length exceeds maximumEncodedLength.
+func EncodeLabels(dottedName string, maximumEncodedLength int) ([]byte, error) {
+	if dottedName == "" {
+		return nil, fmt.Errorf("name is empty")
+	}
+
+	labels := strings.Split(dottedName, ".")
+
+	encodedLength := len(dottedName) + 1
+	if encodedLength > maximumEncodedLength {
+		return nil, fmt.Errorf("encoded length of name (%d) exceeds maximum (%d)", encodedLength, maximumEncodedLength)
+	}
+
+	encoded := make([]byte, 0, encodedLength)
+
+	for i, label := range labels {
+		if len(label) == 0 {
+			return nil, fmt.Errorf("label (%d) in name is empty", i+1)
+		}
+
+		if len(label) > MaximumLabelLength {
+			return nil, fmt.Errorf("label (%d) in name has length (%d), which exceeds maximum (%d)", i+1, len(label), MaximumLabelLength)
+		}
+
+		encoded = append(encoded, byte(len(label)))
+		encoded = append(encoded, label...)
+	}
+
+	return encoded, nil
+}
+
+// DecodeLabels converts a sequence of length-prefixed labels to a dotted name.
+// A single trailing zero-length (root) label is permitted and ignored.
+func DecodeLabels(encoded []byte) (string, error) {
+	if len(encoded) == 0 {
+		return "", fmt.Errorf("encoded name is empty")
+	}
+
+	labels := make([]string, 0, 5)
+
+	for i := 0; i < len(encoded); {
+		labelLength := int(encoded[i])
+
+		if labelLength == 0 {
+			if i != len(encoded)-1 {
+				return "", fmt.Errorf("zero-length label at offset (%d) is not at end of name", i)
+			}
+			break
+		}
+
+		if labelLength > MaximumLabelLength {
+			return "", fmt.Errorf("label at offset (%d) has length (%d), which exceeds maximum (%d)", i, labelLength, MaximumLabelLength)
+		}
+
+		if i+1+labelLength > len(encoded) {
+			return "", fmt.Errorf("label at offset (%d) has length (%d), but only (%d) octets remain", i, labelLength, len(encoded)-i-1)
+		}
+
+		labels = append(labels, string(encoded[i+1:i+1+labelLength]))
+		i += 1 + labelLength
+	}
+
+	if len(labels) == 0 {
+		return "", fmt.Errorf("encoded name contains no labels")
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+var matcherForOperatorIdentifier = regexp.MustCompile(`(?i)^(.+)\.(mnc\d{3}\.mcc\d{3}\.gprs)$`)
+
+// Split separates an APN into its Network Identifier (e.g., "internet") and its
+// Operator Identifier (e.g., "mnc001.mcc001.gprs").  If the APN does not end with
+// an Operator Identifier, operatorIdentifier is the empty string.
+func Split(apn string) (networkIdentifier string, operatorIdentifier string) {
+	if matches := matcherForOperatorIdentifier.FindStringSubmatch(apn); matches != nil {
+		return matches[1], matches[2]
+	}
+
+	return apn, ""
+}
+
+// OperatorIdentifierFor returns the APN Operator Identifier for an MCC and MNC
+// (e.g., "mnc001.mcc001.gprs").  A two-digit MNC is zero-padded to three digits.
+func OperatorIdentifierFor(mcc string, mnc string) string {
+	if len(mnc) == 2 {
+		mnc = "0" + mnc
+	}
+
+	return fmt.Sprintf("mnc%s.mcc%s.gprs", mnc, mcc)
+}
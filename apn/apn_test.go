@@ -0,0 +1,115 @@
+package apn_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/gtp/apn"
+	"github.com/go-test/deep"
+)
+
+func TestEncodeAndDecodeLabels(t *testing.T) {
+	testCases := []struct {
+		name    string
+		encoded []byte
+	}{
+		{
+			name:    "internet",
+			encoded: []byte{0x08, 'i', 'n', 't', 'e', 'r', 'n', 'e', 't'},
+		},
+		{
+			name: "internet.mnc001.mcc001.gprs",
+			encoded: []byte{
+				0x08, 'i', 'n', 't', 'e', 'r', 'n', 'e', 't',
+				0x06, 'm', 'n', 'c', '0', '0', '1',
+				0x06, 'm', 'c', 'c', '0', '0', '1',
+				0x04, 'g', 'p', 'r', 's',
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		encoded, err := apn.EncodeLabels(testCase.name, apn.MaximumAPNLength)
+		if err != nil {
+			t.Errorf("[%s] on EncodeLabels expected no error, got = (%s)", testCase.name, err.Error())
+		} else if diff := deep.Equal(testCase.encoded, encoded); diff != nil {
+			t.Errorf("[%s] on EncodeLabels: %s", testCase.name, diff)
+		}
+
+		decoded, err := apn.DecodeLabels(testCase.encoded)
+		if err != nil {
+			t.Errorf("[%s] on DecodeLabels expected no error, got = (%s)", testCase.name, err.Error())
+		} else if decoded != testCase.name {
+			t.Errorf("[%s] on DecodeLabels expected (%s), got (%s)", testCase.name, testCase.name, decoded)
+		}
+	}
+
+	if decoded, err := apn.DecodeLabels([]byte{0x03, 'a', 'b', 'c', 0x00}); err != nil || decoded != "abc" {
+		t.Errorf("[DecodeLabels] with trailing root label expected (abc), got (%s), err = (%v)", decoded, err)
+	}
+}
+
+func TestEncodeLabelsInvalidCases(t *testing.T) {
+	testCases := []struct {
+		name          string
+		maximumLength int
+	}{
+		{"", apn.MaximumAPNLength},
+		{"internet..gprs", apn.MaximumAPNLength},
+		{"internet.", apn.MaximumAPNLength},
+		{strings.Repeat("a", 64), apn.MaximumAPNLength},
+		{strings.Repeat("abcdefghi.", 10) + "j", apn.MaximumAPNLength},
+	}
+
+	for testIndex, testCase := range testCases {
+		if _, err := apn.EncodeLabels(testCase.name, testCase.maximumLength); err == nil {
+			t.Errorf("[EncodeLabels] on test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+
+	if _, err := apn.EncodeLabels(strings.Repeat("abcdefghi.", 10)+"j", apn.MaximumFQDNLength); err != nil {
+		t.Errorf("[EncodeLabels] on 102 octet name with FQDN maximum expected no error, got = (%s)", err.Error())
+	}
+}
+
+func TestDecodeLabelsInvalidCases(t *testing.T) {
+	testCases := [][]byte{
+		{},
+		{0x00},
+		{0x05, 'a', 'b'},
+		{0x01, 'a', 0x00, 0x01, 'b'},
+		append([]byte{0x40}, []byte(strings.Repeat("a", 64))...),
+	}
+
+	for testIndex, encoded := range testCases {
+		if _, err := apn.DecodeLabels(encoded); err == nil {
+			t.Errorf("[DecodeLabels] on test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		apn                        string
+		expectedNetworkIdentifier  string
+		expectedOperatorIdentifier string
+	}{
+		{"internet", "internet", ""},
+		{"internet.mnc001.mcc001.gprs", "internet", "mnc001.mcc001.gprs"},
+		{"ims.corp.MNC260.MCC310.GPRS", "ims.corp", "MNC260.MCC310.GPRS"},
+		{"mnc001.mcc001.gprs", "mnc001.mcc001.gprs", ""},
+		{"internet.mnc01.mcc001.gprs", "internet.mnc01.mcc001.gprs", ""},
+	}
+
+	for _, testCase := range testCases {
+		networkIdentifier, operatorIdentifier := apn.Split(testCase.apn)
+
+		if networkIdentifier != testCase.expectedNetworkIdentifier || operatorIdentifier != testCase.expectedOperatorIdentifier {
+			t.Errorf("[Split] for (%s) expected (%s, %s), got (%s, %s)", testCase.apn, testCase.expectedNetworkIdentifier, testCase.expectedOperatorIdentifier, networkIdentifier, operatorIdentifier)
+		}
+	}
+
+	if got := apn.OperatorIdentifierFor("310", "26"); got != "mnc026.mcc310.gprs" {
+		t.Errorf("[OperatorIdentifierFor] expected (mnc026.mcc310.gprs), got (%s)", got)
+	}
+}
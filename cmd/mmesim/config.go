@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Procedure names, which are also the names of the PDUs in the template
+const (
+	ProcedureAttach         = "Attach"
+	ProcedureRelease        = "Release"
+	ProcedureServiceRequest = "ServiceRequest"
+	ProcedureDetach         = "Detach"
+)
+
+var defaultProcedures = []string{ProcedureAttach, ProcedureRelease, ProcedureServiceRequest, ProcedureDetach}
+
+// Defaults for settings that are not in the configuration file
+const (
+	DefaultConcurrency = 10
+	DefaultTimeout     = 10 * time.Second
+	DefaultAPN         = "internet"
+	DefaultMNCLength   = 2
+)
+
+// PeerConfig is an SGW toward which UEs are attached
+type PeerConfig struct {
+	Name    string `yaml:"Name"`
+	Address string `yaml:"Address"`
+}
+
+// UEConfig describes the simulated UEs.  The IMSI of the first UE is
+// FirstIMSI, and each other UE has the next IMSI in sequence.  MCC and MNC are
+// set together, or are both taken from the start of FirstIMSI, in which case
+// MNCLength (2 or 3, with a default of 2) is the number of digits in the MNC.
+type UEConfig struct {
+	FirstIMSI string `yaml:"FirstIMSI"`
+	Count     int    `yaml:"Count"`
+	APN       string `yaml:"APN"`
+	MCC       string `yaml:"MCC"`
+	MNC       string `yaml:"MNC"`
+	MNCLength int    `yaml:"MNCLength"`
+}
+
+// Config is the simulator configuration file
+type Config struct {
+	// LocalAddress is the UDP address for the S11 socket.  The default is
+	// an ephemeral port on all addresses.
+	LocalAddress string `yaml:"LocalAddress"`
+
+	// MMEAddress is the IP address in the MME S11 F-TEID, ENodeBAddress is
+	// the IP address in the S1-U eNodeB F-TEID, and PGWAddress is the IP
+	// address in the PGW S5/S8 F-TEID of the Create Session Request
+	MMEAddress    string `yaml:"MMEAddress"`
+	ENodeBAddress string `yaml:"ENodeBAddress"`
+	PGWAddress    string `yaml:"PGWAddress"`
+
+	// Template is the path of a template file that replaces the built-in
+	// PDU for each procedure that it names
+	Template string `yaml:"Template"`
+
+	Peers []PeerConfig `yaml:"Peers"`
+	UEs   UEConfig     `yaml:"UEs"`
+
+	// Procedures is the call flow that is performed for each UE, in order.
+	// The default is Attach, Release, ServiceRequest and Detach.
+	Procedures []string `yaml:"Procedures"`
+
+	// Concurrency is the number of UEs that run their call flows at the same
+	// time.  Timeout (e.g., "5s") limits each procedure, including
+	// retransmissions.
+	Concurrency int    `yaml:"Concurrency"`
+	Timeout     string `yaml:"Timeout"`
+
+	timeout time.Duration
+}
+
+// ReadConfigFromFile reads and validates the configuration file
+func ReadConfigFromFile(filePath string) (*Config, error) {
+	contents, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadConfigFromString(string(contents))
+}
+
+// ReadConfigFromString reads and validates a configuration, and fills in the
+// defaults
+func ReadConfigFromString(definition string) (*Config, error) {
+	config := &Config{}
+	if err := yaml.UnmarshalStrict([]byte(definition), config); err != nil {
+		return nil, err
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (config *Config) validate() error {
+	if net.ParseIP(config.MMEAddress).To4() == nil {
+		return fmt.Errorf("MMEAddress (%s) is not an IPv4 address", config.MMEAddress)
+	}
+
+	if net.ParseIP(config.ENodeBAddress).To4() == nil {
+		return fmt.Errorf("ENodeBAddress (%s) is not an IPv4 address", config.ENodeBAddress)
+	}
+
+	if net.ParseIP(config.PGWAddress).To4() == nil {
+		return fmt.Errorf("PGWAddress (%s) is not an IPv4 address", config.PGWAddress)
+	}
+
+	if len(config.Peers) == 0 {
+		return fmt.Errorf("no Peers are configured")
+	}
+
+	for i, peer := range config.Peers {
+		if _, err := net.ResolveUDPAddr("udp", peer.Address); err != nil {
+			return fmt.Errorf("Peer (%s) Address (%s) is not valid: %s", peer.Name, peer.Address, err)
+		}
+
+		if peer.Name == "" {
+			config.Peers[i].Name = peer.Address
+		}
+	}
+
+	if config.UEs.Count < 1 {
+		return fmt.Errorf("UEs Count (%d) must be at least 1", config.UEs.Count)
+	}
+
+	if _, err := imsiAt(config.UEs.FirstIMSI, config.UEs.Count-1); err != nil {
+		return err
+	}
+
+	if config.UEs.APN == "" {
+		config.UEs.APN = DefaultAPN
+	}
+
+	if err := config.UEs.setPLMN(); err != nil {
+		return err
+	}
+
+	if len(config.Procedures) == 0 {
+		config.Procedures = defaultProcedures
+	}
+
+	for _, procedure := range config.Procedures {
+		if !procedureIsKnown(procedure) {
+			return fmt.Errorf("Procedure (%s) is not one of (%s, %s, %s, %s)", procedure, ProcedureAttach, ProcedureRelease, ProcedureServiceRequest, ProcedureDetach)
+		}
+	}
+
+	if config.Concurrency == 0 {
+		config.Concurrency = DefaultConcurrency
+	} else if config.Concurrency < 0 {
+		return fmt.Errorf("Concurrency (%d) must not be negative", config.Concurrency)
+	}
+
+	config.timeout = DefaultTimeout
+	if config.Timeout != "" {
+		timeout, err := time.ParseDuration(config.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("Timeout (%s) is not a positive duration", config.Timeout)
+		}
+		config.timeout = timeout
+	}
+
+	return nil
+}
+
+// setPLMN checks MCC and MNC, or takes them from FirstIMSI if neither is set
+func (ues *UEConfig) setPLMN() error {
+	if ues.MCC != "" || ues.MNC != "" {
+		if ues.MCC == "" || ues.MNC == "" {
+			return fmt.Errorf("UEs MCC (%s) and MNC (%s) must be set together", ues.MCC, ues.MNC)
+		}
+
+		if ues.MNCLength != 0 {
+			return fmt.Errorf("UEs MNCLength must not be set with MCC and MNC")
+		}
+
+		return nil
+	}
+
+	switch ues.MNCLength {
+	case 0:
+		ues.MNCLength = DefaultMNCLength
+	case 2, 3:
+	default:
+		return fmt.Errorf("UEs MNCLength (%d) must be 2 or 3", ues.MNCLength)
+	}
+
+	ues.MCC, ues.MNC = ues.FirstIMSI[:3], ues.FirstIMSI[3:3+ues.MNCLength]
+
+	return nil
+}
+
+func procedureIsKnown(procedure string) bool {
+	for _, known := range defaultProcedures {
+		if procedure == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// imsiAt returns the IMSI that is offset after firstIMSI, with the same
+// number of digits
+func imsiAt(firstIMSI string, offset int) (string, error) {
+	if len(firstIMSI) < 6 || len(firstIMSI) > 15 {
+		return "", fmt.Errorf("FirstIMSI (%s) must have between 6 and 15 digits", firstIMSI)
+	}
+
+	first, err := strconv.ParseUint(firstIMSI, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("FirstIMSI (%s) must contain only digits", firstIMSI)
+	}
+
+	imsi := fmt.Sprintf("%0*d", len(firstIMSI), first+uint64(offset))
+	if len(imsi) != len(firstIMSI) {
+		return "", fmt.Errorf("UEs Count is too large for FirstIMSI (%s)", firstIMSI)
+	}
+
+	return imsi, nil
+}
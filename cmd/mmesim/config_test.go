@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadConfigFromString(t *testing.T) {
+	config, err := ReadConfigFromString(`
+MMEAddress: 10.0.0.1
+ENodeBAddress: 10.0.1.1
+PGWAddress: 10.0.2.1
+Peers:
+  - Address: 127.0.0.1:2123
+UEs:
+  FirstIMSI: "001010000000099"
+  Count: 2
+`)
+	if err != nil {
+		t.Fatalf("[TestReadConfigFromString] expected no error, got (%s)", err)
+	}
+
+	if config.Peers[0].Name != "127.0.0.1:2123" || config.UEs.APN != DefaultAPN || config.UEs.MCC != "001" || config.UEs.MNC != "01" {
+		t.Errorf("[TestReadConfigFromString] expected defaults for Peer Name, APN, MCC and MNC, got (%s, %s, %s, %s)", config.Peers[0].Name, config.UEs.APN, config.UEs.MCC, config.UEs.MNC)
+	}
+
+	if len(config.Procedures) != 4 || config.Concurrency != DefaultConcurrency || config.timeout != DefaultTimeout {
+		t.Errorf("[TestReadConfigFromString] expected default Procedures, Concurrency and Timeout")
+	}
+
+	invalidConfigs := []string{
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nUEs: { FirstIMSI: \"001010000000001\", Count: 1 }\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"99999\", Count: 1 }\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"999999\", Count: 2 }\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1 }\nProcedures: [ Attach, Handover ]\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1 }\nTimeout: 10\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1 }\nUnknownSetting: 1\n",
+		"ENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1 }\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1, MCC: \"001\" }\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1, MNC: \"01\" }\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1, MNCLength: 4 }\n",
+		"MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1, MCC: \"001\", MNC: \"01\", MNCLength: 3 }\n",
+	}
+
+	for testNumber, definition := range invalidConfigs {
+		if _, err := ReadConfigFromString(definition); err == nil {
+			t.Errorf("[TestReadConfigFromString] on test number [%d] expected error, got none", testNumber)
+		}
+	}
+}
+
+func TestIMSIAt(t *testing.T) {
+	for testNumber, testCase := range []struct {
+		first    string
+		offset   int
+		expected string
+	}{
+		{"001010000000001", 0, "001010000000001"},
+		{"001010000000099", 2, "001010000000101"},
+		{"310150123456789", 10, "310150123456799"},
+	} {
+		if imsi, err := imsiAt(testCase.first, testCase.offset); err != nil || imsi != testCase.expected {
+			t.Errorf("[TestIMSIAt] on test number [%d] expected (%s), got (%s, %v)", testNumber, testCase.expected, imsi, err)
+		}
+	}
+}
+
+func TestParsedTimeout(t *testing.T) {
+	config, err := ReadConfigFromString("MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: { FirstIMSI: \"001010000000001\", Count: 1 }\nTimeout: 250ms\n")
+	if err != nil || config.timeout != 250*time.Millisecond {
+		t.Errorf("[TestParsedTimeout] expected Timeout (250ms), got (%v, %v)", config, err)
+	}
+}
+
+func TestConfigPLMN(t *testing.T) {
+	for testNumber, testCase := range []struct {
+		ues         string
+		expectedMCC string
+		expectedMNC string
+	}{
+		{`{ FirstIMSI: "310150123456789", Count: 1 }`, "310", "15"},
+		{`{ FirstIMSI: "310150123456789", Count: 1, MNCLength: 3 }`, "310", "150"},
+		{`{ FirstIMSI: "310150123456789", Count: 1, MCC: "311", MNC: "480" }`, "311", "480"},
+	} {
+		config, err := ReadConfigFromString("MMEAddress: 10.0.0.1\nENodeBAddress: 10.0.1.1\nPGWAddress: 10.0.2.1\nPeers: [ { Address: 127.0.0.1:2123 } ]\nUEs: " + testCase.ues + "\n")
+		if err != nil {
+			t.Errorf("[TestConfigPLMN] on test number [%d] expected no error, got (%s)", testNumber, err)
+			continue
+		}
+
+		if config.UEs.MCC != testCase.expectedMCC || config.UEs.MNC != testCase.expectedMNC {
+			t.Errorf("[TestConfigPLMN] on test number [%d] expected MCC (%s) and MNC (%s), got (%s) and (%s)", testNumber, testCase.expectedMCC, testCase.expectedMNC, config.UEs.MCC, config.UEs.MNC)
+		}
+	}
+}
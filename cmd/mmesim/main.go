@@ -0,0 +1,79 @@
+// Command mmesim acts as an MME toward one or more SGWs on S11.  For each of a
+// range of UEs, it performs a call flow of attach (Create Session), S1
+// release (Release Access Bearers), service request (Modify Bearer) and
+// detach (Delete Session), then writes the success and failure counts and the
+// latencies of each procedure.  The peers, UEs and call flow are read from a
+// YAML file:
+//
+//	MMEAddress: 10.0.0.1
+//	ENodeBAddress: 10.0.1.1
+//	PGWAddress: 10.0.2.1
+//	Peers:
+//	  - Name: sgw1
+//	    Address: 10.0.0.2:2123
+//	UEs:
+//	  FirstIMSI: "001010000000001"
+//	  Count: 1000
+//	  APN: internet
+//	Concurrency: 50
+//	Timeout: 10s
+//
+// The MCC and MNC of the UEs are taken from FirstIMSI with a 2 digit MNC,
+// unless UEs has MNCLength: 3, or sets MCC and MNC explicitly.
+//
+// The PDU for each procedure comes from a built-in gtpv2.Template.  A
+// Template file in the configuration replaces the PDUs that it names
+// (Attach, Release, ServiceRequest or Detach).  mmesim exits with status 1 if
+// any procedure fails.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run runs the simulator and returns the exit status
+func run() int {
+	configPath := flag.String("config", "", "path of the YAML configuration file")
+	flag.Parse()
+
+	if *configPath == "" || flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s -config <file.yaml>\n", os.Args[0])
+		return 2
+	}
+
+	config, err := ReadConfigFromFile(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "configuration (%s): %s\n", *configPath, err)
+		return 2
+	}
+
+	simulator, err := NewSimulator(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	defer simulator.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	summary := simulator.Run(ctx)
+
+	if err := summary.Write(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if summary.TotalFailures() > 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/blorticus-go/gtp/gtpv2"
+	"github.com/blorticus-go/gtp/teid"
+)
+
+//go:embed template.yaml
+var builtInTemplate string
+
+// defaultEBI is the EBI of the default bearer of each UE
+const defaultEBI = 5
+
+// errSkipped is recorded for a procedure that is not attempted because an
+// earlier procedure for the UE failed
+var errSkipped = errors.New("skipped after earlier failure")
+
+// ueTemplateData provides the values for the template of each procedure.
+// SGWTEID is set from the Create Session Response.
+type ueTemplateData struct {
+	IMSI          string
+	APN           string
+	MCC           string
+	MNC           string
+	EBI           uint8
+	MMEAddress    string
+	MMETEID       uint32
+	ENodeBAddress string
+	ENodeBTEID    uint32
+	PGWAddress    string
+	SGWTEID       uint32
+}
+
+// Simulator acts as an MME toward one or more SGWs, performing the configured
+// procedures for each UE and collecting the results in a Summary
+type Simulator struct {
+	config    *Config
+	templates []*gtpv2.Template
+	transport *gtpv2.Transport
+	clients   []*gtpv2.Client
+	paths     *gtpv2.PathManager
+
+	mmeTEIDs    *teid.Allocator
+	eNodeBTEIDs *teid.Allocator
+}
+
+// NewSimulator opens the S11 socket, and reads the template file, if there is
+// one
+func NewSimulator(config *Config) (*Simulator, error) {
+	builtIn, err := gtpv2.ReadYamlTemplateFromString(builtInTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("built-in template: %s", err)
+	}
+
+	templates := []*gtpv2.Template{builtIn}
+	if config.Template != "" {
+		fromFile, err := gtpv2.ReadYamlTemplateFromFile(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("template (%s): %s", config.Template, err)
+		}
+		templates = []*gtpv2.Template{fromFile, builtIn}
+	}
+
+	mmeTEIDs, _ := teid.NewAllocator(teid.AllocatorConfig{Strategy: teid.Random})
+	eNodeBTEIDs, _ := teid.NewAllocator(teid.AllocatorConfig{Strategy: teid.Random})
+
+	peers := make([]net.Addr, len(config.Peers))
+	for i, peer := range config.Peers {
+		if peers[i], err = net.ResolveUDPAddr("udp", peer.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	localAddress := config.LocalAddress
+	if localAddress == "" {
+		localAddress = ":0"
+	}
+
+	conn, err := net.ListenPacket("udp", localAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := gtpv2.NewTransport(conn, gtpv2.TransportConfig{})
+	sequenceNumbers := gtpv2.NewSequenceNumberManager()
+
+	clients := make([]*gtpv2.Client, len(peers))
+	for i, peer := range peers {
+		clients[i] = gtpv2.NewClient(transport, peer, sequenceNumbers)
+	}
+
+	return &Simulator{
+		config:      config,
+		templates:   templates,
+		transport:   transport,
+		clients:     clients,
+		paths:       gtpv2.NewPathManager(transport, gtpv2.PathManagerConfig{SequenceNumbers: sequenceNumbers}),
+		mmeTEIDs:    mmeTEIDs,
+		eNodeBTEIDs: eNodeBTEIDs,
+	}, nil
+}
+
+// LocalAddr returns the address of the S11 socket
+func (simulator *Simulator) LocalAddr() net.Addr {
+	return simulator.transport.LocalAddr()
+}
+
+// Run performs the procedures for every UE, with up to Concurrency UEs at a
+// time, and returns the results.  UEs are assigned to the Peers in turn.  If
+// ctx is done, UEs that have not started are not run.
+func (simulator *Simulator) Run(ctx context.Context) *Summary {
+	summary := newSummary(simulator.config.Procedures)
+
+	answerCtx, stopAnswering := context.WithCancel(context.Background())
+	defer stopAnswering()
+
+	go simulator.answerRequests(answerCtx, summary)
+
+	ueIndexes := make(chan int)
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < simulator.config.Concurrency; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for ueIndex := range ueIndexes {
+				simulator.runUE(ctx, ueIndex, summary)
+			}
+		}()
+	}
+
+	for ueIndex := 0; ueIndex < simulator.config.UEs.Count && ctx.Err() == nil; ueIndex++ {
+		select {
+		case ueIndexes <- ueIndex:
+		case <-ctx.Done():
+		}
+	}
+
+	close(ueIndexes)
+	waitGroup.Wait()
+
+	return summary
+}
+
+// Close closes the S11 socket
+func (simulator *Simulator) Close() error {
+	simulator.paths.Close()
+	return simulator.transport.Close()
+}
+
+// answerRequests answers Echo Requests from the SGWs, and rejects other
+// requests with Cause Service not supported, counting them in summary, until
+// ctx is done or the Transport is closed
+func (simulator *Simulator) answerRequests(ctx context.Context, summary *Summary) {
+	for {
+		incoming, err := simulator.transport.Receive(ctx)
+		if err != nil {
+			return
+		}
+
+		if incoming.PDU.Type == gtpv2.EchoRequest && incoming.Err == nil {
+			simulator.paths.HandleEchoRequest(incoming)
+			continue
+		}
+
+		summary.recordRejectedRequest(incoming.PDU.Type)
+
+		rejection := incoming.Err
+		if rejection == nil {
+			rejection = &gtpv2.RequestError{Cause: gtpv2.CauseServiceNotSupported, Reason: fmt.Errorf("the simulator does not handle (%s)", gtpv2.NameOfMessageForType(incoming.PDU.Type))}
+		}
+
+		if response, err := gtpv2.NewErrorResponse(incoming.PDU, rejection); err == nil {
+			simulator.transport.SendResponse(incoming.Peer, response)
+		}
+	}
+}
+
+// ue is the state of one simulated UE
+type ue struct {
+	client  *gtpv2.Client
+	session *gtpv2.Session
+	data    ueTemplateData
+}
+
+func (simulator *Simulator) runUE(ctx context.Context, ueIndex int, summary *Summary) {
+	imsi, _ := imsiAt(simulator.config.UEs.FirstIMSI, ueIndex)
+
+	ue := &ue{
+		client:  simulator.clients[ueIndex%len(simulator.clients)],
+		session: gtpv2.NewSession(gtpv2.SessionSideRequester),
+		data: ueTemplateData{
+			IMSI:          imsi,
+			APN:           simulator.config.UEs.APN,
+			MCC:           simulator.config.UEs.MCC,
+			MNC:           simulator.config.UEs.MNC,
+			EBI:           defaultEBI,
+			MMEAddress:    simulator.config.MMEAddress,
+			ENodeBAddress: simulator.config.ENodeBAddress,
+			PGWAddress:    simulator.config.PGWAddress,
+		},
+	}
+
+	var err error
+	if ue.data.MMETEID, err = simulator.mmeTEIDs.Allocate(); err == nil {
+		defer simulator.mmeTEIDs.Release(ue.data.MMETEID)
+		if ue.data.ENodeBTEID, err = simulator.eNodeBTEIDs.Allocate(); err == nil {
+			defer simulator.eNodeBTEIDs.Release(ue.data.ENodeBTEID)
+		}
+	}
+
+	for _, procedure := range simulator.config.Procedures {
+		// after a failure, the UE is still detached if it is attached, so
+		// that the SGW is not left with the session
+		if err != nil && !(procedure == ProcedureDetach && !ue.session.IsEmpty()) {
+			summary.recordFailure(procedure, errSkipped)
+			continue
+		}
+
+		started := time.Now()
+		procedureErr := simulator.runProcedure(ctx, ue, procedure)
+		if procedureErr != nil {
+			summary.recordFailure(procedure, procedureErr)
+			err = procedureErr
+		} else {
+			summary.recordSuccess(procedure, time.Since(started))
+		}
+	}
+}
+
+func (simulator *Simulator) runProcedure(ctx context.Context, ue *ue, procedure string) error {
+	request, err := simulator.generatePDU(procedure, &ue.data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, simulator.config.timeout)
+	defer cancel()
+
+	response, err := ue.client.SendRequest(ctx, request)
+
+	var rejection *gtpv2.RejectionError
+	if errors.As(err, &rejection) {
+		response = rejection.Response
+	} else if err != nil {
+		return err
+	}
+
+	// the Session does not track Release Access Bearers, which does not
+	// change the bearers
+	if procedure != ProcedureRelease {
+		if applyErr := ue.session.Apply(request); applyErr != nil {
+			return applyErr
+		}
+
+		if applyErr := ue.session.Apply(response); applyErr != nil {
+			return applyErr
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch procedure {
+	case ProcedureAttach:
+		if ue.session.RemoteFTEID == nil || ue.session.PDNConnections[defaultEBI] == nil {
+			return fmt.Errorf("Create Session Response has no SGW F-TEID or accepted default bearer")
+		}
+		ue.data.SGWTEID = ue.session.RemoteFTEID.Key
+
+	case ProcedureDetach:
+		if !ue.session.IsEmpty() {
+			return fmt.Errorf("Delete Session Response did not remove the PDN connection")
+		}
+	}
+
+	return nil
+}
+
+// generatePDU generates the PDU for a procedure from the first template that
+// has it
+func (simulator *Simulator) generatePDU(procedure string, data *ueTemplateData) (*gtpv2.PDU, error) {
+	for _, template := range simulator.templates {
+		if template.HasPDU(procedure) {
+			return template.GeneratePDUByName(procedure, data)
+		}
+	}
+
+	return nil, fmt.Errorf("no template has PDU (%s)", procedure)
+}
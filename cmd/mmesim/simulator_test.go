@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv2"
+)
+
+// fakeSGW answers the S11 requests of the simulator, rejecting the Create
+// Session Request for rejectedIMSI.  Before answering the Create Session
+// Request for createBearerIMSI, it sends a Create Bearer Request to the
+// simulator over transport, and keeps the answer in createBearerAnswer.
+type fakeSGW struct {
+	rejectedIMSI     string
+	createBearerIMSI string
+	transport        *gtpv2.Transport
+
+	lock     sync.Mutex
+	nextTEID uint32
+	sessions map[uint32]uint32 // SGW control TEID to MME control TEID
+
+	createBearerAnswer *gtpv2.PDU
+}
+
+func (sgw *fakeSGW) server() *gtpv2.Server {
+	server := gtpv2.NewServer(gtpv2.ServerConfig{})
+
+	server.HandleFunc(gtpv2.CreateSessionRequest, func(ctx context.Context, incoming *gtpv2.IncomingMessage) (*gtpv2.PDU, error) {
+		request := &gtpv2.TypedCreateSessionRequest{}
+		if err := request.Unmarshal(incoming.PDU); err != nil {
+			return nil, err
+		}
+
+		response := &gtpv2.TypedCreateSessionResponse{TEID: request.SenderFTEIDForControlPlane.Key}
+		if request.IMSI.AsString == sgw.rejectedIMSI {
+			response.Cause = &gtpv2.TypedCause{Value: gtpv2.CauseNoResourcesAvailable}
+			return response.Marshal()
+		}
+
+		if request.IMSI.AsString == sgw.createBearerIMSI {
+			cbRequest := gtpv2.NewPDU(gtpv2.CreateBearerRequest, 0x000100, []*gtpv2.IE{}).AddTEID(request.SenderFTEIDForControlPlane.Key)
+			if answer, err := sgw.transport.SendRequest(ctx, incoming.Peer, cbRequest); err == nil {
+				sgw.lock.Lock()
+				sgw.createBearerAnswer = answer.PDU
+				sgw.lock.Unlock()
+			}
+		}
+
+		sgw.lock.Lock()
+		sgw.nextTEID++
+		sgwTEID := sgw.nextTEID
+		sgw.sessions[sgwTEID] = request.SenderFTEIDForControlPlane.Key
+		sgw.lock.Unlock()
+
+		response.Cause = &gtpv2.TypedCause{Value: gtpv2.CauseRequestAccepted}
+		response.SenderFTEIDForControlPlane = &gtpv2.TypedFTEID{InterfaceType: 11, Key: sgwTEID, IPv4Addr: net.IP{127, 0, 0, 1}}
+		response.PAA = &gtpv2.TypedPAA{PDNType: gtpv2.PDNTypeIPv4, IPv4Address: net.IP{192, 168, 0, 1}}
+
+		bearerContext := response.NewBearerContextCreated(request.BearerContextsToBeCreated[0].EBI)
+		bearerContext.Cause = &gtpv2.TypedCause{Value: gtpv2.CauseRequestAccepted}
+		bearerContext.SetFTEID(gtpv2.FTEIDRoleS1USGW, &gtpv2.TypedFTEID{InterfaceType: 1, Key: sgwTEID, IPv4Addr: net.IP{127, 0, 0, 1}})
+
+		return response.Marshal()
+	})
+
+	server.HandleFunc(gtpv2.ReleaseAccessBearersRequest, func(ctx context.Context, incoming *gtpv2.IncomingMessage) (*gtpv2.PDU, error) {
+		return (&gtpv2.TypedReleaseAccessBearersResponse{TEID: sgw.mmeTEIDFor(incoming.PDU.TEID), Cause: &gtpv2.TypedCause{Value: gtpv2.CauseRequestAccepted}}).Marshal()
+	})
+
+	server.HandleFunc(gtpv2.ModifyBearerRequest, func(ctx context.Context, incoming *gtpv2.IncomingMessage) (*gtpv2.PDU, error) {
+		return (&gtpv2.TypedModifyBearerResponse{TEID: sgw.mmeTEIDFor(incoming.PDU.TEID), Cause: &gtpv2.TypedCause{Value: gtpv2.CauseRequestAccepted}}).Marshal()
+	})
+
+	server.HandleFunc(gtpv2.DeleteSessionRequest, func(ctx context.Context, incoming *gtpv2.IncomingMessage) (*gtpv2.PDU, error) {
+		mmeTEID := sgw.mmeTEIDFor(incoming.PDU.TEID)
+
+		sgw.lock.Lock()
+		delete(sgw.sessions, incoming.PDU.TEID)
+		sgw.lock.Unlock()
+
+		return (&gtpv2.TypedDeleteSessionResponse{TEID: mmeTEID, Cause: &gtpv2.TypedCause{Value: gtpv2.CauseRequestAccepted}}).Marshal()
+	})
+
+	return server
+}
+
+func (sgw *fakeSGW) mmeTEIDFor(sgwTEID uint32) uint32 {
+	sgw.lock.Lock()
+	defer sgw.lock.Unlock()
+
+	return sgw.sessions[sgwTEID]
+}
+
+func TestSimulatorCallFlow(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("[TestSimulatorCallFlow] failed to open SGW socket: %s", err)
+	}
+
+	sgwTransport := gtpv2.NewTransport(conn, gtpv2.TransportConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	sgw := &fakeSGW{rejectedIMSI: "001010000000003", createBearerIMSI: "001010000000005", transport: sgwTransport, sessions: make(map[uint32]uint32)}
+	go sgw.server().Serve(ctx, sgwTransport)
+
+	defer func() {
+		cancel()
+		sgwTransport.Close()
+	}()
+
+	config, err := ReadConfigFromString(`
+LocalAddress: 127.0.0.1:0
+MMEAddress: 127.0.0.1
+ENodeBAddress: 127.0.0.2
+PGWAddress: 127.0.0.3
+Peers:
+  - Name: sgw
+    Address: ` + sgwTransport.LocalAddr().String() + `
+UEs:
+  FirstIMSI: "001010000000001"
+  Count: 20
+Concurrency: 4
+Timeout: 2s
+`)
+	if err != nil {
+		t.Fatalf("[TestSimulatorCallFlow] expected no error on ReadConfigFromString, got (%s)", err)
+	}
+
+	simulator, err := NewSimulator(config)
+	if err != nil {
+		t.Fatalf("[TestSimulatorCallFlow] expected no error on NewSimulator, got (%s)", err)
+	}
+	defer simulator.Close()
+
+	summary := simulator.Run(context.Background())
+
+	for _, expected := range []struct {
+		procedure string
+		successes int
+		failures  int
+		reason    string
+	}{
+		{ProcedureAttach, 19, 1, "rejected with cause (No resources available)"},
+		{ProcedureRelease, 19, 1, "skipped after earlier failure"},
+		{ProcedureServiceRequest, 19, 1, "skipped after earlier failure"},
+		{ProcedureDetach, 19, 1, "skipped after earlier failure"},
+	} {
+		results := summary.Results(expected.procedure)
+		if results.Successes != expected.successes || results.Failures != expected.failures || len(results.Latencies) != expected.successes {
+			t.Errorf("[TestSimulatorCallFlow] expected (%d) successes and (%d) failures of (%s), got (%d) and (%d)", expected.successes, expected.failures, expected.procedure, results.Successes, results.Failures)
+		}
+
+		if results.FailureReasons[expected.reason] != expected.failures {
+			t.Errorf("[TestSimulatorCallFlow] expected (%s) failure reason (%s), got (%v)", expected.procedure, expected.reason, results.FailureReasons)
+		}
+	}
+
+	sgw.lock.Lock()
+	if sessions := len(sgw.sessions); sessions != 0 {
+		t.Errorf("[TestSimulatorCallFlow] expected no sessions left on SGW, got (%d)", sessions)
+	}
+
+	if sgw.createBearerAnswer == nil || sgw.createBearerAnswer.Type != gtpv2.CreateBearerResponse || causeValueIn(sgw.createBearerAnswer) != gtpv2.CauseServiceNotSupported {
+		t.Errorf("[TestSimulatorCallFlow] expected Create Bearer Request to be rejected with cause (%s)", gtpv2.CauseServiceNotSupported)
+	}
+	sgw.lock.Unlock()
+
+	if rejected := summary.RejectedRequests(); rejected["Create Bearer Request"] != 1 || len(rejected) != 1 {
+		t.Errorf("[TestSimulatorCallFlow] expected (1) rejected Create Bearer Request, got (%v)", rejected)
+	}
+
+	var output bytes.Buffer
+	if err := summary.Write(&output); err != nil {
+		t.Fatalf("[TestSimulatorCallFlow] expected no error on Write, got (%s)", err)
+	}
+
+	if !strings.Contains(output.String(), "ServiceRequest") || !strings.Contains(output.String(), "Attach: 1 x rejected with cause") || !strings.Contains(output.String(), "Create Bearer Request: 1") {
+		t.Errorf("[TestSimulatorCallFlow] summary is missing procedures or failure reasons:\n%s", output.String())
+	}
+}
+
+// causeValueIn returns the value of the Cause IE in pdu, or 0 if it has none
+func causeValueIn(pdu *gtpv2.PDU) gtpv2.CauseValue {
+	for _, ie := range pdu.InformationElements {
+		if ie.Type == gtpv2.Cause && len(ie.Data) > 0 {
+			return gtpv2.CauseValue(ie.Data[0])
+		}
+	}
+
+	return 0
+}
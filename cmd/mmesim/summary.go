@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blorticus-go/gtp/gtpv2"
+)
+
+// ProcedureResults are the results of one procedure for all UEs.  Latencies
+// are for successful attempts.
+type ProcedureResults struct {
+	Successes int
+	Failures  int
+	Latencies []time.Duration
+
+	// FailureReasons counts the failures by reason (e.g., "no response")
+	FailureReasons map[string]int
+}
+
+// Summary collects the results of each procedure.  A Summary is safe for
+// concurrent use.
+type Summary struct {
+	procedures []string
+
+	lock             sync.Mutex
+	results          map[string]*ProcedureResults
+	rejectedRequests map[string]int
+}
+
+func newSummary(procedures []string) *Summary {
+	results := make(map[string]*ProcedureResults)
+	for _, procedure := range procedures {
+		results[procedure] = &ProcedureResults{FailureReasons: make(map[string]int)}
+	}
+
+	return &Summary{procedures: procedures, results: results, rejectedRequests: make(map[string]int)}
+}
+
+func (summary *Summary) recordSuccess(procedure string, latency time.Duration) {
+	summary.lock.Lock()
+	defer summary.lock.Unlock()
+
+	results := summary.results[procedure]
+	results.Successes++
+	results.Latencies = append(results.Latencies, latency)
+}
+
+func (summary *Summary) recordFailure(procedure string, err error) {
+	summary.lock.Lock()
+	defer summary.lock.Unlock()
+
+	results := summary.results[procedure]
+	results.Failures++
+	results.FailureReasons[failureReason(err)]++
+}
+
+func (summary *Summary) recordRejectedRequest(messageType gtpv2.MessageType) {
+	summary.lock.Lock()
+	defer summary.lock.Unlock()
+
+	summary.rejectedRequests[gtpv2.NameOfMessageForType(messageType)]++
+}
+
+// Results returns the results of a procedure
+func (summary *Summary) Results(procedure string) ProcedureResults {
+	summary.lock.Lock()
+	defer summary.lock.Unlock()
+
+	results := *summary.results[procedure]
+	results.Latencies = append([]time.Duration(nil), results.Latencies...)
+
+	results.FailureReasons = make(map[string]int)
+	for reason, count := range summary.results[procedure].FailureReasons {
+		results.FailureReasons[reason] = count
+	}
+
+	return results
+}
+
+// TotalFailures returns the number of failures of all procedures
+func (summary *Summary) TotalFailures() int {
+	summary.lock.Lock()
+	defer summary.lock.Unlock()
+
+	total := 0
+	for _, results := range summary.results {
+		total += results.Failures
+	}
+
+	return total
+}
+
+// RejectedRequests returns the number of requests from the SGWs, other than
+// Echo Requests, that were rejected, by message type name
+func (summary *Summary) RejectedRequests() map[string]int {
+	summary.lock.Lock()
+	defer summary.lock.Unlock()
+
+	rejectedRequests := make(map[string]int)
+	for messageName, count := range summary.rejectedRequests {
+		rejectedRequests[messageName] = count
+	}
+
+	return rejectedRequests
+}
+
+// Write writes a table of the success and failure counts and latencies of
+// each procedure, followed by the reasons for the failures and the requests
+// from the SGWs that were rejected
+func (summary *Summary) Write(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "Procedure\tSuccess\tFailure\tMin\tAvg\tP50\tP95\tMax\t")
+
+	var reasons []string
+	for _, procedure := range summary.procedures {
+		results := summary.Results(procedure)
+		latencies := results.Latencies
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		fmt.Fprintf(table, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t\n", procedure, results.Successes, results.Failures,
+			latencyAt(latencies, 0), averageLatency(latencies), latencyAt(latencies, 0.50), latencyAt(latencies, 0.95), latencyAt(latencies, 1))
+
+		var procedureReasons []string
+		for reason, count := range results.FailureReasons {
+			procedureReasons = append(procedureReasons, fmt.Sprintf("%s: %d x %s", procedure, count, reason))
+		}
+		sort.Strings(procedureReasons)
+		reasons = append(reasons, procedureReasons...)
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if len(reasons) > 0 {
+		if _, err := fmt.Fprintf(writer, "\nFailures:\n  %s\n", strings.Join(reasons, "\n  ")); err != nil {
+			return err
+		}
+	}
+
+	var rejected []string
+	for messageName, count := range summary.RejectedRequests() {
+		rejected = append(rejected, fmt.Sprintf("%s: %d", messageName, count))
+	}
+	sort.Strings(rejected)
+
+	if len(rejected) > 0 {
+		if _, err := fmt.Fprintf(writer, "\nRejected SGW requests:\n  %s\n", strings.Join(rejected, "\n  ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// latencyAt returns the latency at a quantile of sorted latencies, or "-" if
+// there are none
+func latencyAt(sortedLatencies []time.Duration, quantile float64) string {
+	if len(sortedLatencies) == 0 {
+		return "-"
+	}
+
+	return roundLatency(sortedLatencies[int(quantile*float64(len(sortedLatencies)-1))])
+}
+
+func averageLatency(latencies []time.Duration) string {
+	if len(latencies) == 0 {
+		return "-"
+	}
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+
+	return roundLatency(total / time.Duration(len(latencies)))
+}
+
+func roundLatency(latency time.Duration) string {
+	return latency.Round(time.Microsecond).String()
+}
+
+// failureReason returns a short description of a failure, so that failures
+// with the same cause are counted together
+func failureReason(err error) string {
+	var rejection *gtpv2.RejectionError
+
+	switch {
+	case errors.As(err, &rejection):
+		return fmt.Sprintf("rejected with cause (%s)", rejection.Cause)
+	case errors.Is(err, gtpv2.ErrNoResponse):
+		return "no response"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, gtpv2.ErrIllegalTransition):
+		return "unexpected response"
+	case errors.Is(err, errSkipped):
+		return "skipped after earlier failure"
+	}
+
+	return err.Error()
+}
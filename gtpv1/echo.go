@@ -0,0 +1,40 @@
+package gtpv1
+
+import (
+	"fmt"
+)
+
+// NewEchoRequest creates an Echo Request with the sequence number
+func NewEchoRequest(sequenceNumber uint16) *PDU {
+	return NewPDU(EchoRequest, 0).UseSequenceNumber(sequenceNumber)
+}
+
+// NewEchoResponse creates the Echo Response to an Echo Request, with a Recovery
+// IE carrying the local restart counter (TS 29.060 section 7.2.2).  For
+// GTP-U, the restart counter should be 0.  Returns an error if request is not
+// an Echo Request.
+func NewEchoResponse(request *PDU, restartCounter uint8) (*PDU, error) {
+	if request.Type != EchoRequest {
+		return nil, fmt.Errorf("message type (%s) is not Echo Request", NameOfMessageForType(request.Type))
+	}
+
+	return NewPDU(EchoResponse, 0).UseSequenceNumber(request.SequenceNumber).WithInformationElements([]*IE{
+		NewIEWithRawData(Recovery, []byte{restartCounter}),
+	}), nil
+}
+
+// RestartCounterIn returns the value of the Recovery IE in a PDU, or false if
+// it has no well-formed Recovery IE.  A change in the value received from a
+// peer means that the peer has restarted.
+func RestartCounterIn(pdu *PDU) (uint8, bool) {
+	for _, ie := range pdu.InformationElements {
+		if ie.Type == Recovery {
+			if len(ie.Data) != 1 {
+				return 0, false
+			}
+			return ie.Data[0], true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,43 @@
+package gtpv1_test
+
+import (
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+)
+
+func TestEcho(t *testing.T) {
+	request := gtpv1.NewEchoRequest(0x0102)
+
+	response, err := gtpv1.NewEchoResponse(request, 5)
+	if err != nil {
+		t.Fatalf("[TestEcho] expected no error, got (%s)", err)
+	}
+
+	expected := []byte{0x32, 0x02, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x0e, 0x05}
+	encoded := response.Encode()
+	if err := compareByteArrays(expected, encoded); err != nil {
+		t.Errorf("[TestEcho] on Encode of Echo Response: %s", err)
+	}
+
+	decoded, err := gtpv1.DecodePDU(encoded)
+	if err != nil {
+		t.Fatalf("[TestEcho] expected no error on DecodePDU of Echo Response, got (%s)", err)
+	}
+
+	if decoded.Type != gtpv1.EchoResponse || decoded.SequenceNumber != 0x0102 || len(decoded.InformationElements) != 1 {
+		t.Errorf("[TestEcho] decoded Echo Response is not correct")
+	}
+
+	if restartCounter, isPresent := gtpv1.RestartCounterIn(response); !isPresent || restartCounter != 5 {
+		t.Errorf("[TestEcho] expected restart counter (5), got (%d, %t)", restartCounter, isPresent)
+	}
+
+	if _, isPresent := gtpv1.RestartCounterIn(request); isPresent {
+		t.Errorf("[TestEcho] expected no restart counter in Echo Request")
+	}
+
+	if _, err := gtpv1.NewEchoResponse(response, 5); err == nil {
+		t.Errorf("[TestEcho] expected error on NewEchoResponse for Echo Response, got none")
+	}
+}
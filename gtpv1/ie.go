@@ -46,6 +46,7 @@ const (
 	MMContext                             = 129
 	PDPContext                            = 130
 	AccessPointName                       = 131
+	APN                                   = 131
 	ProtocolConfigurationOptions          = 132
 	GSNAddress                            = 133
 	MSInternationalPSTNISDNNumber         = 134
@@ -214,6 +215,13 @@ func NameOfIEForType(ieType IEType) string {
 	return ieNames[int(ieType)]
 }
 
+// TypedIE represents any IE that has its encoded value converted
+// to a typed struct
+type TypedIE interface {
+	ToIE() *IE
+	ToIEErrorable() (*IE, error)
+}
+
 // IE is a GTPv1 Information Element.  Data is the BigEndian data bytes.
 type IE struct {
 	Type IEType
@@ -236,7 +244,8 @@ func (ie *IE) encodedLength() uint16 {
 // streamBytesConsumed is the number of bytes from the start of stream that
 // were consumed to produce IE.
 func DecodeIE(stream []byte) (ie *IE, streamBytesConsumed int, err error) {
-	if len(stream) < 4 {
+	// a TV IE with one octet of data, like Recovery, is only two octets long
+	if len(stream) < 2 {
 		return nil, 0, fmt.Errorf("insufficient octets in stream for a complete GTPv1 IE")
 	}
 
@@ -334,3 +343,30 @@ func (ie *IE) Encode() []byte {
 
 	return encodedBytes
 }
+
+// TypedDataErrorable converts the IE to its typed representation, returning
+// an error if there is no typed representation for the IE type or if the
+// IE data cannot be converted.
+func (ie *IE) TypedDataErrorable() (TypedIE, error) {
+	switch ie.Type {
+	case APN:
+		return makeTypedAPN(ie)
+	case FQDN:
+		return makeTypedFQDN(ie)
+	case IMSI:
+		return makeTypedIMSI(ie)
+	case IMEI:
+		return makeTypedIMEI(ie)
+	case MSISDN:
+		return makeTypedMSISDN(ie)
+	case SelectedPLMNID:
+		return makeTypedSelectedPLMNID(ie)
+	case ProtocolConfigurationOptions:
+		return makeTypedPCO(ie)
+	case TrafficFlowTemplate:
+		return makeTypedTFT(ie)
+
+	default:
+		return nil, fmt.Errorf("no type conversion for IE")
+	}
+}
@@ -0,0 +1,105 @@
+package gtpv1
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/apn"
+)
+
+// TypedAPN is a structured version of an Access Point Name IE.  AsString is
+// the dotted form of the APN (e.g., "internet.mnc001.mcc001.gprs").
+type TypedAPN struct {
+	AsString string
+}
+
+// NetworkIdentifier returns the APN Network Identifier (e.g., "internet")
+func (typedAPN *TypedAPN) NetworkIdentifier() string {
+	networkIdentifier, _ := apn.Split(typedAPN.AsString)
+	return networkIdentifier
+}
+
+// OperatorIdentifier returns the APN Operator Identifier (e.g.,
+// "mnc001.mcc001.gprs"), or the empty string if the APN does not
+// include one
+func (typedAPN *TypedAPN) OperatorIdentifier() string {
+	_, operatorIdentifier := apn.Split(typedAPN.AsString)
+	return operatorIdentifier
+}
+
+// ToIE creates an IE from the structured version of an APN, and
+// panics if there is an error
+func (typedAPN *TypedAPN) ToIE() *IE {
+	ie, err := typedAPN.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (typedAPN *TypedAPN) ToIEErrorable() (*IE, error) {
+	data, err := apn.EncodeLabels(typedAPN.AsString, apn.MaximumAPNLength)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APN: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(APN, data)
+}
+
+func makeTypedAPN(fromIE *IE) (*TypedAPN, error) {
+	if fromIE.Type != APN {
+		return nil, fmt.Errorf("supplied IE is not of type Access Point Name")
+	}
+
+	asString, err := apn.DecodeLabels(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APN encoding: %s", err)
+	}
+
+	return &TypedAPN{AsString: asString}, nil
+}
+
+// TypedFQDN is a structured version of a Fully Qualified Domain Name IE.
+// AsString is the dotted form of the name.
+type TypedFQDN struct {
+	AsString string
+}
+
+// ToIE creates an IE from the structured version of an FQDN, and
+// panics if there is an error
+func (fqdn *TypedFQDN) ToIE() *IE {
+	ie, err := fqdn.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (fqdn *TypedFQDN) ToIEErrorable() (*IE, error) {
+	data, err := apn.EncodeLabels(fqdn.AsString, apn.MaximumFQDNLength)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FQDN: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(FQDN, data)
+}
+
+func makeTypedFQDN(fromIE *IE) (*TypedFQDN, error) {
+	if fromIE.Type != FQDN {
+		return nil, fmt.Errorf("supplied IE is not of type FQDN")
+	}
+
+	asString, err := apn.DecodeLabels(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FQDN encoding: %s", err)
+	}
+
+	return &TypedFQDN{AsString: asString}, nil
+}
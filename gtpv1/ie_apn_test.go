@@ -0,0 +1,74 @@
+package gtpv1_test
+
+import (
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+	"github.com/go-test/deep"
+)
+
+func TestTypedAPN(t *testing.T) {
+	typedAPN := &gtpv1.TypedAPN{AsString: "internet.mnc001.mcc001.gprs"}
+	expectedEncoding := []byte{
+		131, 0x00, 0x1c,
+		0x08, 'i', 'n', 't', 'e', 'r', 'n', 'e', 't',
+		0x06, 'm', 'n', 'c', '0', '0', '1',
+		0x06, 'm', 'c', 'c', '0', '0', '1',
+		0x04, 'g', 'p', 'r', 's',
+	}
+
+	ie, err := typedAPN.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedAPN] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(expectedEncoding, ie.Encode()); diff != nil {
+		t.Errorf("[TestTypedAPN] on Encode(): %s", diff)
+	}
+
+	decodedIE, _, err := gtpv1.DecodeIE(expectedEncoding)
+	if err != nil {
+		t.Fatalf("[TestTypedAPN] did not expect error on DecodeIE, but got error = (%s)", err.Error())
+	}
+
+	typed, err := decodedIE.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedAPN] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	decodedAPN := typed.(*gtpv1.TypedAPN)
+
+	if diff := deep.Equal(typedAPN, decodedAPN); diff != nil {
+		t.Errorf("[TestTypedAPN] on TypedData: %s", diff)
+	}
+
+	if decodedAPN.NetworkIdentifier() != "internet" || decodedAPN.OperatorIdentifier() != "mnc001.mcc001.gprs" {
+		t.Errorf("[TestTypedAPN] expected identifiers (internet, mnc001.mcc001.gprs), got (%s, %s)", decodedAPN.NetworkIdentifier(), decodedAPN.OperatorIdentifier())
+	}
+}
+
+func TestTypedFQDN(t *testing.T) {
+	fqdn := &gtpv1.TypedFQDN{AsString: "ggsn.example.net"}
+
+	ie, err := fqdn.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedFQDN] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal([]byte{0x04, 'g', 'g', 's', 'n', 0x07, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0x03, 'n', 'e', 't'}, ie.Data); diff != nil {
+		t.Errorf("[TestTypedFQDN] on ToIEErrorable: %s", diff)
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedFQDN] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(fqdn, typed.(*gtpv1.TypedFQDN)); diff != nil {
+		t.Errorf("[TestTypedFQDN] on TypedData: %s", diff)
+	}
+
+	if _, err := gtpv1.NewIEWithRawData(gtpv1.FQDN, []byte{0x04, 'g', 'g'}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedFQDN] expected error on TypedData for truncated label, but got none")
+	}
+}
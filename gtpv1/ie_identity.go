@@ -0,0 +1,204 @@
+package gtpv1
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/tbcd"
+)
+
+// TypedIMSI is a structured version of an IMSI IE.  The IMSI IE has a fixed
+// length of 8 octets, so unused digit positions are encoded as filler.
+type TypedIMSI struct {
+	AsString string
+}
+
+// ToIE creates an IE from the structured version of an IMSI, and
+// panics if there is an error
+func (imsi *TypedIMSI) ToIE() *IE {
+	ie, err := imsi.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (imsi *TypedIMSI) ToIEErrorable() (*IE, error) {
+	if len(imsi.AsString) < 6 || len(imsi.AsString) > 15 {
+		return nil, fmt.Errorf("IMSI contains (%d) digits, but must contain between 6 and 15", len(imsi.AsString))
+	}
+
+	data, err := tbcd.EncodeToLength(imsi.AsString, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMSI: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(IMSI, data)
+}
+
+func makeTypedIMSI(fromIE *IE) (*TypedIMSI, error) {
+	if fromIE.Type != IMSI {
+		return nil, fmt.Errorf("supplied IE is not of type IMSI")
+	}
+
+	if len(fromIE.Data) != 8 {
+		return nil, fmt.Errorf("length of IE data is not correct for IMSI type")
+	}
+
+	asString, err := tbcd.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMSI encode value: %s", err)
+	}
+
+	return &TypedIMSI{AsString: asString}, nil
+}
+
+// TypedIMEI is a structured version of an IMEI(SV) IE.  AsString is either
+// a 15 digit IMEI or a 16 digit IMEISV.
+type TypedIMEI struct {
+	AsString string
+}
+
+// ToIE creates an IE from the structured version of an IMEI(SV), and
+// panics if there is an error
+func (imei *TypedIMEI) ToIE() *IE {
+	ie, err := imei.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (imei *TypedIMEI) ToIEErrorable() (*IE, error) {
+	if len(imei.AsString) != 15 && len(imei.AsString) != 16 {
+		return nil, fmt.Errorf("IMEI(SV) contains (%d) digits, but must contain 15 or 16", len(imei.AsString))
+	}
+
+	data, err := tbcd.EncodeToLength(imei.AsString, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMEI(SV): %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(IMEI, data)
+}
+
+func makeTypedIMEI(fromIE *IE) (*TypedIMEI, error) {
+	if fromIE.Type != IMEI {
+		return nil, fmt.Errorf("supplied IE is not of type IMEI(SV)")
+	}
+
+	if len(fromIE.Data) != 8 {
+		return nil, fmt.Errorf("length of IE data is not correct for IMEI(SV) type")
+	}
+
+	asString, err := tbcd.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMEI(SV) encode value: %s", err)
+	}
+
+	if len(asString) != 15 && len(asString) != 16 {
+		return nil, fmt.Errorf("IMEI(SV) contains (%d) digits, but must contain 15 or 16", len(asString))
+	}
+
+	return &TypedIMEI{AsString: asString}, nil
+}
+
+// MSISDN nature of address indicator values (TS 29.002 section 17.7.8).  The
+// nature of address is actually a uint3 value.
+const (
+	MSISDNNatureOfAddressUnknown                   = 0
+	MSISDNNatureOfAddressInternationalNumber       = 1
+	MSISDNNatureOfAddressNationalSignificantNumber = 2
+	MSISDNNatureOfAddressNetworkSpecificNumber     = 3
+	MSISDNNatureOfAddressSubscriberNumber          = 4
+)
+
+// MSISDN numbering plan indicator values (TS 29.002 section 17.7.8).  The
+// numbering plan is actually a uint4 value.
+const (
+	MSISDNNumberingPlanUnknown       = 0
+	MSISDNNumberingPlanISDNTelephony = 1
+	MSISDNNumberingPlanData          = 3
+	MSISDNNumberingPlanTelex         = 4
+	MSISDNNumberingPlanLandMobile    = 6
+	MSISDNNumberingPlanNational      = 8
+	MSISDNNumberingPlanPrivate       = 9
+)
+
+// TypedMSISDN is a structured version of an MSISDN IE, which is an
+// ISDN-AddressString: an octet carrying the nature of address and numbering
+// plan, followed by the TBCD-encoded digits.  The usual values are
+// MSISDNNatureOfAddressInternationalNumber and MSISDNNumberingPlanISDNTelephony.
+type TypedMSISDN struct {
+	NatureOfAddress uint8
+	NumberingPlan   uint8
+	AsString        string
+}
+
+// ToIE creates an IE from the structured version of an MSISDN, and
+// panics if there is an error
+func (msisdn *TypedMSISDN) ToIE() *IE {
+	ie, err := msisdn.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (msisdn *TypedMSISDN) ToIEErrorable() (*IE, error) {
+	if msisdn.NatureOfAddress > 0x07 {
+		return nil, fmt.Errorf("MSISDN nature of address (%d) exceeds maximum 3-bit value", msisdn.NatureOfAddress)
+	}
+
+	if msisdn.NumberingPlan > 0x0f {
+		return nil, fmt.Errorf("MSISDN numbering plan (%d) exceeds maximum 4-bit value", msisdn.NumberingPlan)
+	}
+
+	if len(msisdn.AsString) > 15 {
+		return nil, fmt.Errorf("MSISDN contains (%d) digits, but must contain no more than 15", len(msisdn.AsString))
+	}
+
+	digits, err := tbcd.Encode(msisdn.AsString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MSISDN: %s", err)
+	}
+
+	// the extension bit is always set because there is no extension octet
+	data := make([]byte, 1, len(digits)+1)
+	data[0] = 0x80 | msisdn.NatureOfAddress<<4 | msisdn.NumberingPlan
+	data = append(data, digits...)
+
+	return NewIEWithRawDataErrorable(MSISDN, data)
+}
+
+func makeTypedMSISDN(fromIE *IE) (*TypedMSISDN, error) {
+	if fromIE.Type != MSISDN {
+		return nil, fmt.Errorf("supplied IE is not of type MSISDN")
+	}
+
+	if len(fromIE.Data) < 2 || len(fromIE.Data) > 9 {
+		return nil, fmt.Errorf("length of IE data is not correct for MSISDN type")
+	}
+
+	asString, err := tbcd.Decode(fromIE.Data[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid MSISDN encode value: %s", err)
+	}
+
+	return &TypedMSISDN{
+		NatureOfAddress: (fromIE.Data[0] & 0x70) >> 4,
+		NumberingPlan:   fromIE.Data[0] & 0x0f,
+		AsString:        asString,
+	}, nil
+}
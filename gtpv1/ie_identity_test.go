@@ -0,0 +1,139 @@
+package gtpv1_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+	"github.com/go-test/deep"
+)
+
+func TestTypedIMSI(t *testing.T) {
+	testCases := []struct {
+		imsi            *gtpv1.TypedIMSI
+		expectedEncoded []byte
+	}{
+		{
+			imsi:            &gtpv1.TypedIMSI{AsString: "001002789012345"},
+			expectedEncoded: []byte{0x02, 0x00, 0x01, 0x20, 0x87, 0x09, 0x21, 0x43, 0xf5},
+		},
+		{
+			imsi:            &gtpv1.TypedIMSI{AsString: "3102601234"},
+			expectedEncoded: []byte{0x02, 0x13, 0x20, 0x06, 0x21, 0x43, 0xff, 0xff, 0xff},
+		},
+	}
+
+	for _, testCase := range testCases {
+		ie, err := testCase.imsi.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedIMSI] (%s) did not expect error, but got error = (%s)", testCase.imsi.AsString, err.Error())
+			continue
+		}
+
+		if diff := deep.Equal(testCase.expectedEncoded, ie.Encode()); diff != nil {
+			t.Errorf("[TestTypedIMSI] (%s) on Encode(): %s", testCase.imsi.AsString, diff)
+		}
+
+		decodedIE, _, err := gtpv1.DecodeIE(append(testCase.expectedEncoded, 0x00, 0x00))
+		if err != nil {
+			t.Errorf("[TestTypedIMSI] (%s) did not expect error on DecodeIE, but got error = (%s)", testCase.imsi.AsString, err.Error())
+			continue
+		}
+
+		typed, err := decodedIE.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedIMSI] (%s) expected no error on TypedData but got error = (%s)", testCase.imsi.AsString, err.Error())
+			continue
+		}
+
+		if diff := deep.Equal(testCase.imsi, typed.(*gtpv1.TypedIMSI)); diff != nil {
+			t.Errorf("[TestTypedIMSI] (%s) on TypedData: %s", testCase.imsi.AsString, diff)
+		}
+	}
+
+	_, err := (&gtpv1.TypedIMSI{AsString: "0010027890a2345"}).ToIEErrorable()
+	if err == nil {
+		t.Errorf("[TestTypedIMSI] expected error on ToIEErrorable for invalid digit, but got none")
+	} else if !strings.Contains(err.Error(), "position (11)") {
+		t.Errorf("[TestTypedIMSI] expected error on ToIEErrorable to identify position (11), got (%s)", err.Error())
+	}
+
+	_, err = gtpv1.NewIEWithRawData(gtpv1.IMSI, []byte{0x00, 0x01, 0x20, 0x87, 0x09, 0x2c, 0x43, 0xf5}).TypedDataErrorable()
+	if err == nil {
+		t.Errorf("[TestTypedIMSI] expected error on TypedData for invalid digit, but got none")
+	} else if !strings.Contains(err.Error(), "position (11)") {
+		t.Errorf("[TestTypedIMSI] expected error on TypedData to identify position (11), got (%s)", err.Error())
+	}
+}
+
+func TestTypedIMEI(t *testing.T) {
+	imei := &gtpv1.TypedIMEI{AsString: "3520990017614823"}
+
+	ie, err := imei.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedIMEI] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal([]byte{154, 0x00, 0x08, 0x53, 0x02, 0x99, 0x00, 0x71, 0x16, 0x84, 0x32}, ie.Encode()); diff != nil {
+		t.Errorf("[TestTypedIMEI] on Encode(): %s", diff)
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedIMEI] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(imei, typed.(*gtpv1.TypedIMEI)); diff != nil {
+		t.Errorf("[TestTypedIMEI] on TypedData: %s", diff)
+	}
+
+	if _, err := (&gtpv1.TypedIMEI{AsString: "35209900176148"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedIMEI] expected error on ToIEErrorable for 14 digits, but got none")
+	}
+
+	if _, err := (&gtpv1.TypedIMEI{AsString: "352099001761482x"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedIMEI] expected error on ToIEErrorable for invalid digit, but got none")
+	} else if !strings.Contains(err.Error(), "position (16)") {
+		t.Errorf("[TestTypedIMEI] expected error on ToIEErrorable to identify position (16), got (%s)", err.Error())
+	}
+}
+
+func TestTypedMSISDN(t *testing.T) {
+	msisdn := &gtpv1.TypedMSISDN{
+		NatureOfAddress: gtpv1.MSISDNNatureOfAddressInternationalNumber,
+		NumberingPlan:   gtpv1.MSISDNNumberingPlanISDNTelephony,
+		AsString:        "15551234567",
+	}
+
+	ie, err := msisdn.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedMSISDN] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal([]byte{134, 0x00, 0x07, 0x91, 0x51, 0x55, 0x21, 0x43, 0x65, 0xf7}, ie.Encode()); diff != nil {
+		t.Errorf("[TestTypedMSISDN] on Encode(): %s", diff)
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedMSISDN] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(msisdn, typed.(*gtpv1.TypedMSISDN)); diff != nil {
+		t.Errorf("[TestTypedMSISDN] on TypedData: %s", diff)
+	}
+
+	if _, err := (&gtpv1.TypedMSISDN{NatureOfAddress: 8, AsString: "1555"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedMSISDN] expected error on ToIEErrorable for nature of address 8, but got none")
+	}
+
+	if _, err := (&gtpv1.TypedMSISDN{AsString: "1555-1234"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedMSISDN] expected error on ToIEErrorable for invalid digit, but got none")
+	} else if !strings.Contains(err.Error(), "position (5)") {
+		t.Errorf("[TestTypedMSISDN] expected error on ToIEErrorable to identify position (5), got (%s)", err.Error())
+	}
+
+	if _, err := gtpv1.NewIEWithRawData(gtpv1.MSISDN, []byte{0x91}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedMSISDN] expected error on TypedData with no digits, but got none")
+	}
+}
@@ -0,0 +1,52 @@
+package gtpv1
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/pco"
+)
+
+// TypedPCO is a structured version of a Protocol Configuration Options IE
+type TypedPCO struct {
+	Container *pco.Container
+}
+
+// ToIE creates an IE from the structured version of a PCO, and
+// panics if there is an error
+func (typedPCO *TypedPCO) ToIE() *IE {
+	ie, err := typedPCO.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (typedPCO *TypedPCO) ToIEErrorable() (*IE, error) {
+	if typedPCO.Container == nil {
+		return nil, fmt.Errorf("invalid PCO: container is nil")
+	}
+
+	data, err := typedPCO.Container.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid PCO: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(ProtocolConfigurationOptions, data)
+}
+
+func makeTypedPCO(fromIE *IE) (*TypedPCO, error) {
+	if fromIE.Type != ProtocolConfigurationOptions {
+		return nil, fmt.Errorf("supplied IE is not of type PCO")
+	}
+
+	container, err := pco.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PCO encoding: %s", err)
+	}
+
+	return &TypedPCO{Container: container}, nil
+}
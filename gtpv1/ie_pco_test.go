@@ -0,0 +1,37 @@
+package gtpv1_test
+
+import (
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+	"github.com/blorticus-go/gtp/pco"
+	"github.com/go-test/deep"
+)
+
+func TestTypedPCO(t *testing.T) {
+	typedPCO := &gtpv1.TypedPCO{Container: pco.NewContainer(pco.NewRequestEntry(pco.IDDNSServerIPv4Address), pco.NewRequestEntry(pco.IDPCSCFIPv4Address))}
+	expectedEncoding := []byte{132, 0x00, 0x07, 0x80, 0x00, 0x0d, 0x00, 0x00, 0x0c, 0x00}
+
+	ie, err := typedPCO.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedPCO] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(expectedEncoding, ie.Encode()); diff != nil {
+		t.Errorf("[TestTypedPCO] on Encode(): %s", diff)
+	}
+
+	decodedIE, _, err := gtpv1.DecodeIE(expectedEncoding)
+	if err != nil {
+		t.Fatalf("[TestTypedPCO] did not expect error on DecodeIE, but got error = (%s)", err.Error())
+	}
+
+	typed, err := decodedIE.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedPCO] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(typedPCO, typed.(*gtpv1.TypedPCO)); diff != nil {
+		t.Errorf("[TestTypedPCO] on TypedData: %s", diff)
+	}
+}
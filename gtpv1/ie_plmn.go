@@ -0,0 +1,52 @@
+package gtpv1
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/plmn"
+)
+
+// TypedSelectedPLMNID is a structured version of a Selected PLMN ID IE
+type TypedSelectedPLMNID struct {
+	PLMN plmn.PLMN
+}
+
+// ToIE creates an IE from the structured version of a Selected PLMN ID, and
+// panics if there is an error
+func (selectedPLMNID *TypedSelectedPLMNID) ToIE() *IE {
+	ie, err := selectedPLMNID.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (selectedPLMNID *TypedSelectedPLMNID) ToIEErrorable() (*IE, error) {
+	data, err := selectedPLMNID.PLMN.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Selected PLMN ID: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(SelectedPLMNID, data)
+}
+
+func makeTypedSelectedPLMNID(fromIE *IE) (*TypedSelectedPLMNID, error) {
+	if fromIE.Type != SelectedPLMNID {
+		return nil, fmt.Errorf("supplied IE is not of type Selected PLMN ID")
+	}
+
+	if len(fromIE.Data) != plmn.EncodedLength {
+		return nil, fmt.Errorf("length of IE data is not correct for Selected PLMN ID type")
+	}
+
+	decoded, err := plmn.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Selected PLMN ID encode value: %s", err)
+	}
+
+	return &TypedSelectedPLMNID{PLMN: decoded}, nil
+}
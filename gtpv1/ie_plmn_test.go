@@ -0,0 +1,35 @@
+package gtpv1_test
+
+import (
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+	"github.com/blorticus-go/gtp/plmn"
+	"github.com/go-test/deep"
+)
+
+func TestTypedSelectedPLMNID(t *testing.T) {
+	selectedPLMNID := &gtpv1.TypedSelectedPLMNID{PLMN: plmn.PLMN{MCC: "310", MNC: "260"}}
+
+	ie, err := selectedPLMNID.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedSelectedPLMNID] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal([]byte{164, 0x00, 0x03, 0x13, 0x00, 0x62}, ie.Encode()); diff != nil {
+		t.Errorf("[TestTypedSelectedPLMNID] on Encode(): %s", diff)
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedSelectedPLMNID] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(selectedPLMNID, typed.(*gtpv1.TypedSelectedPLMNID)); diff != nil {
+		t.Errorf("[TestTypedSelectedPLMNID] on TypedData: %s", diff)
+	}
+
+	if _, err := (&gtpv1.TypedSelectedPLMNID{PLMN: plmn.PLMN{MCC: "31", MNC: "260"}}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedSelectedPLMNID] expected error on ToIEErrorable for two digit MCC, but got none")
+	}
+}
@@ -2,6 +2,9 @@ package gtpv1_test
 
 import (
 	"fmt"
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
 )
 
 // type v1IEComparable struct {
@@ -341,3 +344,18 @@ func compareByteArrays(expected []byte, got []byte) error {
 
 	return nil
 }
+
+func TestDecodeIEShortTV(t *testing.T) {
+	ie, bytesConsumed, err := gtpv1.DecodeIE([]byte{0x0e, 0x05})
+	if err != nil {
+		t.Fatalf("[TestDecodeIEShortTV] expected no error on DecodeIE of Recovery, got (%s)", err)
+	}
+
+	if ie.Type != gtpv1.Recovery || bytesConsumed != 2 || len(ie.Data) != 1 || ie.Data[0] != 5 {
+		t.Errorf("[TestDecodeIEShortTV] expected Recovery with value (5) using (2) octets, got type (%d) with data (%v) using (%d) octets", ie.Type, ie.Data, bytesConsumed)
+	}
+
+	if _, _, err := gtpv1.DecodeIE([]byte{0x0e}); err == nil {
+		t.Errorf("[TestDecodeIEShortTV] expected error on DecodeIE of Recovery with no data, got none")
+	}
+}
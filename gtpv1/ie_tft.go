@@ -0,0 +1,55 @@
+package gtpv1
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/tft"
+)
+
+// TypedTFT is a structured version of a Traffic Flow Template IE
+type TypedTFT struct {
+	TFT *tft.TFT
+}
+
+// ToIE creates an IE from the structured version of a TFT, and
+// panics if there is an error
+func (typedTFT *TypedTFT) ToIE() *IE {
+	ie, err := typedTFT.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing.  The TFT is validated (see tft.TFT.Validate).
+func (typedTFT *TypedTFT) ToIEErrorable() (*IE, error) {
+	if typedTFT.TFT == nil {
+		return nil, fmt.Errorf("invalid TFT: TFT is nil")
+	}
+
+	data, err := typedTFT.TFT.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TFT: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(TrafficFlowTemplate, data)
+}
+
+// makeTypedTFT does not validate the TFT, so that a receiver can decide
+// how to respond to a TFT that is structurally sound but breaks the
+// operation rules
+func makeTypedTFT(fromIE *IE) (*TypedTFT, error) {
+	if fromIE.Type != TrafficFlowTemplate {
+		return nil, fmt.Errorf("supplied IE is not of type TFT")
+	}
+
+	decoded, err := tft.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TFT encoding: %s", err)
+	}
+
+	return &TypedTFT{TFT: decoded}, nil
+}
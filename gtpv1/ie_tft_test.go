@@ -0,0 +1,43 @@
+package gtpv1_test
+
+import (
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+	"github.com/blorticus-go/gtp/tft"
+	"github.com/go-test/deep"
+)
+
+func TestTypedTFT(t *testing.T) {
+	typedTFT := &gtpv1.TypedTFT{
+		TFT: &tft.TFT{
+			Operation:     tft.OperationDeletePacketFilters,
+			PacketFilters: []*tft.PacketFilter{{Identifier: 3}},
+			Parameters:    []*tft.Parameter{},
+		},
+	}
+	expectedEncoding := []byte{137, 0x00, 0x02, 0xa1, 0x03}
+
+	ie, err := typedTFT.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedTFT] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(expectedEncoding, ie.Encode()); diff != nil {
+		t.Errorf("[TestTypedTFT] on Encode(): %s", diff)
+	}
+
+	decodedIE, _, err := gtpv1.DecodeIE(expectedEncoding)
+	if err != nil {
+		t.Fatalf("[TestTypedTFT] did not expect error on DecodeIE, but got error = (%s)", err.Error())
+	}
+
+	typed, err := decodedIE.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedTFT] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(typedTFT, typed.(*gtpv1.TypedTFT)); diff != nil {
+		t.Errorf("[TestTypedTFT] on TypedData: %s", diff)
+	}
+}
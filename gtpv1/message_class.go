@@ -0,0 +1,115 @@
+package gtpv1
+
+import (
+	"net"
+)
+
+// responseTypeForRequest maps each message type that expects an answer to the
+// type of the answer (TS 29.060 section 7).  A response that is itself
+// answered, like an SGSN Context Response, is both a request and a response.
+var responseTypeForRequest = map[MessageType]MessageType{
+	EchoRequest:                           EchoResponse,
+	NodeAliveRequest:                      NodeAliveResponse,
+	RedirectionRequest:                    RedirectionResponse,
+	CreatePDPContextRequest:               CreatePDPContextResponse,
+	UpdatePDPContextRequest:               UpdatePDPContextResponse,
+	DeletePDPContextRequest:               DeletePDPContextResponse,
+	InitiatePDPContextActivationRequest:   InitiatePDPContextActivationResponse,
+	PDUNotificationRequest:                PDUNotificationResponse,
+	PDUNotificationRejectRequest:          PDUNotificationRejectResponse,
+	SendRouteingInformationforGPRSRequest: SendRouteingInformationforGPRS,
+	FailureReportRequest:                  FailureReportResponse,
+	NoteMSGPRSPresentRequest:              NoteMSGPRSPresentResponse,
+	IdentificationRequest:                 IdentificationResponse,
+	SGSNContextRequest:                    SGSNContextResponse,
+	SGSNContextResponse:                   SGSNContextAcknowledge,
+	ForwardRelocationRequest:              ForwardRelocationResponse,
+	ForwardRelocationComplete:             ForwardRelocationCompleteAcknowledge,
+	RelocationCancelRequest:               RelocationCancelResponse,
+	ForwardSRNSContext:                    ForwardSRNSContextAcknowledge,
+	MBMSNotificationRequest:               MBMSNotificationResponse,
+	MBMSNotificationRejectRequest:         MBMSNotificationRejectResponse,
+	CreateMBMSContextRequest:              CreateMBMSContextResponse,
+	UpdateMBMSContextRequest:              UpdateMBMSContextResponse,
+	DeleteMBMSContextRequest:              DeleteMBMSContextResponse,
+	MBMSRegistrationRequest:               MBMSRegistrationResponse,
+	MBMSDeRegistrationRequest:             MBMSDeRegistrationResponse,
+	MBMSSessionStartRequest:               MBMSSessionStartResponse,
+	MBMSSessionStopRequest:                MBMSSessionStopResponse,
+	MBMSSessionUpdateRequest:              MBMSSessionUpdateResponse,
+	MSInfoChangeNotificationRequest:       MSInfoChangeNotificationResponse,
+	DataRecordTransferRequest:             DataRecordTransferResponse,
+}
+
+var requestTypeForResponse = func() map[MessageType]MessageType {
+	reverse := make(map[MessageType]MessageType)
+	for request, response := range responseTypeForRequest {
+		reverse[response] = request
+	}
+	return reverse
+}()
+
+// ResponseTypeFor returns the message type that answers a request (e.g.,
+// CreatePDPContextResponse for CreatePDPContextRequest).  An SGSN Context
+// Response is answered by an SGSN Context Acknowledge.  Returns false if
+// nothing answers messageType.
+func ResponseTypeFor(messageType MessageType) (MessageType, bool) {
+	responseType, isRequest := responseTypeForRequest[messageType]
+	return responseType, isRequest
+}
+
+// RequestTypeFor returns the message type that a response answers.  Returns
+// false if messageType is not a response.
+func RequestTypeFor(messageType MessageType) (MessageType, bool) {
+	requestType, isResponse := requestTypeForResponse[messageType]
+	return requestType, isResponse
+}
+
+// IsRequest returns true if the message type is a request (or other message)
+// that is answered by another message.  This includes a response that is
+// itself answered, like an SGSN Context Response.
+func IsRequest(messageType MessageType) bool {
+	_, isRequest := responseTypeForRequest[messageType]
+	return isRequest
+}
+
+// IsResponse returns true if the message type answers another message.  This
+// includes a response that is itself answered, like an SGSN Context Response.
+func IsResponse(messageType MessageType) bool {
+	_, isResponse := requestTypeForResponse[messageType]
+	return isResponse
+}
+
+// IsTriggered returns true if a message of the type is sent only as a result
+// of receiving another message.  GTPv1 has no triggered requests, so this is
+// the same as IsResponse.
+func IsTriggered(messageType MessageType) bool {
+	return IsResponse(messageType)
+}
+
+// CorrelationKey identifies a transaction with a peer.  All of the messages
+// in a transaction have the same CorrelationKey, so it can be used to match
+// a received response to the request that was sent, or to recognize a
+// retransmitted request.  Peer is the string form of the peer address.
+// InitiatedLocally is true for a transaction that starts with a request sent
+// to the peer, and false for one that starts with a request received from the
+// peer, because each side chooses sequence numbers for its own transactions.
+type CorrelationKey struct {
+	Peer             string
+	SequenceNumber   uint16
+	InitiatedLocally bool
+}
+
+// CorrelationKeyFor returns the CorrelationKey for a message that is sent to
+// (isOutgoing is true) or received from (isOutgoing is false) a peer.  A
+// response is sent by the side that did not start the transaction, except for
+// an SGSN Context Acknowledge, which answers the SGSN Context Response.
+func CorrelationKeyFor(peer net.Addr, pdu *PDU, isOutgoing bool) CorrelationKey {
+	sentByInitiator := !IsResponse(pdu.Type) || pdu.Type == SGSNContextAcknowledge
+
+	return CorrelationKey{
+		Peer:             peer.String(),
+		SequenceNumber:   pdu.SequenceNumber,
+		InitiatedLocally: sentByInitiator == isOutgoing,
+	}
+}
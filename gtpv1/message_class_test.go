@@ -0,0 +1,59 @@
+package gtpv1_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+)
+
+func TestMessageClass(t *testing.T) {
+	if responseType, isRequest := gtpv1.ResponseTypeFor(gtpv1.CreatePDPContextRequest); !isRequest || responseType != gtpv1.CreatePDPContextResponse {
+		t.Errorf("[TestMessageClass] expected Create PDP Context Response for Create PDP Context Request")
+	}
+
+	if requestType, isResponse := gtpv1.RequestTypeFor(gtpv1.PDUNotificationRejectResponse); !isResponse || requestType != gtpv1.PDUNotificationRejectRequest {
+		t.Errorf("[TestMessageClass] expected PDU Notification Reject Request for PDU Notification Reject Response")
+	}
+
+	if _, isRequest := gtpv1.ResponseTypeFor(gtpv1.ErrorIndication); isRequest {
+		t.Errorf("[TestMessageClass] expected no response type for Error Indication")
+	}
+
+	for _, testCase := range []struct {
+		messageType gtpv1.MessageType
+		isRequest   bool
+		isResponse  bool
+	}{
+		{gtpv1.EchoRequest, true, false},
+		{gtpv1.EchoResponse, false, true},
+		{gtpv1.SGSNContextResponse, true, true},
+		{gtpv1.SGSNContextAcknowledge, false, true},
+		{gtpv1.ErrorIndication, false, false},
+	} {
+		if gtpv1.IsRequest(testCase.messageType) != testCase.isRequest || gtpv1.IsResponse(testCase.messageType) != testCase.isResponse || gtpv1.IsTriggered(testCase.messageType) != testCase.isResponse {
+			t.Errorf("[TestMessageClass] classification of (%s) is not correct", gtpv1.NameOfMessageForType(testCase.messageType))
+		}
+	}
+
+	peer := &net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: 2123}
+
+	request := gtpv1.NewPDU(gtpv1.CreatePDPContextRequest, 0).UseSequenceNumber(0x1234)
+	response := gtpv1.NewPDU(gtpv1.CreatePDPContextResponse, 0x01010101).UseSequenceNumber(0x1234)
+	peerRequest := gtpv1.NewPDU(gtpv1.DeletePDPContextRequest, 0x01010101).UseSequenceNumber(0x1234)
+
+	sentKey := gtpv1.CorrelationKeyFor(peer, request, true)
+	if receivedKey := gtpv1.CorrelationKeyFor(peer, response, false); receivedKey != sentKey {
+		t.Errorf("[TestMessageClass] expected received response to have key of sent request")
+	}
+
+	if peerKey := gtpv1.CorrelationKeyFor(peer, peerRequest, false); peerKey == sentKey {
+		t.Errorf("[TestMessageClass] expected received request with same sequence number to have a different key")
+	}
+
+	contextResponse := gtpv1.NewPDU(gtpv1.SGSNContextResponse, 0).UseSequenceNumber(0x0001)
+	contextAcknowledge := gtpv1.NewPDU(gtpv1.SGSNContextAcknowledge, 0).UseSequenceNumber(0x0001)
+	if gtpv1.CorrelationKeyFor(peer, contextResponse, false) != gtpv1.CorrelationKeyFor(peer, contextAcknowledge, true) {
+		t.Errorf("[TestMessageClass] expected SGSN Context Acknowledge to have key of SGSN Context Response")
+	}
+}
@@ -0,0 +1,383 @@
+package gtpv1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Default values for the PathManagerConfig fields.  TS 29.060 section 7.2.1
+// requires that Echo Requests be sent to a peer no more often than every 60
+// seconds, and leaves T3-RESPONSE and N3-REQUESTS to the operator.
+const (
+	DefaultEchoInterval = 60 * time.Second
+	DefaultT3Response   = 3 * time.Second
+	DefaultN3Requests   = 3
+)
+
+// PathEventType identifies the kind of a PathEvent
+type PathEventType uint8
+
+// Path event types
+const (
+	// PathUp is raised when a peer answers an Echo Request (or sends one) for
+	// the first time, or after the path was down
+	PathUp PathEventType = iota + 1
+	// PathDown is raised when an Echo Request is not answered after
+	// N3Requests retransmissions
+	PathDown
+	// PeerRestarted is raised when the restart counter for a peer changes
+	PeerRestarted
+)
+
+var pathEventTypeNames = []string{"", "Path Up", "Path Down", "Peer Restarted"}
+
+// String returns the name of the event type
+func (eventType PathEventType) String() string {
+	if eventType > 0 && int(eventType) < len(pathEventTypeNames) {
+		return pathEventTypeNames[eventType]
+	}
+
+	return fmt.Sprintf("PathEventType(%d)", uint8(eventType))
+}
+
+// PathEvent is a change in the state of the path to a peer.  For a
+// PeerRestarted event, RestartCounter is the new restart counter value for the
+// peer, and PreviousRestartCounter is the value before the restart.
+type PathEvent struct {
+	Type                   PathEventType
+	Peer                   net.Addr
+	RestartCounter         uint8
+	PreviousRestartCounter uint8
+}
+
+// PathManagerConfig provides the settings for a PathManager.  A zero value
+// for a duration or count means that the default is used.
+type PathManagerConfig struct {
+	// EchoInterval is the time between Echo Requests sent to a peer.  The
+	// default is DefaultEchoInterval.
+	EchoInterval time.Duration
+
+	// T3Response is how long to wait for an Echo Response before
+	// retransmitting the Echo Request.  The default is DefaultT3Response.
+	T3Response time.Duration
+
+	// N3Requests is the maximum number of times that an Echo Request is
+	// retransmitted before the path is declared down.  The default is
+	// DefaultN3Requests.  If it is less than zero, an Echo Request is never
+	// retransmitted.
+	N3Requests int
+
+	// RestartCounter is the local restart counter, which is sent in the
+	// Recovery IE of Echo Responses.  For GTP-U, it should be 0.
+	RestartCounter uint8
+
+	// SequenceNumbers allocates the sequence numbers for Echo Requests.  It
+	// should be shared with any other sender of signalling messages over the
+	// connection, so that sequence numbers are not reused.  If it is nil,
+	// the PathManager creates its own.
+	SequenceNumbers *SequenceNumberManager
+
+	// OnEvent is called for each PathEvent.  It is called from a goroutine of
+	// the PathManager, so it should not block.  It may be nil.
+	OnEvent func(event *PathEvent)
+}
+
+// PathManager maintains the path to each GTPv1 peer using Echo Requests (TS
+// 29.060 section 7.2).  An Echo Request is sent to each peer every
+// EchoInterval over a net.PacketConn, and is retransmitted every T3Response,
+// up to N3Requests times, until it is answered.  If it is not answered, the
+// path is declared down.  The restart counter in the Recovery IE of each Echo
+// Response is tracked, and a change raises a PeerRestarted event.  The
+// PathManager only writes to the connection.  The reader of the connection
+// should pass received Echo Requests to HandleEchoRequest, and received Echo
+// Responses to HandleEchoResponse.
+type PathManager struct {
+	conn   net.PacketConn
+	config PathManagerConfig
+
+	lock    sync.Mutex
+	peers   map[string]*pathPeer
+	pending map[pendingEcho]chan *PDU
+	closed  bool
+}
+
+type pathState uint8
+
+const (
+	pathStateUnknown pathState = iota
+	pathStateUp
+	pathStateDown
+)
+
+type pathPeer struct {
+	address               net.Addr
+	state                 pathState
+	restartCounter        uint8
+	restartCounterIsKnown bool
+	stopMonitoring        context.CancelFunc
+}
+
+// pendingEcho identifies an Echo Request that has been sent to a peer and has
+// not been answered
+type pendingEcho struct {
+	peer           string
+	sequenceNumber uint16
+}
+
+// NewPathManager creates a PathManager that sends over conn.  It has no peers
+// until AddPeer is called.  The PathManager does not close conn.
+func NewPathManager(conn net.PacketConn, config PathManagerConfig) *PathManager {
+	if config.EchoInterval <= 0 {
+		config.EchoInterval = DefaultEchoInterval
+	}
+
+	if config.T3Response <= 0 {
+		config.T3Response = DefaultT3Response
+	}
+
+	if config.N3Requests == 0 {
+		config.N3Requests = DefaultN3Requests
+	} else if config.N3Requests < 0 {
+		config.N3Requests = 0
+	}
+
+	if config.SequenceNumbers == nil {
+		config.SequenceNumbers = NewSequenceNumberManager()
+	}
+
+	return &PathManager{
+		conn:    conn,
+		config:  config,
+		peers:   make(map[string]*pathPeer),
+		pending: make(map[pendingEcho]chan *PDU),
+	}
+}
+
+// AddPeer starts sending Echo Requests to a peer.  The first is sent
+// immediately.  Does nothing if the peer is already monitored.
+func (manager *PathManager) AddPeer(peer net.Addr) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if manager.closed {
+		return
+	}
+
+	if _, isKnown := manager.peers[peer.String()]; isKnown {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pathToPeer := &pathPeer{address: peer, stopMonitoring: cancel}
+	manager.peers[peer.String()] = pathToPeer
+
+	go manager.monitor(ctx, pathToPeer)
+}
+
+// RemovePeer stops sending Echo Requests to a peer, and forgets its state
+func (manager *PathManager) RemovePeer(peer net.Addr) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if pathToPeer, isKnown := manager.peers[peer.String()]; isKnown {
+		pathToPeer.stopMonitoring()
+		delete(manager.peers, peer.String())
+	}
+}
+
+// Close stops sending Echo Requests to all peers.  It does not close the
+// connection.
+func (manager *PathManager) Close() {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	manager.closed = true
+
+	for peerKey, pathToPeer := range manager.peers {
+		pathToPeer.stopMonitoring()
+		delete(manager.peers, peerKey)
+	}
+}
+
+// IsPathUp returns true if the last Echo Request sent to the peer was
+// answered, or if an Echo Request has been received from the peer since that
+// Echo Request was sent.  Returns false for a peer that was not added with
+// AddPeer.
+func (manager *PathManager) IsPathUp(peer net.Addr) bool {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	pathToPeer, isKnown := manager.peers[peer.String()]
+	return isKnown && pathToPeer.state == pathStateUp
+}
+
+// HandleEchoRequest answers an Echo Request received from a peer with an Echo
+// Response carrying the local restart counter.  If the peer was added with
+// AddPeer, the request is also taken as evidence that the path is up.  Returns
+// an error if the message is not an Echo Request, or if the response cannot be
+// sent.
+func (manager *PathManager) HandleEchoRequest(request *PDU, peer net.Addr) error {
+	response, err := NewEchoResponse(request, manager.config.RestartCounter)
+	if err != nil {
+		return err
+	}
+
+	if _, err := manager.conn.WriteTo(response.Encode(), peer); err != nil {
+		return err
+	}
+
+	manager.setPathState(peer, pathStateUp)
+
+	return nil
+}
+
+// HandleEchoResponse delivers an Echo Response received from a peer to the
+// Echo Request that it answers.  Returns an error if the message is not an
+// Echo Response, or if it does not answer an Echo Request that is waiting for
+// a response (for example, because it answers a retransmission of a request
+// that was already answered).
+func (manager *PathManager) HandleEchoResponse(response *PDU, peer net.Addr) error {
+	if response.Type != EchoResponse {
+		return fmt.Errorf("message type (%s) is not Echo Response", NameOfMessageForType(response.Type))
+	}
+
+	key := pendingEcho{peer: peer.String(), sequenceNumber: response.SequenceNumber}
+
+	manager.lock.Lock()
+	answer, isPending := manager.pending[key]
+	delete(manager.pending, key)
+	manager.lock.Unlock()
+
+	if !isPending {
+		return fmt.Errorf("Echo Response with sequence number (%d) from peer (%s) does not answer a pending Echo Request", response.SequenceNumber, peer)
+	}
+
+	answer <- response
+
+	return nil
+}
+
+// UpdateRestartCounter records the restart counter received from a peer, and
+// raises a PeerRestarted event if it differs from the last value recorded.
+// It is called for the Recovery IE in each Echo Response, and may also be
+// called for a Recovery IE in any other message from the peer.  Does nothing
+// for a peer that was not added with AddPeer.
+func (manager *PathManager) UpdateRestartCounter(peer net.Addr, restartCounter uint8) {
+	manager.lock.Lock()
+
+	pathToPeer, isKnown := manager.peers[peer.String()]
+	if !isKnown {
+		manager.lock.Unlock()
+		return
+	}
+
+	previousRestartCounter := pathToPeer.restartCounter
+	peerHasRestarted := pathToPeer.restartCounterIsKnown && previousRestartCounter != restartCounter
+
+	pathToPeer.restartCounter = restartCounter
+	pathToPeer.restartCounterIsKnown = true
+
+	manager.lock.Unlock()
+
+	if peerHasRestarted {
+		manager.raise(&PathEvent{
+			Type:                   PeerRestarted,
+			Peer:                   peer,
+			RestartCounter:         restartCounter,
+			PreviousRestartCounter: previousRestartCounter,
+		})
+	}
+}
+
+func (manager *PathManager) monitor(ctx context.Context, pathToPeer *pathPeer) {
+	ticker := time.NewTicker(manager.config.EchoInterval)
+	defer ticker.Stop()
+
+	for {
+		manager.sendEcho(ctx, pathToPeer.address)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (manager *PathManager) sendEcho(ctx context.Context, peer net.Addr) {
+	request := NewEchoRequest(manager.config.SequenceNumbers.Next(peer))
+	key := pendingEcho{peer: peer.String(), sequenceNumber: request.SequenceNumber}
+	answer := make(chan *PDU, 1)
+
+	manager.lock.Lock()
+	manager.pending[key] = answer
+	manager.lock.Unlock()
+
+	defer func() {
+		manager.lock.Lock()
+		delete(manager.pending, key)
+		manager.lock.Unlock()
+	}()
+
+	stream := request.Encode()
+
+	timer := time.NewTimer(manager.config.T3Response)
+	defer timer.Stop()
+
+	for retransmissions := 0; ; retransmissions++ {
+		manager.conn.WriteTo(stream, peer)
+
+		select {
+		case response := <-answer:
+			manager.setPathState(peer, pathStateUp)
+
+			if restartCounter, isPresent := RestartCounterIn(response); isPresent {
+				manager.UpdateRestartCounter(peer, restartCounter)
+			}
+			return
+
+		case <-ctx.Done():
+			return
+
+		case <-timer.C:
+			if retransmissions >= manager.config.N3Requests {
+				manager.setPathState(peer, pathStateDown)
+				return
+			}
+			timer.Reset(manager.config.T3Response)
+		}
+	}
+}
+
+func (manager *PathManager) setPathState(peer net.Addr, state pathState) {
+	manager.lock.Lock()
+
+	pathToPeer, isKnown := manager.peers[peer.String()]
+	if !isKnown {
+		manager.lock.Unlock()
+		return
+	}
+
+	stateHasChanged := pathToPeer.state != state
+	pathToPeer.state = state
+
+	manager.lock.Unlock()
+
+	if stateHasChanged {
+		eventType := PathUp
+		if state == pathStateDown {
+			eventType = PathDown
+		}
+
+		manager.raise(&PathEvent{Type: eventType, Peer: peer})
+	}
+}
+
+func (manager *PathManager) raise(event *PathEvent) {
+	if manager.config.OnEvent != nil {
+		manager.config.OnEvent(event)
+	}
+}
@@ -0,0 +1,215 @@
+package gtpv1_test
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+)
+
+// readEchoMessages passes each Echo Request and Echo Response received on conn
+// to manager, until conn is closed
+func readEchoMessages(conn net.PacketConn, manager *gtpv1.PathManager) {
+	buffer := make([]byte, 65536)
+
+	for {
+		length, from, err := conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		pdu, err := gtpv1.DecodePDU(buffer[:length])
+		if err != nil {
+			continue
+		}
+
+		switch pdu.Type {
+		case gtpv1.EchoRequest:
+			manager.HandleEchoRequest(pdu, from)
+		case gtpv1.EchoResponse:
+			manager.HandleEchoResponse(pdu, from)
+		}
+	}
+}
+
+// echoPeer answers the Echo Requests received on conn with its restartCounter,
+// until it is told to stop answering
+type echoPeer struct {
+	conn net.PacketConn
+
+	lock           sync.Mutex
+	restartCounter uint8
+	isAnswering    bool
+	requestsSeen   int
+}
+
+func (peer *echoPeer) answerEchoRequests() {
+	buffer := make([]byte, 65536)
+
+	for {
+		length, from, err := peer.conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		request, err := gtpv1.DecodePDU(buffer[:length])
+		if err != nil || request.Type != gtpv1.EchoRequest {
+			continue
+		}
+
+		peer.lock.Lock()
+		peer.requestsSeen++
+		isAnswering, restartCounter := peer.isAnswering, peer.restartCounter
+		peer.lock.Unlock()
+
+		if isAnswering {
+			response, _ := gtpv1.NewEchoResponse(request, restartCounter)
+			peer.conn.WriteTo(response.Encode(), from)
+		}
+	}
+}
+
+func expectV1PathEvent(t *testing.T, events <-chan *gtpv1.PathEvent, eventType gtpv1.PathEventType) *gtpv1.PathEvent {
+	select {
+	case event := <-events:
+		if event.Type != eventType {
+			t.Fatalf("[TestPathManager] expected event (%s), got (%s)", eventType, event.Type)
+		}
+		return event
+
+	case <-time.After(2 * time.Second):
+		t.Fatalf("[TestPathManager] expected event (%s), got none", eventType)
+		return nil
+	}
+}
+
+func TestPathManager(t *testing.T) {
+	localConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open local socket: %s", err)
+	}
+	defer localConn.Close()
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open peer socket: %s", err)
+	}
+	defer peerConn.Close()
+
+	events := make(chan *gtpv1.PathEvent, 10)
+	manager := gtpv1.NewPathManager(localConn, gtpv1.PathManagerConfig{
+		EchoInterval:   50 * time.Millisecond,
+		T3Response:     20 * time.Millisecond,
+		N3Requests:     2,
+		RestartCounter: 7,
+		OnEvent:        func(event *gtpv1.PathEvent) { events <- event },
+	})
+	defer manager.Close()
+
+	go readEchoMessages(localConn, manager)
+
+	peer := &echoPeer{conn: peerConn, restartCounter: 1, isAnswering: true}
+	go peer.answerEchoRequests()
+
+	manager.AddPeer(peerConn.LocalAddr())
+
+	event := expectV1PathEvent(t, events, gtpv1.PathUp)
+	if event.Peer.String() != peerConn.LocalAddr().String() {
+		t.Errorf("[TestPathManager] expected event for peer (%s), got (%s)", peerConn.LocalAddr(), event.Peer)
+	}
+
+	if !manager.IsPathUp(peerConn.LocalAddr()) {
+		t.Errorf("[TestPathManager] expected path to be up")
+	}
+
+	// the peer restarts with a new restart counter
+	peer.lock.Lock()
+	peer.restartCounter = 2
+	peer.lock.Unlock()
+
+	event = expectV1PathEvent(t, events, gtpv1.PeerRestarted)
+	if event.RestartCounter != 2 || event.PreviousRestartCounter != 1 {
+		t.Errorf("[TestPathManager] expected restart counter to change from (1) to (2), got (%d) to (%d)", event.PreviousRestartCounter, event.RestartCounter)
+	}
+
+	// the peer stops answering, so the path is down after the Echo Request
+	// and N3Requests retransmissions
+	peer.lock.Lock()
+	peer.isAnswering = false
+	peer.requestsSeen = 0
+	peer.lock.Unlock()
+
+	expectV1PathEvent(t, events, gtpv1.PathDown)
+	manager.RemovePeer(peerConn.LocalAddr())
+
+	peer.lock.Lock()
+	if peer.requestsSeen < 3 {
+		t.Errorf("[TestPathManager] expected at least (3) copies of the Echo Request before the path is down, got (%d)", peer.requestsSeen)
+	}
+	peer.lock.Unlock()
+
+	if manager.IsPathUp(peerConn.LocalAddr()) {
+		t.Errorf("[TestPathManager] expected path to be down")
+	}
+
+	if err := manager.HandleEchoResponse(gtpv1.NewEchoRequest(1), peerConn.LocalAddr()); err == nil {
+		t.Errorf("[TestPathManager] expected error from HandleEchoResponse for Echo Request, got none")
+	}
+
+	if err := manager.HandleEchoRequest(gtpv1.NewPDU(gtpv1.CreatePDPContextRequest, 0), peerConn.LocalAddr()); err == nil {
+		t.Errorf("[TestPathManager] expected error from HandleEchoRequest for Create PDP Context Request, got none")
+	}
+}
+
+func TestPathManagerAnswersEchoRequest(t *testing.T) {
+	localConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open local socket: %s", err)
+	}
+	defer localConn.Close()
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open peer socket: %s", err)
+	}
+	defer peerConn.Close()
+
+	events := make(chan *gtpv1.PathEvent, 10)
+	manager := gtpv1.NewPathManager(localConn, gtpv1.PathManagerConfig{
+		RestartCounter: 7,
+		OnEvent:        func(event *gtpv1.PathEvent) { events <- event },
+	})
+	defer manager.Close()
+
+	go readEchoMessages(localConn, manager)
+
+	peerConn.WriteTo(gtpv1.NewEchoRequest(0x0304).Encode(), localConn.LocalAddr())
+
+	buffer := make([]byte, 65536)
+	peerConn.SetReadDeadline(time.Now().Add(time.Second))
+	length, _, err := peerConn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatalf("[TestPathManagerAnswersEchoRequest] expected Echo Response, got error (%s)", err)
+	}
+
+	response, err := gtpv1.DecodePDU(buffer[:length])
+	if err != nil {
+		t.Fatalf("[TestPathManagerAnswersEchoRequest] expected no error on DecodePDU, got (%s)", err)
+	}
+
+	if restartCounter, isPresent := gtpv1.RestartCounterIn(response); response.Type != gtpv1.EchoResponse || response.SequenceNumber != 0x0304 || !isPresent || restartCounter != 7 {
+		t.Errorf("[TestPathManagerAnswersEchoRequest] expected Echo Response with sequence number (0x0304) and restart counter (7)")
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("[TestPathManagerAnswersEchoRequest] expected no event for a peer not added with AddPeer, got (%s)", event.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if manager.IsPathUp(peerConn.LocalAddr()) {
+		t.Errorf("[TestPathManagerAnswersEchoRequest] expected path to peer not added with AddPeer to be reported down")
+	}
+}
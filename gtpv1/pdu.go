@@ -28,7 +28,7 @@ const (
 	ErrorIndication                       MessageType = 26
 	PDUNotificationRequest                MessageType = 27
 	PDUNotificationResponse               MessageType = 28
-	PDUNotificationRejectRequest          MessageType = 30
+	PDUNotificationRejectRequest          MessageType = 29
 	PDUNotificationRejectResponse         MessageType = 30
 	SupportedExtensionHeadersNotification MessageType = 31
 	SendRouteingInformationforGPRSRequest MessageType = 32
@@ -85,7 +85,7 @@ var pduTypeIsDefined = []bool{
 	false, false, false, false, false, // 15
 	true, true, true, true, true, // 20
 	true, true, true, false, false, // 25
-	true, true, true, false, true, // 30
+	true, true, true, true, true, // 30
 	true, true, true, true, true, // 35
 	true, true, false, false, false, // 40
 	false, false, false, false, false, // 45
@@ -140,7 +140,7 @@ var messageNames = []string{
 	"Reserved", "Reserved", "Reserved", "Reserved", "Reserved", // 15
 	"Create PDP Context Request", "Create PDP Context Response", "Update PDP Context Request", "Update PDP Context Response", "Delete PDP Context Request", // 20
 	"Delete PDP Context Response", "Initiate PDP Context Activation Request", "Initiate PDP Context Activation Response", "Reserved", "Reserved", // 25
-	"Error Indication", "PDU Notification Request", "PDU Notification Response", "Reserved", "PDU Notification Reject Response", // 30
+	"Error Indication", "PDU Notification Request", "PDU Notification Response", "PDU Notification Reject Request", "PDU Notification Reject Response", // 30
 	"Supported Extension Headers Notification", "Send Routeing Information for GPRS Request", "Send Routeing Information for GPRS", "Failure Report Request", "Failure Report Response", // 35
 	"Note MS GPRS Present Request", "Note MS GPRS Present Response", "Reserved", "Reserved", "Reserved", // 40
 	"Reserved", "Reserved", "Reserved", "Reserved", "Reserved", // 45
@@ -475,63 +475,67 @@ func DecodePDU(datagram []byte) (pdu *PDU, err error) {
 		return nil, fmt.Errorf("length field value (%d) does not match stream length (%d) less the fixed header length (8)", pdu.Length, len(datagram))
 	}
 
-	if datagram[0]&0x02 != 0 { // Sequence Number flag
-		if bytesRemainingToProcess < 2 {
-			return nil, fmt.Errorf("insufficient bytes in datagram to include a sequence number")
+	// if any of the E, S and PN flags is set, the header has four optional
+	// octets: the sequence number, the N-PDU number and the type of the first
+	// extension header.  The value of each is only meaningful if its flag is
+	// set (TS 29.060 section 6).
+	if datagram[0]&0x07 != 0 {
+		if bytesRemainingToProcess < 4 {
+			return nil, fmt.Errorf("insufficient bytes in datagram to include the optional header fields")
 		}
 
-		pdu.SequenceNumber = binary.BigEndian.Uint16(datagram[8:10])
-		pdu.IncludeSequenceNumber = true
-
-		bytesRemainingToProcess -= 2
-	}
+		if datagram[0]&0x02 != 0 { // Sequence Number flag
+			pdu.SequenceNumber = binary.BigEndian.Uint16(datagram[8:10])
+			pdu.IncludeSequenceNumber = true
+		}
 
-	if datagram[0]&0x01 != 0 { // NPDU Number flag
-		if bytesRemainingToProcess < 1 {
-			return nil, fmt.Errorf("insufficient bytes in datagram to include a N-PDU number")
+		if datagram[0]&0x01 != 0 { // NPDU Number flag
+			pdu.NPDUNumber = datagram[10]
+			pdu.IncludeNPDUNumber = true
 		}
 
-		pdu.NPDUNumber = datagram[10]
-		pdu.IncludeNPDUNumber = true
+		nextHeaderType := datagram[11]
+		bytesRemainingToProcess -= 4
 
-		bytesRemainingToProcess -= 1
-	}
+		if datagram[0]&0x04 != 0 { // Extension Header flag
+			extensionHeaders := make([]*ExtensionHeader, 0, 1)
 
-	if datagram[0]&0x04 != 0 { // Extension Header flag
-		if bytesRemainingToProcess < 1 {
-			return nil, fmt.Errorf("expected extension header but ran out of bytes in datagram")
-		}
+			// each extension header is its length in 4-octet units, its
+			// contents, and the type of the next extension header
+			for nextHeaderType != byte(NoMoreHeaders) {
+				if !extensionHeaderTypeIsDefined[nextHeaderType] {
+					return nil, fmt.Errorf("extension header of type (0x%02x) is not defined", nextHeaderType)
+				}
 
-		extensionHeaders := make([]*ExtensionHeader, 0, 1)
+				if bytesRemainingToProcess < 1 {
+					return nil, fmt.Errorf("expected extension header but ran out of bytes in datagram")
+				}
 
-		for i := len(datagram) - bytesRemainingToProcess; datagram[i] != byte(NoMoreHeaders); {
-			if !extensionHeaderTypeIsDefined[uint8(datagram[i])] {
-				return nil, fmt.Errorf("extension header of type (0x%02x) is not defined", datagram[i])
-			}
+				i := len(datagram) - bytesRemainingToProcess
+				headerLengthInBytes := int(datagram[i]) * 4
 
-			if bytesRemainingToProcess < 2 {
-				return nil, fmt.Errorf("expected extension header but ran out of bytes in datagram")
-			}
+				if headerLengthInBytes == 0 {
+					return nil, fmt.Errorf("extension header of type (0x%02x) has length 0", nextHeaderType)
+				}
 
-			nextHeaderLengthInBytes := uint16(datagram[i+1]) * 4
+				if bytesRemainingToProcess < headerLengthInBytes {
+					return nil, fmt.Errorf("expected extension header but ran out of bytes in datagram")
+				}
 
-			if bytesRemainingToProcess < int(nextHeaderLengthInBytes) {
-				return nil, fmt.Errorf("expected extension header but ran out of bytes in datagram")
-			}
+				contents := make([]byte, headerLengthInBytes-2)
+				copy(contents, datagram[i+1:i+headerLengthInBytes-1])
 
-			contents := make([]byte, int(nextHeaderLengthInBytes))
-			copy(contents, datagram[i+2:i+(int(nextHeaderLengthInBytes)-2)])
+				extensionHeaders = append(extensionHeaders, &ExtensionHeader{
+					Type:     ExtensionHeaderType(nextHeaderType),
+					Contents: contents,
+				})
 
-			extensionHeaders = append(extensionHeaders, &ExtensionHeader{
-				Type:     ExtensionHeaderType(datagram[i]),
-				Contents: contents,
-			})
+				nextHeaderType = datagram[i+headerLengthInBytes-1]
+				bytesRemainingToProcess -= headerLengthInBytes
+			}
 
-			i += int(nextHeaderLengthInBytes)
-			bytesRemainingToProcess -= int(nextHeaderLengthInBytes)
+			pdu.ExtensionHeaders = extensionHeaders
 		}
-
-		pdu.ExtensionHeaders = extensionHeaders
 	}
 
 	if pdu.Type != GPDU {
@@ -116,6 +116,97 @@ func TestPDUDecodeValid(t *testing.T) {
 	}
 }
 
+func TestPDUDecodeOptionalHeaderFields(t *testing.T) {
+	tpdu := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	for testIndex, testCase := range []struct {
+		encodedBytes          []byte
+		sequenceNumber        *uint16
+		npduNumber            *uint8
+		extensionHeaders      []*gtpv1.ExtensionHeader
+		informationElementLen int
+	}{
+		{ // S: the N-PDU number and next extension header type octets are padding
+			encodedBytes:          []byte{0x32, 0x02, 0x00, 0x06, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x00, 0x00, 0x0e, 0x05},
+			sequenceNumber:        uint16Pointer(0x0102),
+			informationElementLen: 1,
+		},
+		{ // PN: the sequence number octets are padding
+			encodedBytes: append([]byte{0x31, 0xff, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x2a, 0x00}, tpdu...),
+			npduNumber:   uint8Pointer(0x2a),
+		},
+		{ // S and PN
+			encodedBytes:   append([]byte{0x33, 0xff, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x12, 0x34, 0x2a, 0x00}, tpdu...),
+			sequenceNumber: uint16Pointer(0x1234),
+			npduNumber:     uint8Pointer(0x2a),
+		},
+		{ // E with one PDCP PDU Number extension header
+			encodedBytes:     append([]byte{0x34, 0xff, 0x00, 0x0c, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xc0, 0x01, 0x56, 0x78, 0x00}, tpdu...),
+			extensionHeaders: []*gtpv1.ExtensionHeader{{Type: gtpv1.PDCPPDUNumber, Contents: []byte{0x56, 0x78}}},
+		},
+		{ // S, PN and E with two extension headers
+			encodedBytes: append([]byte{0x37, 0xff, 0x00, 0x10, 0x00, 0x00, 0x00, 0x01, 0x00, 0x07, 0x09, 0xc0,
+				0x01, 0x00, 0x01, 0x01,
+				0x01, 0xff, 0xff, 0x00}, tpdu...),
+			sequenceNumber: uint16Pointer(0x0007),
+			npduNumber:     uint8Pointer(0x09),
+			extensionHeaders: []*gtpv1.ExtensionHeader{
+				{Type: gtpv1.PDCPPDUNumber, Contents: []byte{0x00, 0x01}},
+				{Type: gtpv1.MBMSSupportIndication, Contents: []byte{0xff, 0xff}},
+			},
+		},
+	} {
+		pdu, err := gtpv1.DecodePDU(testCase.encodedBytes)
+		if err != nil {
+			t.Errorf("[TestPDUDecodeOptionalHeaderFields] on test number [%d] expected no error, got (%s)", testIndex+1, err)
+			continue
+		}
+
+		if pdu.IncludeSequenceNumber != (testCase.sequenceNumber != nil) || (testCase.sequenceNumber != nil && pdu.SequenceNumber != *testCase.sequenceNumber) {
+			t.Errorf("[TestPDUDecodeOptionalHeaderFields] on test number [%d] sequence number (%t, 0x%04x) is not correct", testIndex+1, pdu.IncludeSequenceNumber, pdu.SequenceNumber)
+		}
+
+		if pdu.IncludeNPDUNumber != (testCase.npduNumber != nil) || (testCase.npduNumber != nil && pdu.NPDUNumber != *testCase.npduNumber) {
+			t.Errorf("[TestPDUDecodeOptionalHeaderFields] on test number [%d] N-PDU number (%t, 0x%02x) is not correct", testIndex+1, pdu.IncludeNPDUNumber, pdu.NPDUNumber)
+		}
+
+		if diff := deep.Equal(testCase.extensionHeaders, pdu.ExtensionHeaders); diff != nil {
+			t.Errorf("[TestPDUDecodeOptionalHeaderFields] on test number [%d] extension headers: %s", testIndex+1, diff)
+		}
+
+		if pdu.Type == gtpv1.GPDU {
+			if diff := deep.Equal(tpdu, pdu.TPDU); diff != nil {
+				t.Errorf("[TestPDUDecodeOptionalHeaderFields] on test number [%d] T-PDU: %s", testIndex+1, diff)
+			}
+		} else if len(pdu.InformationElements) != testCase.informationElementLen {
+			t.Errorf("[TestPDUDecodeOptionalHeaderFields] on test number [%d] expected (%d) IEs, got (%d)", testIndex+1, testCase.informationElementLen, len(pdu.InformationElements))
+		}
+	}
+
+	for testIndex, encodedBytes := range [][]byte{
+		// S flag, but only two octets after the mandatory header
+		{0x32, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02},
+		// extension header with length 0
+		{0x34, 0xff, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xc0, 0x00, 0x00, 0x00, 0x00},
+		// extension header longer than the datagram
+		{0x34, 0xff, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0xc0, 0x02, 0x00, 0x00, 0x00},
+		// undefined extension header type
+		{0x34, 0xff, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x7f, 0x01, 0x00, 0x00, 0x00},
+	} {
+		if _, err := gtpv1.DecodePDU(encodedBytes); err == nil {
+			t.Errorf("[TestPDUDecodeOptionalHeaderFields] on error test number [%d] expected error, got none", testIndex+1)
+		}
+	}
+}
+
+func uint16Pointer(value uint16) *uint16 {
+	return &value
+}
+
+func uint8Pointer(value uint8) *uint8 {
+	return &value
+}
+
 func TestGPDUEncode(t *testing.T) {
 	sequenceNumbers := []uint16{0x28db}
 
@@ -0,0 +1,63 @@
+package gtpv1
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+)
+
+// SequenceNumberManager allocates sequence numbers for signalling messages
+// sent to peers.  Each peer has its own 16-bit sequence number space, and
+// numbers are allocated from it in a cyclic manner (TS 29.060 section 7.6).
+// The first value for a peer is random, so that the numbers used after a
+// restart are unlikely to match those used before it.  A
+// SequenceNumberManager is safe for concurrent use.
+type SequenceNumberManager struct {
+	lock sync.Mutex
+	next map[string]uint16
+}
+
+// NewSequenceNumberManager creates a SequenceNumberManager with no peers
+func NewSequenceNumberManager() *SequenceNumberManager {
+	return &SequenceNumberManager{
+		next: make(map[string]uint16),
+	}
+}
+
+// Next allocates the sequence number for a request sent to peer
+func (manager *SequenceNumberManager) Next(peer net.Addr) uint16 {
+	peerKey := peer.String()
+
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	next, peerIsKnown := manager.next[peerKey]
+	if !peerIsKnown {
+		next = uint16(rand.Intn(0x10000))
+	}
+	manager.next[peerKey] = next + 1
+
+	return next
+}
+
+// SequenceNumberFor returns the sequence number for a message of messageType
+// sent to peer.  If triggeringMessage is nil, the sequence number is allocated
+// as with Next.  Otherwise, the message is the response to triggeringMessage,
+// and the sequence number is that of triggeringMessage.  Returns an error if
+// messageType is not the response to triggeringMessage, or if
+// triggeringMessage is nil and messageType is a response.
+func (manager *SequenceNumberManager) SequenceNumberFor(peer net.Addr, messageType MessageType, triggeringMessage *PDU) (uint16, error) {
+	if triggeringMessage == nil {
+		if IsResponse(messageType) {
+			return 0, fmt.Errorf("message type (%s) is only sent in answer to another message", NameOfMessageForType(messageType))
+		}
+		return manager.Next(peer), nil
+	}
+
+	if responseType, isRequest := ResponseTypeFor(triggeringMessage.Type); !isRequest || responseType != messageType {
+		return 0, fmt.Errorf("message type (%s) cannot be sent in answer to message type (%s) with sequence number (%d)", NameOfMessageForType(messageType), NameOfMessageForType(triggeringMessage.Type), triggeringMessage.SequenceNumber)
+	}
+
+	return triggeringMessage.SequenceNumber, nil
+}
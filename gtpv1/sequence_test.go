@@ -0,0 +1,64 @@
+package gtpv1_test
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/blorticus-go/gtp/gtpv1"
+)
+
+func TestSequenceNumberManager(t *testing.T) {
+	manager := gtpv1.NewSequenceNumberManager()
+
+	peerA := &net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: 2123}
+	peerB := &net.UDPAddr{IP: net.IP{10, 0, 0, 2}, Port: 2123}
+
+	first := manager.Next(peerA)
+	for i := 1; i <= 0x10001; i++ {
+		if next := manager.Next(peerA); next != first+uint16(i) {
+			t.Fatalf("[TestSequenceNumberManager] on allocation [%d] expected (%d), got (%d)", i, first+uint16(i), next)
+		}
+	}
+
+	firstForB := manager.Next(peerB)
+	if next := manager.Next(peerB); next != firstForB+1 {
+		t.Errorf("[TestSequenceNumberManager] expected peer B to have its own sequence number space")
+	}
+
+	request := gtpv1.NewPDU(gtpv1.CreatePDPContextRequest, 0).UseSequenceNumber(0x1234)
+
+	if sequenceNumber, err := manager.SequenceNumberFor(peerA, gtpv1.CreatePDPContextResponse, request); err != nil || sequenceNumber != 0x1234 {
+		t.Errorf("[TestSequenceNumberManager] expected response to have sequence number of request, got (%d, %v)", sequenceNumber, err)
+	}
+
+	if _, err := manager.SequenceNumberFor(peerA, gtpv1.DeletePDPContextResponse, request); err == nil {
+		t.Errorf("[TestSequenceNumberManager] expected error for response that does not answer request, got none")
+	}
+
+	if _, err := manager.SequenceNumberFor(peerA, gtpv1.CreatePDPContextResponse, nil); err == nil {
+		t.Errorf("[TestSequenceNumberManager] expected error for response without request, got none")
+	}
+
+	allocated := make(chan uint16, 1000)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for j := 0; j < 100; j++ {
+				allocated <- manager.Next(peerB)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	close(allocated)
+
+	seen := make(map[uint16]bool)
+	for sequenceNumber := range allocated {
+		if seen[sequenceNumber] {
+			t.Fatalf("[TestSequenceNumberManager] sequence number (%d) allocated more than once", sequenceNumber)
+		}
+		seen[sequenceNumber] = true
+	}
+}
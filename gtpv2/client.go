@@ -0,0 +1,173 @@
+package gtpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrVersionNotSupported is returned by a Client when the peer answers a
+// request with a Version Not Supported Indication
+var ErrVersionNotSupported = errors.New("peer answered with Version Not Supported Indication")
+
+// RejectionError is returned by a Client when the Cause in the answer to a
+// request is a rejection (see CauseValue.IsRejection).  Response is the
+// answer, which may carry IEs that explain the rejection.
+type RejectionError struct {
+	Cause       CauseValue
+	OffendingIE *OffendingIE
+	Response    *PDU
+}
+
+// Error returns the message type of the answer and the Cause value
+func (rejection *RejectionError) Error() string {
+	if rejection.OffendingIE != nil {
+		return fmt.Sprintf("peer answered with (%s) with rejection cause (%s) for IE (%s) instance (%d)", NameOfMessageForType(rejection.Response.Type), rejection.Cause, NameOfIEForType(rejection.OffendingIE.Type), rejection.OffendingIE.InstanceNumber)
+	}
+
+	return fmt.Sprintf("peer answered with (%s) with rejection cause (%s)", NameOfMessageForType(rejection.Response.Type), rejection.Cause)
+}
+
+// ClientConfig provides the settings for Dial
+type ClientConfig struct {
+	// LocalAddress is the UDP address to which the Client socket is bound.
+	// If it is empty, an ephemeral port on all addresses is used.
+	LocalAddress string
+
+	// Transport configures the Transport for the Client socket
+	Transport TransportConfig
+
+	// SequenceNumbers allocates the sequence numbers for requests.  If it is
+	// nil, the Client creates its own.
+	SequenceNumbers *SequenceNumberManager
+}
+
+// Client sends requests to a single GTPv2 peer over a Transport, allocating
+// the sequence number for each request.  A Client is safe for concurrent use.
+type Client struct {
+	transport       *Transport
+	peer            net.Addr
+	sequenceNumbers *SequenceNumberManager
+	ownsTransport   bool
+}
+
+// Dial creates a Client for the peer at the UDP address peerAddress, with a
+// Transport on a new socket.  The socket is closed by Client.Close.
+func Dial(peerAddress string, config ClientConfig) (*Client, error) {
+	peer, err := net.ResolveUDPAddr("udp", peerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	localAddress := config.LocalAddress
+	if localAddress == "" {
+		localAddress = ":0"
+	}
+
+	conn, err := net.ListenPacket("udp", localAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewClient(NewTransport(conn, config.Transport), peer, config.SequenceNumbers)
+	client.ownsTransport = true
+
+	return client, nil
+}
+
+// NewClient creates a Client for peer that sends over transport, which may be
+// shared with a Server or other Clients.  sequenceNumbers should be shared by
+// everything that sends requests over transport.  If it is nil, the Client
+// creates its own.
+func NewClient(transport *Transport, peer net.Addr, sequenceNumbers *SequenceNumberManager) *Client {
+	if sequenceNumbers == nil {
+		sequenceNumbers = NewSequenceNumberManager()
+	}
+
+	return &Client{
+		transport:       transport,
+		peer:            peer,
+		sequenceNumbers: sequenceNumbers,
+	}
+}
+
+// Peer returns the address of the peer
+func (client *Client) Peer() net.Addr {
+	return client.peer
+}
+
+// Transport returns the Transport used by the Client
+func (client *Client) Transport() *Transport {
+	return client.transport
+}
+
+// SendRequest sets the sequence number of request to the next one for the
+// peer, sends it, and returns the answer.  Besides the errors from
+// Transport.SendRequest (including ErrNoResponse when the peer does not
+// answer), returns ErrVersionNotSupported if the answer is a Version Not
+// Supported Indication, and a *RejectionError if the answer has a Cause that
+// rejects the request.  A message piggybacked on the answer is not returned
+// (see SendRequestForAnswer), but a piggybacked request is also received by
+// the Transport, so it reaches Transport.Receive or a Server.
+func (client *Client) SendRequest(ctx context.Context, request *PDU) (*PDU, error) {
+	answer, err := client.SendRequestForAnswer(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return answer.PDU, nil
+}
+
+// SendRequestForAnswer is SendRequest, but returns the answer as it was
+// received, including the PiggybackedPDU, if there is one
+func (client *Client) SendRequestForAnswer(ctx context.Context, request *PDU) (*IncomingMessage, error) {
+	request.SequenceNumber = client.sequenceNumbers.Next(client.peer, request.Type)
+
+	answer, err := client.transport.SendRequest(ctx, client.peer, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if answer.PDU.Type == VersionNotSupportedIndication {
+		return nil, ErrVersionNotSupported
+	}
+
+	for _, ie := range answer.PDU.InformationElements {
+		if ie.Type == Cause && ie.InstanceNumber == 0 {
+			if cause, err := makeTypedCause(ie); err == nil && cause.IsRejection() {
+				return nil, &RejectionError{Cause: cause.Value, OffendingIE: cause.OffendingIE, Response: answer.PDU}
+			}
+			break
+		}
+	}
+
+	return answer, nil
+}
+
+// Exchange marshals request, sends it as with SendRequest, and unmarshals the
+// answer into response.  Returns an error if the answer is not of the type of
+// response, or if it cannot be unmarshalled.
+func (client *Client) Exchange(ctx context.Context, request TypedMessage, response TypedMessage) error {
+	requestPdu, err := request.Marshal()
+	if err != nil {
+		return err
+	}
+
+	answer, err := client.SendRequest(ctx, requestPdu)
+	if err != nil {
+		return err
+	}
+
+	return response.Unmarshal(answer)
+}
+
+// Close closes the Transport if it was created by Dial.  Otherwise, it does
+// nothing.
+func (client *Client) Close() error {
+	if client.ownsTransport {
+		return client.transport.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package gtpv2
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	server := NewServer(ServerConfig{})
+
+	server.HandleFunc(DeleteSessionRequest, func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+		var deleteSessionRequest TypedDeleteSessionRequest
+		if err := deleteSessionRequest.Unmarshal(request.PDU); err != nil {
+			return nil, err
+		}
+
+		if deleteSessionRequest.LinkedEBI == nil || *deleteSessionRequest.LinkedEBI != 5 {
+			return nil, &RequestError{Cause: CauseMandatoryIEIncorrect, OffendingIE: &OffendingIE{Type: EBI}, Reason: errors.New("unknown EBI")}
+		}
+
+		return (&TypedDeleteSessionResponse{
+			TEID:  0x1000,
+			Cause: &TypedCause{Value: CauseRequestAccepted},
+		}).Marshal()
+	})
+
+	serverTransport, _ := startLoopbackServer(t, server)
+
+	client, err := Dial(serverTransport.LocalAddr().String(), ClientConfig{
+		LocalAddress: "127.0.0.1:0",
+		Transport:    TransportConfig{T3Response: 50 * time.Millisecond, N3Requests: 1},
+	})
+	if err != nil {
+		t.Fatalf("[TestClient] expected no error on Dial, got (%s)", err)
+	}
+	defer client.Close()
+
+	var response TypedDeleteSessionResponse
+	err = client.Exchange(context.Background(), &TypedDeleteSessionRequest{TEID: 0x2000, LinkedEBI: uint8Pointer(5)}, &response)
+	if err != nil {
+		t.Fatalf("[TestClient] expected no error on Exchange, got (%s)", err)
+	}
+
+	if response.TEID != 0x1000 || response.Cause == nil || response.Cause.Value != CauseRequestAccepted {
+		t.Errorf("[TestClient] Delete Session Response is not correct")
+	}
+
+	err = client.Exchange(context.Background(), &TypedDeleteSessionRequest{TEID: 0x2000, LinkedEBI: uint8Pointer(6)}, &response)
+
+	var rejection *RejectionError
+	if !errors.As(err, &rejection) {
+		t.Fatalf("[TestClient] expected RejectionError, got (%v)", err)
+	}
+
+	if rejection.Cause != CauseMandatoryIEIncorrect || rejection.OffendingIE == nil || rejection.OffendingIE.Type != EBI || rejection.Response.Type != DeleteSessionResponse {
+		t.Errorf("[TestClient] RejectionError is not correct")
+	}
+
+	firstRequest := NewPDU(ModifyBearerRequest, 0, []*IE{}).AddTEID(0x2000)
+	if _, err := client.SendRequest(context.Background(), firstRequest); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("[TestClient] expected ErrNoResponse for request with no handler, got (%v)", err)
+	}
+
+	secondRequest := NewPDU(ModifyBearerRequest, 0, []*IE{}).AddTEID(0x2000)
+	client.SendRequest(context.Background(), secondRequest)
+
+	if secondRequest.SequenceNumber != (firstRequest.SequenceNumber+1)&0x7fffff {
+		t.Errorf("[TestClient] expected sequence number (%06x) to follow (%06x)", secondRequest.SequenceNumber, firstRequest.SequenceNumber)
+	}
+}
+
+func TestClientVersionNotSupported(t *testing.T) {
+	transport, peerConn := newLoopbackTransportAndPeer(t, TransportConfig{T3Response: time.Second})
+
+	go func() {
+		request, from := readPDUFromConn(t, peerConn, time.Second)
+		if request == nil {
+			return
+		}
+
+		peerConn.WriteTo(NewPDU(VersionNotSupportedIndication, request.SequenceNumber, []*IE{}).Encode(), from)
+	}()
+
+	client := NewClient(transport, peerConn.LocalAddr(), nil)
+
+	if _, err := client.SendRequest(context.Background(), NewPDU(CreateSessionRequest, 0, []*IE{}).AddTEID(0)); !errors.Is(err, ErrVersionNotSupported) {
+		t.Errorf("[TestClientVersionNotSupported] expected ErrVersionNotSupported, got (%v)", err)
+	}
+
+	// a Version Not Supported Indication that answers nothing is discarded
+	peerConn.WriteTo(NewPDU(VersionNotSupportedIndication, 0x10, []*IE{}).Encode(), transport.LocalAddr())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := transport.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("[TestClientVersionNotSupported] expected unsolicited Version Not Supported Indication not to be delivered, got (%v)", err)
+	}
+
+	if client.Close() != nil || client.Peer().String() != peerConn.LocalAddr().(*net.UDPAddr).String() {
+		t.Errorf("[TestClientVersionNotSupported] Close or Peer is not correct")
+	}
+}
+
+func TestClientPiggybackedAnswer(t *testing.T) {
+	transport, peerConn := newLoopbackTransportAndPeer(t, TransportConfig{T3Response: time.Second})
+
+	go func() {
+		request, from := readPDUFromConn(t, peerConn, time.Second)
+		if request == nil {
+			return
+		}
+
+		csResponse := NewPDU(CreateSessionResponse, request.SequenceNumber, []*IE{NewIEWithRawData(Cause, []byte{byte(CauseRequestAccepted), 0})}).AddTEID(0x10)
+		stream := append(csResponse.Encode(), NewPDU(CreateBearerRequest, 0x000701, []*IE{}).AddTEID(0x10).Encode()...)
+		stream[0] |= 0x10
+
+		peerConn.WriteTo(stream, from)
+	}()
+
+	client := NewClient(transport, peerConn.LocalAddr(), nil)
+
+	answer, err := client.SendRequestForAnswer(context.Background(), NewPDU(CreateSessionRequest, 0, []*IE{}).AddTEID(0))
+	if err != nil {
+		t.Fatalf("[TestClientPiggybackedAnswer] expected no error, got (%s)", err)
+	}
+
+	if answer.PDU.Type != CreateSessionResponse || answer.PiggybackedPDU == nil || answer.PiggybackedPDU.Type != CreateBearerRequest {
+		t.Errorf("[TestClientPiggybackedAnswer] expected Create Session Response with piggybacked Create Bearer Request")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if incoming, err := transport.Receive(ctx); err != nil || incoming.PDU.Type != CreateBearerRequest || incoming.PDU.SequenceNumber != 0x000701 {
+		t.Errorf("[TestClientPiggybackedAnswer] expected piggybacked Create Bearer Request to be received, got (%v)", err)
+	}
+}
@@ -0,0 +1,162 @@
+package gtpv2
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// RequestError is a problem with a received request that, under TS 29.274
+// section 7.7, is reported to the sender with a rejection Cause in the
+// response.  OffendingIE is nil when the Cause does not identify an IE.
+type RequestError struct {
+	Cause       CauseValue
+	OffendingIE *OffendingIE
+	Reason      error
+}
+
+// Error returns the Cause and the reason for the error
+func (requestError *RequestError) Error() string {
+	return fmt.Sprintf("request rejected with cause (%s): %s", requestError.Cause, requestError.Reason)
+}
+
+// Unwrap returns the reason for the error
+func (requestError *RequestError) Unwrap() error {
+	return requestError.Reason
+}
+
+// DecodePDUHeader decodes only the header of a GTPv2 PDU.  The returned PDU
+// has no IEs.  It is useful for identifying a request that DecodePDU cannot
+// decode, so that it can be rejected.  Returns an error if the stream is too
+// short for the header, or if the version is not 2.
+func DecodePDUHeader(stream []byte) (*PDU, error) {
+	if len(stream) < 8 {
+		return nil, fmt.Errorf("stream length (%d) too short for a GTPv2 PDU header", len(stream))
+	}
+
+	if (stream[0] >> 5) != 2 {
+		return nil, fmt.Errorf("GTPv2 PDU version should be 2, but in stream, it is (%d)", (stream[0] >> 5))
+	}
+
+	pdu := &PDU{
+		IsCarryingPiggybackedPDU: (stream[0] & 0x10) == 0x10,
+		Type:                     MessageType(stream[1]),
+		TotalLength:              binary.BigEndian.Uint16(stream[2:4]) + 4,
+		InformationElements:      make([]*IE, 0),
+	}
+
+	if (stream[0] & 0x08) == 0x08 {
+		if len(stream) < 12 {
+			return nil, fmt.Errorf("stream length (%d) too short for a GTPv2 PDU header with TEID", len(stream))
+		}
+
+		pdu.TEIDFieldIsPresent = true
+		pdu.TEID = binary.BigEndian.Uint32(stream[4:8])
+		pdu.SequenceNumber = binary.BigEndian.Uint32(stream[8:12]) >> 8
+
+		if (stream[0] & 0x04) == 0x04 {
+			pdu.PriorityFieldIsPresent = true
+			pdu.Priority = stream[11] >> 4
+		}
+	} else {
+		pdu.SequenceNumber = binary.BigEndian.Uint32(stream[4:8]) >> 8
+	}
+
+	return pdu, nil
+}
+
+// DecodeRequest is the same as DecodePDU, but is meant for a received request.
+// If the stream cannot be decoded but the header can, the returned pdu is the
+// header (see DecodePDUHeader), and the error is a *RequestError with Cause
+// CauseInvalidLength if the header length field does not match the length of
+// the stream, or CauseInvalidMessageFormat otherwise.  That pdu and error may
+// be passed to NewErrorResponse.  If the header cannot be decoded, the returned
+// pdu is nil, because there is no way to respond.
+func DecodeRequest(stream []byte) (pdu *PDU, piggybackedPdu *PDU, err error) {
+	pdu, piggybackedPdu, err = DecodePDU(stream)
+	if err == nil {
+		return pdu, piggybackedPdu, nil
+	}
+
+	header, headerErr := DecodePDUHeader(stream)
+	if headerErr != nil {
+		return nil, nil, err
+	}
+
+	cause := CauseInvalidMessageFormat
+	if !header.IsCarryingPiggybackedPDU && int(header.TotalLength) != len(stream) {
+		cause = CauseInvalidLength
+	}
+
+	return header, nil, &RequestError{Cause: cause, Reason: err}
+}
+
+// ValidateRequest applies PDU.Validate in ValidationLenient mode, and returns
+// a *RequestError for the first violation, or nil if there are none.  A
+// missing mandatory IE produces CauseMandatoryIEMissing, and an incorrect
+// mandatory IE produces CauseMandatoryIEIncorrect.  In both cases, the IE is
+// the offending IE.
+func ValidateRequest(pdu *PDU) error {
+	violations := pdu.Validate(ValidationLenient)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	violation := violations[0]
+
+	cause := CauseMandatoryIEIncorrect
+	if violation.Type == ViolationMissingMandatoryIE {
+		cause = CauseMandatoryIEMissing
+	}
+
+	return &RequestError{
+		Cause:       cause,
+		OffendingIE: &OffendingIE{Type: violation.IEType, InstanceNumber: violation.Instance},
+		Reason:      fmt.Errorf("%s", violation),
+	}
+}
+
+// NewErrorResponse creates the response to a rejected request.  The response
+// has the message type that answers the request type, the sequence number of
+// the request, and a Cause IE.  If err is (or wraps) a *RequestError, the Cause
+// value and offending IE come from it.  Any other err is treated as a failure
+// to decode the request, so the Cause value is CauseInvalidMessageFormat.  The
+// response TEID is the TEID of the Sender F-TEID for Control Plane in the
+// request, if it is present and can be decoded, and 0 otherwise (TS 29.274
+// section 5.5.2).  When the sender's TEID is known from the session state
+// instead, the caller should change the response TEID.  Returns an error if
+// the request message type has no response that carries a Cause.
+func NewErrorResponse(request *PDU, err error) (*PDU, error) {
+	responseType, isRequest := ResponseTypeFor(request.Type)
+	if !isRequest || request.Type == EchoRequest {
+		return nil, fmt.Errorf("message type (%s) has no response that carries a Cause", NameOfMessageForType(request.Type))
+	}
+
+	cause := &TypedCause{Value: CauseInvalidMessageFormat}
+
+	var requestError *RequestError
+	if errors.As(err, &requestError) {
+		cause.Value = requestError.Cause
+		cause.OffendingIE = requestError.OffendingIE
+	}
+
+	causeIE, causeErr := cause.ToIEErrorable()
+	if causeErr != nil {
+		return nil, causeErr
+	}
+
+	return NewPDU(responseType, request.SequenceNumber, []*IE{causeIE}).AddTEID(senderTEIDIn(request)), nil
+}
+
+func senderTEIDIn(request *PDU) uint32 {
+	for _, ie := range request.InformationElements {
+		if ie.Type == FTEID && ie.InstanceNumber == 0 {
+			if fteid, err := makeTypedFTEID(ie); err == nil {
+				return fteid.Key
+			}
+			return 0
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,108 @@
+package gtpv2
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	request := &TypedCreateSessionRequest{
+		SequenceNumber:             0x00abcdef,
+		RATType:                    uint8Pointer(6),
+		SenderFTEIDForControlPlane: &TypedFTEID{InterfaceType: 10, Key: 0x0a0b0c0d, IPv4Addr: net.IP{10, 0, 0, 1}},
+	}
+	request.NewBearerContextToBeCreated(5)
+
+	requestPDU, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestNewErrorResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	validationErr := ValidateRequest(requestPDU)
+	if validationErr == nil {
+		t.Fatalf("[TestNewErrorResponse] expected error on ValidateRequest with missing APN, got none")
+	}
+
+	response, err := NewErrorResponse(requestPDU, validationErr)
+	if err != nil {
+		t.Fatalf("[TestNewErrorResponse] expected no error on NewErrorResponse, got error = (%s)", err.Error())
+	}
+
+	if response.Type != CreateSessionResponse || response.SequenceNumber != 0x00abcdef || !response.TEIDFieldIsPresent || response.TEID != 0x0a0b0c0d {
+		t.Errorf("[TestNewErrorResponse] response header is not correct: type = (%s), sequence number = (0x%06x), TEID = (0x%08x)", NameOfMessageForType(response.Type), response.SequenceNumber, response.TEID)
+	}
+
+	if len(response.InformationElements) != 1 {
+		t.Fatalf("[TestNewErrorResponse] expected (1) IE in response, got (%d)", len(response.InformationElements))
+	}
+
+	if err := compareByteArrays([]byte{70, 0x00, byte(APN), 0x00, 0x00, 0x00}, response.InformationElements[0].Data); err != nil {
+		t.Errorf("[TestNewErrorResponse] Cause IE data for missing APN does not match expected: %s", err.Error())
+	}
+
+	incorrectRequestPDU := NewPDU(CreateSessionRequest, 1, []*IE{
+		NewIEWithRawData(RATType, []byte{}),
+		requestPDU.InformationElements[1],
+		NewIEWithRawData(APN, []byte{0x08, 'i', 'n', 't', 'e', 'r', 'n', 'e', 't'}),
+		requestPDU.InformationElements[2],
+	}).AddTEID(0)
+
+	response, err = NewErrorResponse(incorrectRequestPDU, ValidateRequest(incorrectRequestPDU))
+	if err != nil {
+		t.Fatalf("[TestNewErrorResponse] expected no error on NewErrorResponse, got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays([]byte{69, 0x00, byte(RATType), 0x00, 0x00, 0x00}, response.InformationElements[0].Data); err != nil {
+		t.Errorf("[TestNewErrorResponse] Cause IE data for incorrect RAT Type does not match expected: %s", err.Error())
+	}
+
+	encodedRequest := requestPDU.Encode()
+
+	header, _, err := DecodeRequest(encodedRequest[:len(encodedRequest)-1])
+	if header == nil || err == nil {
+		t.Fatalf("[TestNewErrorResponse] expected header and error on DecodeRequest of truncated request")
+	}
+
+	response, err = NewErrorResponse(header, err)
+	if err != nil {
+		t.Fatalf("[TestNewErrorResponse] expected no error on NewErrorResponse, got error = (%s)", err.Error())
+	}
+
+	if response.SequenceNumber != 0x00abcdef || response.TEID != 0 {
+		t.Errorf("[TestNewErrorResponse] expected response to truncated request to have sequence number (0xabcdef) and TEID (0)")
+	}
+
+	if err := compareByteArrays([]byte{67, 0x00}, response.InformationElements[0].Data); err != nil {
+		t.Errorf("[TestNewErrorResponse] Cause IE data for truncated request does not match expected: %s", err.Error())
+	}
+
+	encodedRequest[13] = 0xff // length of first IE (RAT Type) exceeds the message
+	header, _, err = DecodeRequest(encodedRequest)
+	var requestError *RequestError
+	if header == nil || !errors.As(err, &requestError) || requestError.Cause != CauseInvalidMessageFormat {
+		t.Errorf("[TestNewErrorResponse] expected RequestError with cause Invalid Message Format on DecodeRequest with IE overflow, got error = (%v)", err)
+	}
+
+	if pdu, _, err := DecodeRequest([]byte{0x48, 0x20, 0x00}); pdu != nil || err == nil {
+		t.Errorf("[TestNewErrorResponse] expected nil PDU and error on DecodeRequest with incomplete header")
+	}
+
+	if _, _, err := DecodeRequest([]byte{0x50, 0x01, 0x00, 0x04, 0x00, 0x00, 0x01, 0x00}); err == nil {
+		t.Errorf("[TestNewErrorResponse] expected error on DecodeRequest with piggyback flag but no piggybacked PDU")
+	}
+
+	response, err = NewErrorResponse(NewPDU(DeleteSessionRequest, 7, []*IE{}), fmt.Errorf("some error"))
+	if err != nil {
+		t.Fatalf("[TestNewErrorResponse] expected no error on NewErrorResponse, got error = (%s)", err.Error())
+	}
+
+	if response.Type != DeleteSessionResponse || response.InformationElements[0].Data[0] != byte(CauseInvalidMessageFormat) {
+		t.Errorf("[TestNewErrorResponse] expected Delete Session Response with cause Invalid Message Format for generic error")
+	}
+
+	if _, err := NewErrorResponse(NewPDU(EchoRequest, 1, []*IE{}), validationErr); err == nil {
+		t.Errorf("[TestNewErrorResponse] expected error on NewErrorResponse for Echo Request, got none")
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
-	"regexp"
-	"strings"
+
+	"github.com/blorticus-go/gtp/tbcd"
 )
 
 // IEType represents the various IE types for GTPv2
@@ -171,6 +171,8 @@ const (
 	MappedUEUsageType                                    = 200
 	SecondaryRATUsageDataReport                          = 201
 	UPFunctionSelectionIndicationFlags                   = 202
+	MaximumPacketLossRate                                = 203
+	APNRateControlStatus                                 = 204
 	ExtensionType                                        = 254
 	PrivateExtension                                     = 255
 )
@@ -216,7 +218,7 @@ var ieNames = []string{
 	"WLAN Offloadability Indication", "Paging and Service Information", "Integer Number", "Millisecond Time Stamp", "Monitoring Event Information",
 	"ECGI List", "Remote UE Context", "Remote User ID", "Remote UE IP information", "CIoT Optimizations Support Indication",
 	"SCEF PDN Connection", "Header Compression Configuration", "Extended Protocol Configuration Options (ePCO)", "Serving PLMN Rate Control", "Counter", // 199
-	"Mapped UE Usage Type", "Secondary RAT Usage Data Report", "UP Function Selection Indication Flags", "Reserved", "Reserved",
+	"Mapped UE Usage Type", "Secondary RAT Usage Data Report", "UP Function Selection Indication Flags", "Maximum Packet Loss Rate", "APN Rate Control Status",
 	"Reserved", "Reserved", "Reserved", "Reserved", "Reserved",
 	"Reserved", "Reserved", "Reserved", "Reserved", "Reserved",
 	"Reserved", "Reserved", "Reserved", "Reserved", "Reserved", // 219
@@ -364,15 +366,57 @@ func (ie *IE) Encode() []byte {
 	return encodedBytes
 }
 
+// errNoTypeConversion is returned by TypedDataErrorable for an IE type that
+// has no typed representation
+var errNoTypeConversion = fmt.Errorf("no type conversion for IE")
+
 func (ie *IE) TypedDataErrorable() (TypedIE, error) {
 	switch ie.Type {
 	case IMSI:
 		return makeTypedIMSI(ie)
 	case FTEID:
 		return makeTypedFTEID(ie)
+	case Cause:
+		return makeTypedCause(ie)
+	case ULI:
+		return makeTypedULI(ie)
+	case BearerQoS:
+		return makeTypedBearerQoS(ie)
+	case FlowQoS:
+		return makeTypedFlowQoS(ie)
+	case AMBR:
+		return makeTypedAMBR(ie)
+	case PAA:
+		return makeTypedPAA(ie)
+	case PDNType:
+		return makeTypedPDNType(ie)
+	case APN:
+		return makeTypedAPN(ie)
+	case FQDN:
+		return makeTypedFQDN(ie)
+	case MEI:
+		return makeTypedMEI(ie)
+	case MSISDN:
+		return makeTypedMSISDN(ie)
+	case ServingNetwork:
+		return makeTypedServingNetwork(ie)
+	case PLMNID:
+		return makeTypedPLMNID(ie)
+	case Indication:
+		return makeTypedIndication(ie)
+	case ProtocolConfigurationOptions:
+		return makeTypedPCO(ie)
+	case APCO:
+		return makeTypedAPCO(ie)
+	case ePCO:
+		return makeTypedEPCO(ie)
+	case BearerTFT:
+		return makeTypedBearerTFT(ie)
+	case BearerContext:
+		return makeTypedBearerContext(ie)
 
 	default:
-		return nil, fmt.Errorf("no type conversion for IE")
+		return nil, errNoTypeConversion
 	}
 }
 
@@ -507,63 +551,20 @@ func (imsi *TypedIMSI) ToIE() *IE {
 	return ie
 }
 
-var matcherForProperIMSI = regexp.MustCompile(`^\d{1,15}$`)
-
-func stringDigitToByte(digit string) byte {
-	switch digit {
-	case "0":
-		return 0x00
-	case "1":
-		return 0x01
-	case "2":
-		return 0x02
-	case "3":
-		return 0x03
-	case "4":
-		return 0x04
-	case "5":
-		return 0x05
-	case "6":
-		return 0x06
-	case "7":
-		return 0x07
-	case "8":
-		return 0x08
-	case "9":
-		return 0x09
-	default:
-		return 0x00
-	}
-}
-
 // ToIEErrorable is the same as ToIE, but returns an error if one
 // occurs, rather than panicing
 func (imsi *TypedIMSI) ToIEErrorable() (*IE, error) {
-	if !matcherForProperIMSI.MatchString(imsi.AsString) {
-		return nil, fmt.Errorf("invalid format for IMSI string")
+	if len(imsi.AsString) > 15 {
+		return nil, fmt.Errorf("IMSI contains (%d) digits, but must contain no more than 15", len(imsi.AsString))
 	}
 
 	// encoding requires that last nyble is 1111b if there is an odd number of digits in IMSI
-	data := make([]byte, 0, (len(imsi.AsString)/2 + (len(imsi.AsString) % 2)))
-
-	imsiAsSequence := strings.Split(imsi.AsString, "")
-
-	if len(imsiAsSequence)%2 == 0 {
-		for i := 0; i < len(imsiAsSequence); i += 2 {
-			encodedByte := (stringDigitToByte(imsiAsSequence[i+1]) << 4) | stringDigitToByte(imsiAsSequence[i])
-			data = append(data, encodedByte)
-		}
-	} else {
-		for i := 0; i < len(imsiAsSequence)-1; i += 2 {
-			encodedByte := (stringDigitToByte(imsiAsSequence[i+1]) << 4) | stringDigitToByte(imsiAsSequence[i])
-			data = append(data, encodedByte)
-		}
-
-		encodedByte := 0xf0 | stringDigitToByte(imsiAsSequence[len(imsiAsSequence)-1])
-		data = append(data, encodedByte)
+	data, err := tbcd.Encode(imsi.AsString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMSI: %s", err)
 	}
 
-	return NewIEWithRawData(IMSI, data), nil
+	return NewIEWithRawDataErrorable(IMSI, data)
 }
 
 func makeTypedIMSI(fromIE *IE) (*TypedIMSI, error) {
@@ -575,35 +576,13 @@ func makeTypedIMSI(fromIE *IE) (*TypedIMSI, error) {
 		return nil, fmt.Errorf("length of IE data is not correct for IMSI type")
 	}
 
-	var imsiBytesAsStrings []string
-
-	imsiBytesAsStrings = make([]string, 0, len(fromIE.Data))
-
-	for i, imsiByte := range fromIE.Data {
-		highNybble := (imsiByte & 0xf0) >> 4
-
-		if highNybble == 0x0f {
-			if i < len(fromIE.Data)-1 {
-				return nil, fmt.Errorf("invalid IMSI ecnode value")
-			}
-		} else if highNybble > 9 {
-			return nil, fmt.Errorf("invalid IMSI ecnode value")
-		}
-
-		lowNybble := imsiByte & 0x0f
-		if lowNybble > 9 {
-			return nil, fmt.Errorf("invalid IMSI encode value")
-		}
-
-		if highNybble == 0x0f {
-			imsiBytesAsStrings = append(imsiBytesAsStrings, string(rune(int('0')+int(lowNybble))))
-		} else {
-			imsiBytesAsStrings = append(imsiBytesAsStrings, string(rune(int('0')+int(lowNybble)))+string(rune(int('0')+int(highNybble))))
-		}
+	asString, err := tbcd.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMSI encode value: %s", err)
 	}
 
 	imsi := &TypedIMSI{
-		AsString: strings.Join(imsiBytesAsStrings, ""),
+		AsString: asString,
 	}
 
 	return imsi, nil
@@ -0,0 +1,74 @@
+package gtpv2
+
+import (
+	"testing"
+)
+
+func TestTypedAPN(t *testing.T) {
+	typedAPN := &TypedAPN{AsString: "internet.mnc001.mcc001.gprs"}
+	expectedDataBytes := []byte{
+		0x08, 'i', 'n', 't', 'e', 'r', 'n', 'e', 't',
+		0x06, 'm', 'n', 'c', '0', '0', '1',
+		0x06, 'm', 'c', 'c', '0', '0', '1',
+		0x04, 'g', 'p', 'r', 's',
+	}
+
+	ie, err := typedAPN.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedAPN] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedAPN] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedAPN] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	decodedAPN := typed.(*TypedAPN)
+
+	if decodedAPN.AsString != typedAPN.AsString {
+		t.Errorf("[TestTypedAPN] expected AsString = (%s), got (%s)", typedAPN.AsString, decodedAPN.AsString)
+	}
+
+	if decodedAPN.NetworkIdentifier() != "internet" {
+		t.Errorf("[TestTypedAPN] expected NetworkIdentifier() = (internet), got (%s)", decodedAPN.NetworkIdentifier())
+	}
+
+	if decodedAPN.OperatorIdentifier() != "mnc001.mcc001.gprs" {
+		t.Errorf("[TestTypedAPN] expected OperatorIdentifier() = (mnc001.mcc001.gprs), got (%s)", decodedAPN.OperatorIdentifier())
+	}
+
+	if _, err := (&TypedAPN{AsString: "internet..gprs"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedAPN] expected error on ToIEErrorable for empty label, but got none")
+	}
+
+	if _, err := NewIEWithRawData(APN, []byte{0x09, 'i', 'n', 't'}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedAPN] expected error on TypedData for truncated label, but got none")
+	}
+}
+
+func TestTypedFQDN(t *testing.T) {
+	fqdn := &TypedFQDN{AsString: "pgw.example.com"}
+	expectedDataBytes := []byte{0x03, 'p', 'g', 'w', 0x07, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0x03, 'c', 'o', 'm'}
+
+	ie, err := fqdn.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedFQDN] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedFQDN] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedFQDN] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if got := typed.(*TypedFQDN).AsString; got != fqdn.AsString {
+		t.Errorf("[TestTypedFQDN] expected AsString = (%s), got (%s)", fqdn.AsString, got)
+	}
+}
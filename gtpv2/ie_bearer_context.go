@@ -0,0 +1,360 @@
+package gtpv2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/blorticus-go/gtp/tft"
+)
+
+// FTEIDRole identifies the purpose of an F-TEID in a Bearer Context (e.g., the
+// S1-U eNodeB F-TEID).  In a Bearer Context, an F-TEID's role is not carried
+// in the IE.  Instead, it is determined by the F-TEID's instance number, and the
+// mapping of instance numbers to roles depends on the message type and the
+// instance number of the Bearer Context itself.
+type FTEIDRole uint8
+
+// F-TEID roles in Bearer Contexts
+const (
+	FTEIDRoleS1UENodeB FTEIDRole = iota + 1
+	FTEIDRoleS1USGW
+	FTEIDRoleS4USGSN
+	FTEIDRoleS4USGW
+	FTEIDRoleS5S8USGW
+	FTEIDRoleS5S8UPGW
+	FTEIDRoleS12RNC
+	FTEIDRoleS12SGW
+	FTEIDRoleS2bUePDG
+	FTEIDRoleS2bUPGW
+	FTEIDRoleS2aUTWAN
+	FTEIDRoleS2aUPGW
+	FTEIDRoleS11UMME
+	FTEIDRoleS11USGW
+)
+
+var fteidRoleNames = []string{
+	"", "S1-U eNodeB", "S1-U SGW", "S4-U SGSN", "S4-U SGW", "S5/S8-U SGW", "S5/S8-U PGW",
+	"S12 RNC", "S12 SGW", "S2b-U ePDG", "S2b-U PGW", "S2a-U TWAN", "S2a-U PGW", "S11-U MME", "S11-U SGW",
+}
+
+// String returns the name of the role (e.g., "S5/S8-U SGW")
+func (role FTEIDRole) String() string {
+	if role > 0 && int(role) < len(fteidRoleNames) {
+		return fteidRoleNames[role]
+	}
+
+	return fmt.Sprintf("FTEIDRole(%d)", uint8(role))
+}
+
+type bearerContextPlacement struct {
+	messageType MessageType
+	instance    uint8
+}
+
+// bearerContextFTEIDInstances provides, for each message type and Bearer
+// Context instance, the instance number of each F-TEID role that may appear
+// in the Bearer Context (TS 29.274 Tables 7.2.1-2, 7.2.1-3, 7.2.2-2, 7.2.3-2,
+// 7.2.4-2, 7.2.7-2, 7.2.8-2 and 7.2.16-2)
+var bearerContextFTEIDInstances = map[bearerContextPlacement]map[FTEIDRole]uint8{
+	{CreateSessionRequest, 0}: {
+		FTEIDRoleS1UENodeB: 0, FTEIDRoleS4USGSN: 1, FTEIDRoleS5S8USGW: 2, FTEIDRoleS5S8UPGW: 3,
+		FTEIDRoleS12RNC: 4, FTEIDRoleS2bUePDG: 5, FTEIDRoleS2aUTWAN: 6, FTEIDRoleS11UMME: 7,
+	},
+	{CreateSessionRequest, 1}: {
+		FTEIDRoleS4USGSN: 1,
+	},
+	{CreateSessionResponse, 0}: {
+		FTEIDRoleS1USGW: 0, FTEIDRoleS4USGW: 1, FTEIDRoleS5S8UPGW: 2, FTEIDRoleS12SGW: 3,
+		FTEIDRoleS2bUPGW: 4, FTEIDRoleS2aUPGW: 5, FTEIDRoleS11USGW: 6,
+	},
+	{ModifyBearerRequest, 0}: {
+		FTEIDRoleS1UENodeB: 0, FTEIDRoleS5S8USGW: 1, FTEIDRoleS12RNC: 2, FTEIDRoleS4USGSN: 3, FTEIDRoleS11UMME: 4,
+	},
+	{ModifyBearerResponse, 0}: {
+		FTEIDRoleS1USGW: 0, FTEIDRoleS12SGW: 1, FTEIDRoleS4USGW: 2, FTEIDRoleS11USGW: 3,
+	},
+	{CreateBearerRequest, 0}: {
+		FTEIDRoleS1USGW: 0, FTEIDRoleS5S8UPGW: 1, FTEIDRoleS12SGW: 2, FTEIDRoleS4USGW: 3,
+		FTEIDRoleS2bUPGW: 4, FTEIDRoleS2aUPGW: 5,
+	},
+	{CreateBearerResponse, 0}: {
+		FTEIDRoleS1UENodeB: 0, FTEIDRoleS1USGW: 1, FTEIDRoleS5S8USGW: 2, FTEIDRoleS5S8UPGW: 3,
+		FTEIDRoleS12RNC: 4, FTEIDRoleS12SGW: 5, FTEIDRoleS4USGSN: 6, FTEIDRoleS4USGW: 7,
+		FTEIDRoleS2bUePDG: 8, FTEIDRoleS2bUPGW: 9, FTEIDRoleS2aUTWAN: 10, FTEIDRoleS2aUPGW: 11,
+	},
+	{UpdateBearerResponse, 0}: {
+		FTEIDRoleS4USGSN: 0, FTEIDRoleS12RNC: 1,
+	},
+}
+
+// TypedBearerContext is a structured version of a Bearer Context grouped IE.
+// Because the meaning of some children depends on the message that contains
+// the Bearer Context, MessageType and Instance (the instance number of the
+// Bearer Context IE in that message) should be set.  They are used to map
+// F-TEID roles to instance numbers (see FTEID and SetFTEID), and Instance is
+// applied to the IE created by ToIE.
+//
+// EBI is always included.  The other typed children are optional, and are nil
+// when absent.  FTEIDs are keyed by instance number.  Child IEs that are not
+// modelled by a field, or that have an unexpected instance number, are kept
+// in UnknownIEs, and are re-encoded after the modelled children.
+type TypedBearerContext struct {
+	MessageType MessageType
+	Instance    uint8
+	EBI         uint8
+	TFT         *tft.TFT
+	Cause       *TypedCause
+	BearerQoS   *TypedBearerQoS
+	ChargingID  *uint32
+	BearerFlags *uint8
+	FTEIDs      map[uint8]*TypedFTEID
+	UnknownIEs  []*IE
+}
+
+func (bearerContext *TypedBearerContext) instanceForRole(role FTEIDRole) (uint8, error) {
+	roles, isKnownPlacement := bearerContextFTEIDInstances[bearerContextPlacement{bearerContext.MessageType, bearerContext.Instance}]
+	if !isKnownPlacement {
+		return 0, fmt.Errorf("no F-TEIDs are defined for a Bearer Context with instance (%d) in message type (%d)", bearerContext.Instance, bearerContext.MessageType)
+	}
+
+	instance, roleIsDefined := roles[role]
+	if !roleIsDefined {
+		return 0, fmt.Errorf("F-TEID role (%s) is not defined for a Bearer Context with instance (%d) in message type (%d)", role, bearerContext.Instance, bearerContext.MessageType)
+	}
+
+	return instance, nil
+}
+
+// FTEID returns the F-TEID for the role, based on MessageType and Instance.
+// Returns nil if the F-TEID is not present, or the role is not defined for
+// the MessageType and Instance.
+func (bearerContext *TypedBearerContext) FTEID(role FTEIDRole) *TypedFTEID {
+	instance, err := bearerContext.instanceForRole(role)
+	if err != nil {
+		return nil
+	}
+
+	return bearerContext.FTEIDs[instance]
+}
+
+// SetFTEID sets the F-TEID for the role, based on MessageType and Instance.
+// If fteid is nil, the F-TEID is removed.  Returns an error if the role is
+// not defined for the MessageType and Instance.
+func (bearerContext *TypedBearerContext) SetFTEID(role FTEIDRole, fteid *TypedFTEID) error {
+	instance, err := bearerContext.instanceForRole(role)
+	if err != nil {
+		return err
+	}
+
+	if fteid == nil {
+		delete(bearerContext.FTEIDs, instance)
+		return nil
+	}
+
+	if bearerContext.FTEIDs == nil {
+		bearerContext.FTEIDs = make(map[uint8]*TypedFTEID)
+	}
+
+	bearerContext.FTEIDs[instance] = fteid
+
+	return nil
+}
+
+// ToIE creates an IE from the structured version of a Bearer Context, and
+// panics if there is an error
+func (bearerContext *TypedBearerContext) ToIE() *IE {
+	ie, err := bearerContext.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (bearerContext *TypedBearerContext) ToIEErrorable() (*IE, error) {
+	if bearerContext.EBI > 0x0f {
+		return nil, fmt.Errorf("EBI (%d) exceeds maximum 4-bit value", bearerContext.EBI)
+	}
+
+	if bearerContext.Instance > 0x0f {
+		return nil, fmt.Errorf("Bearer Context instance (%d) exceeds maximum 4-bit value", bearerContext.Instance)
+	}
+
+	children := []*IE{NewIEWithRawData(EBI, []byte{bearerContext.EBI})}
+
+	appendTyped := func(name string, typed TypedIE, instance uint8) error {
+		ie, err := typed.ToIEErrorable()
+		if err != nil {
+			return fmt.Errorf("on Bearer Context %s: %s", name, err)
+		}
+
+		ie.InstanceNumber = instance
+		children = append(children, ie)
+
+		return nil
+	}
+
+	if bearerContext.Cause != nil {
+		if err := appendTyped("Cause", bearerContext.Cause, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if bearerContext.TFT != nil {
+		if err := appendTyped("TFT", &TypedBearerTFT{TFT: bearerContext.TFT}, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	// a nil F-TEID is treated as absent, as with SetFTEID(role, nil)
+	fteidInstances := make([]int, 0, len(bearerContext.FTEIDs))
+	for instance, fteid := range bearerContext.FTEIDs {
+		if fteid != nil {
+			fteidInstances = append(fteidInstances, int(instance))
+		}
+	}
+	sort.Ints(fteidInstances)
+
+	for _, instance := range fteidInstances {
+		if instance > 0x0f {
+			return nil, fmt.Errorf("Bearer Context F-TEID instance (%d) exceeds maximum 4-bit value", instance)
+		}
+
+		if err := appendTyped("F-TEID", bearerContext.FTEIDs[uint8(instance)], uint8(instance)); err != nil {
+			return nil, err
+		}
+	}
+
+	if bearerContext.BearerQoS != nil {
+		if err := appendTyped("Bearer QoS", bearerContext.BearerQoS, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if bearerContext.ChargingID != nil {
+		children = append(children, NewIEWithRawData(ChargingID, binary.BigEndian.AppendUint32(nil, *bearerContext.ChargingID)))
+	}
+
+	if bearerContext.BearerFlags != nil {
+		children = append(children, NewIEWithRawData(BearerFlags, []byte{*bearerContext.BearerFlags}))
+	}
+
+	children = append(children, bearerContext.UnknownIEs...)
+
+	ie, err := NewGroupedIEErrorable(BearerContext, children)
+	if err != nil {
+		return nil, err
+	}
+
+	ie.InstanceNumber = bearerContext.Instance
+
+	return ie, nil
+}
+
+// DecodeBearerContext converts a Bearer Context IE to a TypedBearerContext,
+// where the IE is contained in a message of type inMessageType.  The
+// TypedBearerContext Instance is the IE InstanceNumber.  Using
+// TypedDataErrorable on a Bearer Context IE is the same as using this
+// function with an inMessageType of 0, so the F-TEIDs will be available
+// by instance number, but not by role.
+func DecodeBearerContext(fromIE *IE, inMessageType MessageType) (*TypedBearerContext, error) {
+	if fromIE.Type != BearerContext {
+		return nil, fmt.Errorf("supplied IE is not of type Bearer Context")
+	}
+
+	children, err := ExtractGroupedIEsFrom(fromIE)
+	if err != nil {
+		return nil, fmt.Errorf("on Bearer Context: %s", err)
+	}
+
+	bearerContext := &TypedBearerContext{
+		MessageType: inMessageType,
+		Instance:    fromIE.InstanceNumber,
+		FTEIDs:      make(map[uint8]*TypedFTEID),
+		UnknownIEs:  make([]*IE, 0),
+	}
+
+	// a repeated child of a modelled type is kept in UnknownIEs, rather
+	// than replacing the first one
+	modelledTypesFound := make(map[IEType]bool)
+
+	for _, child := range children {
+		if child.Type == FTEID {
+			if _, isDuplicate := bearerContext.FTEIDs[child.InstanceNumber]; isDuplicate {
+				return nil, fmt.Errorf("Bearer Context contains more than one F-TEID with instance (%d)", child.InstanceNumber)
+			}
+
+			fteid, err := makeTypedFTEID(child)
+			if err != nil {
+				return nil, fmt.Errorf("on Bearer Context F-TEID with instance (%d): %s", child.InstanceNumber, err)
+			}
+
+			bearerContext.FTEIDs[child.InstanceNumber] = fteid
+			continue
+		}
+
+		if child.InstanceNumber != 0 || modelledTypesFound[child.Type] {
+			bearerContext.UnknownIEs = append(bearerContext.UnknownIEs, child)
+			continue
+		}
+
+		switch child.Type {
+		case EBI:
+			if len(child.Data) != 1 {
+				return nil, fmt.Errorf("length of Bearer Context EBI data is not correct")
+			}
+			bearerContext.EBI = child.Data[0] & 0x0f
+
+		case Cause:
+			if bearerContext.Cause, err = makeTypedCause(child); err != nil {
+				return nil, fmt.Errorf("on Bearer Context Cause: %s", err)
+			}
+
+		case BearerTFT:
+			bearerTFT, err := makeTypedBearerTFT(child)
+			if err != nil {
+				return nil, fmt.Errorf("on Bearer Context TFT: %s", err)
+			}
+			bearerContext.TFT = bearerTFT.TFT
+
+		case BearerQoS:
+			if bearerContext.BearerQoS, err = makeTypedBearerQoS(child); err != nil {
+				return nil, fmt.Errorf("on Bearer Context Bearer QoS: %s", err)
+			}
+
+		case ChargingID:
+			if len(child.Data) != 4 {
+				return nil, fmt.Errorf("length of Bearer Context Charging ID data is not correct")
+			}
+			chargingID := binary.BigEndian.Uint32(child.Data)
+			bearerContext.ChargingID = &chargingID
+
+		case BearerFlags:
+			if len(child.Data) < 1 {
+				return nil, fmt.Errorf("length of Bearer Context Bearer Flags data is not correct")
+			}
+			bearerFlags := child.Data[0]
+			bearerContext.BearerFlags = &bearerFlags
+
+		default:
+			bearerContext.UnknownIEs = append(bearerContext.UnknownIEs, child)
+			continue
+		}
+
+		modelledTypesFound[child.Type] = true
+	}
+
+	if !modelledTypesFound[EBI] {
+		return nil, fmt.Errorf("Bearer Context does not contain an EBI")
+	}
+
+	return bearerContext, nil
+}
+
+func makeTypedBearerContext(fromIE *IE) (*TypedBearerContext, error) {
+	return DecodeBearerContext(fromIE, 0)
+}
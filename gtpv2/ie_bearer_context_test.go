@@ -0,0 +1,176 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTypedBearerContext(t *testing.T) {
+	chargingID := uint32(0x01020304)
+
+	bearerContext := &TypedBearerContext{
+		MessageType: CreateSessionResponse,
+		Instance:    0,
+		EBI:         5,
+		Cause:       &TypedCause{Value: CauseRequestAccepted},
+		ChargingID:  &chargingID,
+		FTEIDs:      map[uint8]*TypedFTEID{},
+		UnknownIEs:  []*IE{},
+	}
+
+	if err := bearerContext.SetFTEID(FTEIDRoleS1USGW, &TypedFTEID{InterfaceType: 1, Key: 0x11111111, IPv4Addr: net.IP{10, 0, 0, 1}}); err != nil {
+		t.Fatalf("[TestTypedBearerContext] expected no error on SetFTEID for S1-U SGW, got error = (%s)", err.Error())
+	}
+
+	if err := bearerContext.SetFTEID(FTEIDRoleS5S8UPGW, &TypedFTEID{InterfaceType: 5, Key: 0x22222222, IPv4Addr: net.IP{10, 0, 0, 2}}); err != nil {
+		t.Fatalf("[TestTypedBearerContext] expected no error on SetFTEID for S5/S8-U PGW, got error = (%s)", err.Error())
+	}
+
+	if err := bearerContext.SetFTEID(FTEIDRoleS1UENodeB, &TypedFTEID{}); err == nil {
+		t.Errorf("[TestTypedBearerContext] expected error on SetFTEID for S1-U eNodeB in Create Session Response, got none")
+	}
+
+	expectedDataBytes := []byte{
+		0x49, 0x00, 0x01, 0x00, 0x05,
+		0x02, 0x00, 0x02, 0x00, 0x10, 0x00,
+		0x57, 0x00, 0x09, 0x00, 0x81, 0x11, 0x11, 0x11, 0x11, 10, 0, 0, 1,
+		0x57, 0x00, 0x09, 0x02, 0x85, 0x22, 0x22, 0x22, 0x22, 10, 0, 0, 2,
+		0x5e, 0x00, 0x04, 0x00, 0x01, 0x02, 0x03, 0x04,
+	}
+
+	ie, err := bearerContext.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedBearerContext] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedBearerContext] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	decoded, err := DecodeBearerContext(ie, CreateSessionResponse)
+	if err != nil {
+		t.Fatalf("[TestTypedBearerContext] expected no error on DecodeBearerContext, but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(bearerContext, decoded); diff != nil {
+		t.Errorf("[TestTypedBearerContext] on DecodeBearerContext: %s", diff)
+	}
+
+	if fteid := decoded.FTEID(FTEIDRoleS5S8UPGW); fteid == nil || fteid.Key != 0x22222222 {
+		t.Errorf("[TestTypedBearerContext] expected S5/S8-U PGW F-TEID with key 0x22222222, got (%v)", fteid)
+	}
+
+	if fteid := decoded.FTEID(FTEIDRoleS12SGW); fteid != nil {
+		t.Errorf("[TestTypedBearerContext] expected no S12 SGW F-TEID, got (%v)", fteid)
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedBearerContext] expected no error on TypedData, but got error = (%s)", err.Error())
+	}
+
+	if fteid := typed.(*TypedBearerContext).FTEID(FTEIDRoleS5S8UPGW); fteid != nil {
+		t.Errorf("[TestTypedBearerContext] expected no F-TEID by role without message type, got (%v)", fteid)
+	}
+
+	if fteid := typed.(*TypedBearerContext).FTEIDs[2]; fteid == nil || fteid.Key != 0x22222222 {
+		t.Errorf("[TestTypedBearerContext] expected F-TEID at instance 2 with key 0x22222222, got (%v)", fteid)
+	}
+}
+
+func TestTypedBearerContextRolesByMessage(t *testing.T) {
+	fteid := &TypedFTEID{InterfaceType: 4, Key: 0xabcd, IPv4Addr: net.IP{192, 0, 2, 1}}
+
+	testCases := []struct {
+		messageType      MessageType
+		instance         uint8
+		role             FTEIDRole
+		expectedInstance uint8
+	}{
+		{CreateSessionRequest, 0, FTEIDRoleS5S8USGW, 2},
+		{CreateSessionRequest, 1, FTEIDRoleS4USGSN, 1},
+		{ModifyBearerRequest, 0, FTEIDRoleS1UENodeB, 0},
+		{ModifyBearerRequest, 0, FTEIDRoleS11UMME, 4},
+		{ModifyBearerResponse, 0, FTEIDRoleS4USGW, 2},
+		{CreateBearerRequest, 0, FTEIDRoleS5S8UPGW, 1},
+		{CreateBearerResponse, 0, FTEIDRoleS2aUPGW, 11},
+	}
+
+	for _, testCase := range testCases {
+		bearerContext := &TypedBearerContext{MessageType: testCase.messageType, Instance: testCase.instance, EBI: 6}
+
+		if err := bearerContext.SetFTEID(testCase.role, fteid); err != nil {
+			t.Errorf("[TestTypedBearerContextRolesByMessage] (%d/%d/%s) expected no error on SetFTEID, got error = (%s)", testCase.messageType, testCase.instance, testCase.role, err.Error())
+			continue
+		}
+
+		ie := bearerContext.ToIE()
+		if ie.InstanceNumber != testCase.instance {
+			t.Errorf("[TestTypedBearerContextRolesByMessage] (%d/%d/%s) expected IE instance (%d), got (%d)", testCase.messageType, testCase.instance, testCase.role, testCase.instance, ie.InstanceNumber)
+		}
+
+		children, _ := ExtractGroupedIEsFrom(ie)
+		if len(children) != 2 || children[1].Type != FTEID || children[1].InstanceNumber != testCase.expectedInstance {
+			t.Errorf("[TestTypedBearerContextRolesByMessage] (%d/%d/%s) expected F-TEID child at instance (%d)", testCase.messageType, testCase.instance, testCase.role, testCase.expectedInstance)
+		}
+	}
+
+	if err := (&TypedBearerContext{MessageType: DeleteBearerRequest}).SetFTEID(FTEIDRoleS1USGW, fteid); err == nil {
+		t.Errorf("[TestTypedBearerContextRolesByMessage] expected error on SetFTEID for Delete Bearer Request, got none")
+	}
+}
+
+func TestTypedBearerContextPreservesUnknownIEs(t *testing.T) {
+	ie := NewGroupedIE(BearerContext, []*IE{
+		NewIEWithRawData(EBI, []byte{0x05}),
+		NewIEWithRawData(PacketFlowID, []byte{0x05, 0x00, 0x00, 0x00, 0x01}),
+		{Type: ChargingID, InstanceNumber: 1, Data: []byte{0x00, 0x00, 0x00, 0x01}, TotalLength: 8},
+		NewIEWithRawData(ChargingID, []byte{0x00, 0x00, 0x00, 0x02}),
+		NewIEWithRawData(ChargingID, []byte{0x00, 0x00, 0x00, 0x03}),
+	})
+	ie.InstanceNumber = 1
+
+	decoded, err := DecodeBearerContext(ie, CreateSessionRequest)
+	if err != nil {
+		t.Fatalf("[TestTypedBearerContextPreservesUnknownIEs] expected no error on DecodeBearerContext, but got error = (%s)", err.Error())
+	}
+
+	if decoded.Instance != 1 || decoded.ChargingID == nil || *decoded.ChargingID != 2 {
+		t.Errorf("[TestTypedBearerContextPreservesUnknownIEs] expected instance (1) and Charging ID (2), got (%d) and (%v)", decoded.Instance, decoded.ChargingID)
+	}
+
+	if len(decoded.UnknownIEs) != 3 {
+		t.Fatalf("[TestTypedBearerContextPreservesUnknownIEs] expected (3) unknown IEs, got (%d)", len(decoded.UnknownIEs))
+	}
+
+	reencoded := decoded.ToIE()
+	children, _ := ExtractGroupedIEsFrom(reencoded)
+
+	if len(children) != 5 {
+		t.Errorf("[TestTypedBearerContextPreservesUnknownIEs] expected (5) children after re-encoding, got (%d)", len(children))
+	}
+
+	if _, err := DecodeBearerContext(NewGroupedIE(BearerContext, []*IE{NewIEWithRawData(ChargingID, []byte{0, 0, 0, 1})}), CreateSessionRequest); err == nil {
+		t.Errorf("[TestTypedBearerContextPreservesUnknownIEs] expected error on DecodeBearerContext without EBI, got none")
+	}
+}
+
+func TestTypedBearerContextSkipsNilFTEID(t *testing.T) {
+	bearerContext := &TypedBearerContext{
+		MessageType: CreateSessionResponse,
+		EBI:         5,
+		FTEIDs:      map[uint8]*TypedFTEID{0: nil, 1: {InterfaceType: 1, Key: 0x01020304, IPv4Addr: net.IP{10, 0, 0, 1}}},
+	}
+
+	ie, err := bearerContext.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedBearerContextSkipsNilFTEID] expected no error on ToIEErrorable, got (%s)", err)
+	}
+
+	children, _ := ExtractGroupedIEsFrom(ie)
+	if len(children) != 2 || children[1].Type != FTEID || children[1].InstanceNumber != 1 {
+		t.Errorf("[TestTypedBearerContextSkipsNilFTEID] expected EBI and F-TEID instance (1) only, got (%d) children", len(children))
+	}
+}
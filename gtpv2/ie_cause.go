@@ -0,0 +1,297 @@
+package gtpv2
+
+import "fmt"
+
+// CauseValue is the value of the Cause field in a Cause IE (TS 29.274 section 8.4)
+type CauseValue uint8
+
+// GTPv2 Cause values, from TS 29.274 Table 8.4-1.  Values 1 through 15 are
+// used in request/initial messages, 16 through 63 indicate acceptance in a
+// response or triggered request, and 64 through 239 indicate rejection.
+const (
+	CauseLocalDetach                                         CauseValue = 2
+	CauseCompleteDetach                                      CauseValue = 3
+	CauseRATChangedFrom3GPPToNon3GPP                         CauseValue = 4
+	CauseISRDeactivation                                     CauseValue = 5
+	CauseErrorIndicationReceived                             CauseValue = 6
+	CauseIMSIDetachOnly                                      CauseValue = 7
+	CauseReactivationRequested                               CauseValue = 8
+	CausePDNReconnectionToThisAPNDisallowed                  CauseValue = 9
+	CauseAccessChangedFromNon3GPPTo3GPP                      CauseValue = 10
+	CausePDNConnectionInactivityTimerExpires                 CauseValue = 11
+	CausePGWNotResponding                                    CauseValue = 12
+	CauseNetworkFailure                                      CauseValue = 13
+	CauseQoSParameterMismatch                                CauseValue = 14
+	CauseEPSTo5GSMobility                                    CauseValue = 15
+	CauseRequestAccepted                                     CauseValue = 16
+	CauseRequestAcceptedPartially                            CauseValue = 17
+	CauseNewPDNTypeDueToNetworkPreference                    CauseValue = 18
+	CauseNewPDNTypeDueToSingleAddressBearerOnly              CauseValue = 19
+	CauseContextNotFound                                     CauseValue = 64
+	CauseInvalidMessageFormat                                CauseValue = 65
+	CauseVersionNotSupportedByNextPeer                       CauseValue = 66
+	CauseInvalidLength                                       CauseValue = 67
+	CauseServiceNotSupported                                 CauseValue = 68
+	CauseMandatoryIEIncorrect                                CauseValue = 69
+	CauseMandatoryIEMissing                                  CauseValue = 70
+	CauseSystemFailure                                       CauseValue = 72
+	CauseNoResourcesAvailable                                CauseValue = 73
+	CauseSemanticErrorInTheTFTOperation                      CauseValue = 74
+	CauseSyntacticErrorInTheTFTOperation                     CauseValue = 75
+	CauseSemanticErrorsInPacketFilters                       CauseValue = 76
+	CauseSyntacticErrorsInPacketFilters                      CauseValue = 77
+	CauseMissingOrUnknownAPN                                 CauseValue = 78
+	CauseGREKeyNotFound                                      CauseValue = 80
+	CauseRelocationFailure                                   CauseValue = 81
+	CauseDeniedInRAT                                         CauseValue = 82
+	CausePreferredPDNTypeNotSupported                        CauseValue = 83
+	CauseAllDynamicAddressesAreOccupied                      CauseValue = 84
+	CauseUEContextWithoutTFTAlreadyActivated                 CauseValue = 85
+	CauseProtocolTypeNotSupported                            CauseValue = 86
+	CauseUENotResponding                                     CauseValue = 87
+	CauseUERefuses                                           CauseValue = 88
+	CauseServiceDenied                                       CauseValue = 89
+	CauseUnableToPageUE                                      CauseValue = 90
+	CauseNoMemoryAvailable                                   CauseValue = 91
+	CauseUserAuthenticationFailed                            CauseValue = 92
+	CauseAPNAccessDeniedNoSubscription                       CauseValue = 93
+	CauseRequestRejectedReasonNotSpecified                   CauseValue = 94
+	CausePTMSISignatureMismatch                              CauseValue = 95
+	CauseIMSIIMEINotKnown                                    CauseValue = 96
+	CauseSemanticErrorInTheTADOperation                      CauseValue = 97
+	CauseSyntacticErrorInTheTADOperation                     CauseValue = 98
+	CauseRemotePeerNotResponding                             CauseValue = 100
+	CauseCollisionWithNetworkInitiatedRequest                CauseValue = 101
+	CauseUnableToPageUEDueToSuspension                       CauseValue = 102
+	CauseConditionalIEMissing                                CauseValue = 103
+	CauseAPNRestrictionTypeIncompatible                      CauseValue = 104
+	CauseInvalidOverallLengthOfTriggeredResponseAndPiggyback CauseValue = 105
+	CauseDataForwardingNotSupported                          CauseValue = 106
+	CauseInvalidReplyFromRemotePeer                          CauseValue = 107
+	CauseFallbackToGTPv1                                     CauseValue = 108
+	CauseInvalidPeer                                         CauseValue = 109
+	CauseTemporarilyRejectedDueToMobilityProcedureInProgress CauseValue = 110
+	CauseModificationsNotLimitedToS1UBearers                 CauseValue = 111
+	CauseRequestRejectedForAPMIPv6Reason                     CauseValue = 112
+	CauseAPNCongestion                                       CauseValue = 113
+	CauseBearerHandlingNotSupported                          CauseValue = 114
+	CauseUEAlreadyReattached                                 CauseValue = 115
+	CauseMultiplePDNConnectionsForAGivenAPNNotAllowed        CauseValue = 116
+	CauseTargetAccessRestrictedForTheSubscriber              CauseValue = 117
+	CauseMMESGSNRefusesDueToVPLMNPolicy                      CauseValue = 119
+	CauseGTPCEntityCongestion                                CauseValue = 120
+	CauseLateOverlappingRequest                              CauseValue = 121
+	CauseTimedOutRequest                                     CauseValue = 122
+	CauseUEIsTemporarilyNotReachableDueToPowerSaving         CauseValue = 123
+	CauseRelocationFailureDueToNASMessageRedirection         CauseValue = 124
+	CauseUENotAuthorisedByOCSOrExternalAAAServer             CauseValue = 125
+	CauseMultipleAccessesToAPDNConnectionNotAllowed          CauseValue = 126
+	CauseRequestRejectedDueToUECapability                    CauseValue = 127
+	CauseS1UPathFailure                                      CauseValue = 128
+	Cause5GCNotAllowed                                       CauseValue = 129
+)
+
+var causeNames = map[CauseValue]string{
+	2:   "Local Detach",
+	3:   "Complete Detach",
+	4:   "RAT changed from 3GPP to Non-3GPP",
+	5:   "ISR deactivation",
+	6:   "Error Indication received from RNC/eNodeB/S4-SGSN/MME",
+	7:   "IMSI Detach Only",
+	8:   "Reactivation Requested",
+	9:   "PDN reconnection to this APN disallowed",
+	10:  "Access changed from Non-3GPP to 3GPP",
+	11:  "PDN connection inactivity timer expires",
+	12:  "PGW not responding",
+	13:  "Network Failure",
+	14:  "QoS parameter mismatch",
+	15:  "EPS to 5GS Mobility",
+	16:  "Request accepted",
+	17:  "Request accepted partially",
+	18:  "New PDN type due to network preference",
+	19:  "New PDN type due to single address bearer only",
+	64:  "Context Not Found",
+	65:  "Invalid Message Format",
+	66:  "Version not supported by next peer",
+	67:  "Invalid length",
+	68:  "Service not supported",
+	69:  "Mandatory IE incorrect",
+	70:  "Mandatory IE missing",
+	72:  "System failure",
+	73:  "No resources available",
+	74:  "Semantic error in the TFT operation",
+	75:  "Syntactic error in the TFT operation",
+	76:  "Semantic errors in packet filter(s)",
+	77:  "Syntactic errors in packet filter(s)",
+	78:  "Missing or unknown APN",
+	80:  "GRE key not found",
+	81:  "Relocation failure",
+	82:  "Denied in RAT",
+	83:  "Preferred PDN type not supported",
+	84:  "All dynamic addresses are occupied",
+	85:  "UE context without TFT already activated",
+	86:  "Protocol type not supported",
+	87:  "UE not responding",
+	88:  "UE refuses",
+	89:  "Service denied",
+	90:  "Unable to page UE",
+	91:  "No memory available",
+	92:  "User authentication failed",
+	93:  "APN access denied - no subscription",
+	94:  "Request rejected (reason not specified)",
+	95:  "P-TMSI Signature mismatch",
+	96:  "IMSI/IMEI not known",
+	97:  "Semantic error in the TAD operation",
+	98:  "Syntactic error in the TAD operation",
+	100: "Remote peer not responding",
+	101: "Collision with network initiated request",
+	102: "Unable to page UE due to Suspension",
+	103: "Conditional IE missing",
+	104: "APN Restriction type Incompatible with currently active PDN connection",
+	105: "Invalid overall length of the triggered response message and a piggybacked initial message",
+	106: "Data forwarding not supported",
+	107: "Invalid reply from remote peer",
+	108: "Fallback to GTPv1",
+	109: "Invalid peer",
+	110: "Temporarily rejected due to handover/TAU/RAU procedure in progress",
+	111: "Modifications not limited to S1-U bearers",
+	112: "Request rejected for a PMIPv6 reason",
+	113: "APN Congestion",
+	114: "Bearer handling not supported",
+	115: "UE already re-attached",
+	116: "Multiple PDN connections for a given APN not allowed",
+	117: "Target access restricted for the subscriber",
+	119: "MME/SGSN refuses due to VPLMN Policy",
+	120: "GTP-C Entity Congestion",
+	121: "Late Overlapping Request",
+	122: "Timed out Request",
+	123: "UE is temporarily not reachable due to power saving",
+	124: "Relocation failure due to NAS message redirection",
+	125: "UE not authorised by OCS or external AAA Server",
+	126: "Multiple accesses to a PDN connection not allowed",
+	127: "Request rejected due to UE capability",
+	128: "S1-U Path Failure",
+	129: "5GC not allowed",
+}
+
+// String returns the name of the cause value from TS 29.274 Table 8.4-1, or
+// "Spare" if the value is not assigned
+func (value CauseValue) String() string {
+	if name, valueIsAssigned := causeNames[value]; valueIsAssigned {
+		return name
+	}
+
+	return "Spare"
+}
+
+// IsAccepted returns true if the cause value is in the range used to
+// indicate acceptance in a response or triggered request
+func (value CauseValue) IsAccepted() bool {
+	return value >= 16 && value <= 63
+}
+
+// IsRejection returns true if the cause value is in the range used to
+// indicate rejection in a response or triggered request.  Values 240 to 255
+// are spare, and are not rejections.
+func (value CauseValue) IsRejection() bool {
+	return value >= 64 && value <= 239
+}
+
+// OffendingIE identifies the IE that caused a rejection, when it is
+// included in a Cause IE
+type OffendingIE struct {
+	Type           IEType
+	InstanceNumber uint8
+}
+
+// TypedCause is a structured version of a Cause IE.  PDNConnectionIEError
+// is the PCE flag, BearerContextIEError is the BCE flag, and CauseSource
+// is the CS flag, which is set when the cause originated from a remote
+// node rather than the sender.  OffendingIE is nil if the IE does not
+// include the offending IE fields.
+type TypedCause struct {
+	Value                CauseValue
+	PDNConnectionIEError bool
+	BearerContextIEError bool
+	CauseSource          bool
+	OffendingIE          *OffendingIE
+}
+
+// IsAccepted returns true if the cause value indicates acceptance
+func (cause *TypedCause) IsAccepted() bool {
+	return cause.Value.IsAccepted()
+}
+
+// IsRejection returns true if the cause value indicates rejection
+func (cause *TypedCause) IsRejection() bool {
+	return cause.Value.IsRejection()
+}
+
+// ToIE creates an IE from the structured version of a Cause, and
+// panics if there is an error
+func (cause *TypedCause) ToIE() *IE {
+	ie, err := cause.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (cause *TypedCause) ToIEErrorable() (*IE, error) {
+	flags := byte(0)
+
+	if cause.PDNConnectionIEError {
+		flags |= 0x04
+	}
+	if cause.BearerContextIEError {
+		flags |= 0x02
+	}
+	if cause.CauseSource {
+		flags |= 0x01
+	}
+
+	if cause.OffendingIE == nil {
+		return NewIEWithRawDataErrorable(Cause, []byte{byte(cause.Value), flags})
+	}
+
+	if cause.OffendingIE.InstanceNumber > 0x0f {
+		return nil, fmt.Errorf("offending IE instance number (%d) exceeds maximum (15)", cause.OffendingIE.InstanceNumber)
+	}
+
+	return NewIEWithRawDataErrorable(Cause, []byte{
+		byte(cause.Value), flags,
+		byte(cause.OffendingIE.Type), 0x00, 0x00, cause.OffendingIE.InstanceNumber,
+	})
+}
+
+func makeTypedCause(fromIE *IE) (*TypedCause, error) {
+	if fromIE.Type != Cause {
+		return nil, fmt.Errorf("supplied IE is not of type Cause")
+	}
+
+	data := fromIE.Data
+
+	if len(data) != 2 && len(data) != 6 {
+		return nil, fmt.Errorf("length of IE data (%d) is not correct for Cause type", len(data))
+	}
+
+	cause := &TypedCause{
+		Value:                CauseValue(data[0]),
+		PDNConnectionIEError: data[1]&0x04 != 0,
+		BearerContextIEError: data[1]&0x02 != 0,
+		CauseSource:          data[1]&0x01 != 0,
+	}
+
+	if len(data) == 6 {
+		cause.OffendingIE = &OffendingIE{
+			Type:           IEType(data[2]),
+			InstanceNumber: data[5] & 0x0f,
+		}
+	}
+
+	return cause, nil
+}
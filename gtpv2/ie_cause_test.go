@@ -0,0 +1,104 @@
+package gtpv2
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type TypedCauseComparable struct {
+	cause             *TypedCause
+	expectedDataBytes []byte
+}
+
+func TestTypedCause(t *testing.T) {
+	testCases := []TypedCauseComparable{
+		{
+			cause:             &TypedCause{Value: CauseRequestAccepted},
+			expectedDataBytes: []byte{0x10, 0x00},
+		},
+		{
+			cause:             &TypedCause{Value: CauseContextNotFound, PDNConnectionIEError: true, CauseSource: true},
+			expectedDataBytes: []byte{0x40, 0x05},
+		},
+		{
+			cause: &TypedCause{
+				Value:                CauseMandatoryIEMissing,
+				BearerContextIEError: true,
+				OffendingIE:          &OffendingIE{Type: FTEID, InstanceNumber: 2},
+			},
+			expectedDataBytes: []byte{0x46, 0x02, 0x57, 0x00, 0x00, 0x02},
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := testCase.cause.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedCause] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedCause] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typedCause, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedCause] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if diff := deep.Equal(testCase.cause, typedCause.(*TypedCause)); diff != nil {
+			t.Errorf("[TestTypedCause] on test number [%d]: %s", testNumber, diff)
+		}
+	}
+}
+
+func TestTypedCauseInvalidCases(t *testing.T) {
+	for _, data := range [][]byte{{}, {0x10}, {0x10, 0x00, 0x57}, {0x10, 0x00, 0x57, 0x00, 0x00, 0x00, 0x00}} {
+		if _, err := NewIEWithRawData(Cause, data).TypedDataErrorable(); err == nil {
+			t.Errorf("[TestTypedCauseInvalidCases] expected error on TypedData for data (%02x), but got none", data)
+		}
+	}
+
+	if _, err := (&TypedCause{Value: CauseInvalidLength, OffendingIE: &OffendingIE{Type: APN, InstanceNumber: 16}}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedCauseInvalidCases] expected error on ToIEErrorable for instance number 16, but got none")
+	}
+}
+
+func TestCauseValueClassification(t *testing.T) {
+	testCases := []struct {
+		value             CauseValue
+		expectedName      string
+		expectedAccepted  bool
+		expectedRejection bool
+	}{
+		{CauseLocalDetach, "Local Detach", false, false},
+		{CauseRequestAccepted, "Request accepted", true, false},
+		{CauseRequestAcceptedPartially, "Request accepted partially", true, false},
+		{CauseContextNotFound, "Context Not Found", false, true},
+		{CauseMandatoryIEMissing, "Mandatory IE missing", false, true},
+		{CauseValue(71), "Spare", false, true},
+		{CauseValue(239), "Spare", false, true},
+		{CauseValue(240), "Spare", false, false},
+		{CauseValue(255), "Spare", false, false},
+	}
+
+	for _, testCase := range testCases {
+		if got := testCase.value.String(); got != testCase.expectedName {
+			t.Errorf("[TestCauseValueClassification] for cause value (%d) expected name (%s), got (%s)", testCase.value, testCase.expectedName, got)
+		}
+
+		cause := &TypedCause{Value: testCase.value}
+
+		if cause.IsAccepted() != testCase.expectedAccepted {
+			t.Errorf("[TestCauseValueClassification] for cause value (%d) expected IsAccepted() = %t", testCase.value, testCase.expectedAccepted)
+		}
+
+		if cause.IsRejection() != testCase.expectedRejection {
+			t.Errorf("[TestCauseValueClassification] for cause value (%d) expected IsRejection() = %t", testCase.value, testCase.expectedRejection)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package gtpv2
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/tbcd"
+)
+
+// TypedMEI is a structured version of a Mobile Equipment Identity IE.
+// AsString is either a 15 digit IMEI or a 16 digit IMEISV.
+type TypedMEI struct {
+	AsString string
+}
+
+// ToIE creates an IE from the structured version of an MEI, and
+// panics if there is an error
+func (mei *TypedMEI) ToIE() *IE {
+	ie, err := mei.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (mei *TypedMEI) ToIEErrorable() (*IE, error) {
+	if len(mei.AsString) != 15 && len(mei.AsString) != 16 {
+		return nil, fmt.Errorf("MEI contains (%d) digits, but must contain 15 or 16", len(mei.AsString))
+	}
+
+	data, err := tbcd.Encode(mei.AsString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MEI: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(MEI, data)
+}
+
+func makeTypedMEI(fromIE *IE) (*TypedMEI, error) {
+	if fromIE.Type != MEI {
+		return nil, fmt.Errorf("supplied IE is not of type MEI")
+	}
+
+	if len(fromIE.Data) != 8 {
+		return nil, fmt.Errorf("length of IE data is not correct for MEI type")
+	}
+
+	asString, err := tbcd.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MEI encode value: %s", err)
+	}
+
+	if len(asString) != 15 && len(asString) != 16 {
+		return nil, fmt.Errorf("MEI contains (%d) digits, but must contain 15 or 16", len(asString))
+	}
+
+	return &TypedMEI{AsString: asString}, nil
+}
+
+// TypedMSISDN is a structured version of an MSISDN IE.  AsString is the
+// E.164 number, including the country code, as a string of digits.
+type TypedMSISDN struct {
+	AsString string
+}
+
+// ToIE creates an IE from the structured version of an MSISDN, and
+// panics if there is an error
+func (msisdn *TypedMSISDN) ToIE() *IE {
+	ie, err := msisdn.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (msisdn *TypedMSISDN) ToIEErrorable() (*IE, error) {
+	if len(msisdn.AsString) > 15 {
+		return nil, fmt.Errorf("MSISDN contains (%d) digits, but must contain no more than 15", len(msisdn.AsString))
+	}
+
+	data, err := tbcd.Encode(msisdn.AsString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MSISDN: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(MSISDN, data)
+}
+
+func makeTypedMSISDN(fromIE *IE) (*TypedMSISDN, error) {
+	if fromIE.Type != MSISDN {
+		return nil, fmt.Errorf("supplied IE is not of type MSISDN")
+	}
+
+	if len(fromIE.Data) < 1 || len(fromIE.Data) > 8 {
+		return nil, fmt.Errorf("length of IE data is not correct for MSISDN type")
+	}
+
+	asString, err := tbcd.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MSISDN encode value: %s", err)
+	}
+
+	return &TypedMSISDN{AsString: asString}, nil
+}
@@ -0,0 +1,99 @@
+package gtpv2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypedMEI(t *testing.T) {
+	testCases := []struct {
+		mei               *TypedMEI
+		expectedDataBytes []byte
+	}{
+		{
+			mei:               &TypedMEI{AsString: "490154203237518"},
+			expectedDataBytes: []byte{0x94, 0x10, 0x45, 0x02, 0x23, 0x73, 0x15, 0xf8},
+		},
+		{
+			mei:               &TypedMEI{AsString: "3520990017614823"},
+			expectedDataBytes: []byte{0x53, 0x02, 0x99, 0x00, 0x71, 0x16, 0x84, 0x32},
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := testCase.mei.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedMEI] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedMEI] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typed, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedMEI] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if got := typed.(*TypedMEI).AsString; got != testCase.mei.AsString {
+			t.Errorf("[TestTypedMEI] on test number [%d] expected AsString = (%s), got (%s)", testNumber, testCase.mei.AsString, got)
+		}
+	}
+
+	for _, invalid := range []string{"49015420323751", "49015420323751a", "49015420323751801"} {
+		if _, err := (&TypedMEI{AsString: invalid}).ToIEErrorable(); err == nil {
+			t.Errorf("[TestTypedMEI] expected error on ToIEErrorable for (%s), but got none", invalid)
+		}
+	}
+
+	_, err := (&TypedMEI{AsString: "4901542032a7518"}).ToIEErrorable()
+	if err == nil {
+		t.Errorf("[TestTypedMEI] expected error on ToIEErrorable for invalid digit, but got none")
+	} else if !strings.Contains(err.Error(), "position (11)") {
+		t.Errorf("[TestTypedMEI] expected error on ToIEErrorable to identify position (11), got (%s)", err.Error())
+	}
+
+	_, err = NewIEWithRawData(MEI, []byte{0x94, 0x10, 0x45, 0x02, 0x23, 0x73, 0xb5, 0xf8}).TypedDataErrorable()
+	if err == nil {
+		t.Errorf("[TestTypedMEI] expected error on TypedData for invalid digit, but got none")
+	} else if !strings.Contains(err.Error(), "position (14)") {
+		t.Errorf("[TestTypedMEI] expected error on TypedData to identify position (14), got (%s)", err.Error())
+	}
+}
+
+func TestTypedMSISDN(t *testing.T) {
+	msisdn := &TypedMSISDN{AsString: "15551234567"}
+	expectedDataBytes := []byte{0x51, 0x55, 0x21, 0x43, 0x65, 0xf7}
+
+	ie, err := msisdn.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedMSISDN] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedMSISDN] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedMSISDN] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if got := typed.(*TypedMSISDN).AsString; got != msisdn.AsString {
+		t.Errorf("[TestTypedMSISDN] expected AsString = (%s), got (%s)", msisdn.AsString, got)
+	}
+
+	if _, err := (&TypedMSISDN{AsString: "+15551234567"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedMSISDN] expected error on ToIEErrorable for leading '+', but got none")
+	} else if !strings.Contains(err.Error(), "position (1)") {
+		t.Errorf("[TestTypedMSISDN] expected error on ToIEErrorable to identify position (1), got (%s)", err.Error())
+	}
+
+	if _, err := (&TypedMSISDN{AsString: "1555123456789012"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedMSISDN] expected error on ToIEErrorable for 16 digits, but got none")
+	}
+}
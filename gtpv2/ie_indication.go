@@ -0,0 +1,244 @@
+package gtpv2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IndicationFlag identifies a single flag in an Indication IE.  The value is
+// the zero-based bit offset of the flag from the most significant bit of the
+// first octet of the IE data (that is, octet 5 of the IE).  So, the flag in bit
+// 8 of octet 5 is 0, the flag in bit 1 of octet 5 is 7, and the flag in bit 8 of
+// octet 6 is 8.
+type IndicationFlag uint16
+
+// Indication flags (TS 29.274 Figure 8.12-1), in octet and bit order
+const (
+	// octet 5
+	IndicationDAF IndicationFlag = iota
+	IndicationDTF
+	IndicationHI
+	IndicationDFI
+	IndicationOI
+	IndicationISRSI
+	IndicationISRAI
+	IndicationSGWCI
+
+	// octet 6
+	IndicationSQCI
+	IndicationUIMSI
+	IndicationCFSI
+	IndicationCRSI
+	IndicationP
+	IndicationPT
+	IndicationSI
+	IndicationMSV
+
+	// octet 7
+	IndicationRetLoc
+	IndicationPBIC
+	IndicationSRNI
+	IndicationS6AF
+	IndicationS4AF
+	IndicationMBMDT
+	IndicationISRAU
+	IndicationCCRSI
+
+	// octet 8
+	IndicationCPRAI
+	IndicationARRL
+	IndicationPPOFF
+	IndicationPPON
+	IndicationPPSI
+	IndicationCSFBI
+	IndicationCLII
+	IndicationCPSR
+
+	// octet 9
+	IndicationNSI
+	IndicationUASI
+	IndicationDTCI
+	IndicationBDWI
+	IndicationPSCI
+	IndicationPCRI
+	IndicationAOSI
+	IndicationAOPI
+
+	// octet 10
+	IndicationROAAI
+	IndicationEPCOSI
+	IndicationCPOPCI
+	IndicationPMTSMI
+	IndicationS11TF
+	IndicationPNSI
+	IndicationUNACCSI
+	IndicationWPMSI
+
+	// octet 11
+	Indication5GSNN26
+	IndicationREPREFI
+	Indication5GSIWK
+	IndicationEEVRSI
+	IndicationLTEMUI
+	IndicationLTEMPI
+	IndicationENBCRSI
+	IndicationTSPCMI
+
+	// octet 12
+	IndicationCSRMFI
+	IndicationMTEDTN
+	IndicationMTEDTA
+	IndicationN5GNMI
+	Indication5GCNRS
+	Indication5GCNRI
+	Indication5SRHOI
+	IndicationETHPDN
+)
+
+// IndicationPPEI shares a bit with IndicationPPON.  The flag is PPON in
+// requests and PPEI in responses.
+const IndicationPPEI = IndicationPPON
+
+var indicationFlagNames = []string{
+	"DAF", "DTF", "HI", "DFI", "OI", "ISRSI", "ISRAI", "SGWCI",
+	"SQCI", "UIMSI", "CFSI", "CRSI", "P", "PT", "SI", "MSV",
+	"RetLoc", "PBIC", "SRNI", "S6AF", "S4AF", "MBMDT", "ISRAU", "CCRSI",
+	"CPRAI", "ARRL", "PPOFF", "PPON/PPEI", "PPSI", "CSFBI", "CLII", "CPSR",
+	"NSI", "UASI", "DTCI", "BDWI", "PSCI", "PCRI", "AOSI", "AOPI",
+	"ROAAI", "EPCOSI", "CPOPCI", "PMTSMI", "S11TF", "PNSI", "UNACCSI", "WPMSI",
+	"5GSNN26", "REPREFI", "5GSIWK", "EEVRSI", "LTEMUI", "LTEMPI", "ENBCRSI", "TSPCMI",
+	"CSRMFI", "MTEDTN", "MTEDTA", "N5GNMI", "5GCNRS", "5GCNRI", "5SRHOI", "ETHPDN",
+}
+
+// String returns the name of the flag from TS 29.274.  A flag without a
+// known name is returned as its position in the IE (e.g., "Octet13Bit8").
+func (flag IndicationFlag) String() string {
+	if int(flag) < len(indicationFlagNames) {
+		return indicationFlagNames[flag]
+	}
+
+	return fmt.Sprintf("Octet%dBit%d", flag.octetIndex()+5, 8-flag%8)
+}
+
+func (flag IndicationFlag) octetIndex() int {
+	return int(flag / 8)
+}
+
+func (flag IndicationFlag) mask() byte {
+	return 0x80 >> (flag % 8)
+}
+
+// TypedIndication is a structured version of an Indication IE.  It is a set
+// of IndicationFlags.  Flags that are set but have no IndicationFlag constant
+// (for example, when the IE is received from a peer implementing a later
+// release) are preserved, so an IE that is decoded and then re-encoded is
+// unchanged, apart from trailing octets with no flags set.  The zero value
+// has no flags set.
+type TypedIndication struct {
+	octets []byte
+}
+
+// NewTypedIndication returns a TypedIndication with the provided flags set
+func NewTypedIndication(flags ...IndicationFlag) *TypedIndication {
+	indication := &TypedIndication{}
+	for _, flag := range flags {
+		indication.Set(flag)
+	}
+
+	return indication
+}
+
+// IsSet returns true if the flag is set
+func (indication *TypedIndication) IsSet(flag IndicationFlag) bool {
+	if flag.octetIndex() >= len(indication.octets) {
+		return false
+	}
+
+	return indication.octets[flag.octetIndex()]&flag.mask() != 0
+}
+
+// Set sets the flag
+func (indication *TypedIndication) Set(flag IndicationFlag) {
+	for flag.octetIndex() >= len(indication.octets) {
+		indication.octets = append(indication.octets, 0)
+	}
+
+	indication.octets[flag.octetIndex()] |= flag.mask()
+}
+
+// Clear clears the flag
+func (indication *TypedIndication) Clear(flag IndicationFlag) {
+	if flag.octetIndex() < len(indication.octets) {
+		indication.octets[flag.octetIndex()] &^= flag.mask()
+	}
+}
+
+// SetFlags returns each flag that is set, in octet and bit order
+func (indication *TypedIndication) SetFlags() []IndicationFlag {
+	flags := make([]IndicationFlag, 0)
+
+	for flag := IndicationFlag(0); int(flag) < len(indication.octets)*8; flag++ {
+		if indication.IsSet(flag) {
+			flags = append(flags, flag)
+		}
+	}
+
+	return flags
+}
+
+// String returns the names of the set flags, separated by '|'
+func (indication *TypedIndication) String() string {
+	flags := indication.SetFlags()
+	names := make([]string, len(flags))
+
+	for i, flag := range flags {
+		names[i] = flag.String()
+	}
+
+	return strings.Join(names, "|")
+}
+
+// ToIE creates an IE from the structured version of an Indication, and
+// panics if there is an error
+func (indication *TypedIndication) ToIE() *IE {
+	ie, err := indication.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing.  The IE data includes only as many
+// octets as are needed for the highest flag that is set, but always
+// includes at least one octet.
+func (indication *TypedIndication) ToIEErrorable() (*IE, error) {
+	length := len(indication.octets)
+	for length > 1 && indication.octets[length-1] == 0 {
+		length--
+	}
+
+	data := []byte{0x00}
+	if length > 0 {
+		data = append([]byte{}, indication.octets[:length]...)
+	}
+
+	return NewIEWithRawDataErrorable(Indication, data)
+}
+
+func makeTypedIndication(fromIE *IE) (*TypedIndication, error) {
+	if fromIE.Type != Indication {
+		return nil, fmt.Errorf("supplied IE is not of type Indication")
+	}
+
+	if len(fromIE.Data) == 0 {
+		return nil, fmt.Errorf("length of IE data is not correct for Indication type")
+	}
+
+	octets := make([]byte, len(fromIE.Data))
+	copy(octets, fromIE.Data)
+
+	return &TypedIndication{octets: octets}, nil
+}
@@ -0,0 +1,104 @@
+package gtpv2
+
+import (
+	"testing"
+)
+
+func TestTypedIndication(t *testing.T) {
+	testCases := []struct {
+		flags             []IndicationFlag
+		expectedDataBytes []byte
+		expectedString    string
+	}{
+		{
+			flags:             []IndicationFlag{},
+			expectedDataBytes: []byte{0x00},
+			expectedString:    "",
+		},
+		{
+			flags:             []IndicationFlag{IndicationDAF, IndicationSGWCI},
+			expectedDataBytes: []byte{0x81},
+			expectedString:    "DAF|SGWCI",
+		},
+		{
+			flags:             []IndicationFlag{IndicationMSV, IndicationOI},
+			expectedDataBytes: []byte{0x08, 0x01},
+			expectedString:    "OI|MSV",
+		},
+		{
+			flags:             []IndicationFlag{IndicationPPEI, IndicationETHPDN, Indication5GSIWK},
+			expectedDataBytes: []byte{0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x20, 0x01},
+			expectedString:    "PPON/PPEI|5GSIWK|ETHPDN",
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := NewTypedIndication(testCase.flags...).ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedIndication] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedIndication] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typed, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedIndication] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		indication := typed.(*TypedIndication)
+
+		for _, flag := range testCase.flags {
+			if !indication.IsSet(flag) {
+				t.Errorf("[TestTypedIndication] on test number [%d] expected flag (%s) to be set, but it is not", testNumber, flag)
+			}
+		}
+
+		if len(indication.SetFlags()) != len(testCase.flags) {
+			t.Errorf("[TestTypedIndication] on test number [%d] expected (%d) flags set, got (%d)", testNumber, len(testCase.flags), len(indication.SetFlags()))
+		}
+
+		if indication.String() != testCase.expectedString {
+			t.Errorf("[TestTypedIndication] on test number [%d] expected String() = (%s), got (%s)", testNumber, testCase.expectedString, indication.String())
+		}
+	}
+}
+
+func TestTypedIndicationPreservesUnknownFlags(t *testing.T) {
+	received := []byte{0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x81, 0x00}
+
+	typed, err := NewIEWithRawData(Indication, received).TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedIndicationPreservesUnknownFlags] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	indication := typed.(*TypedIndication)
+
+	if expected := "DTF|Octet13Bit8|Octet13Bit1"; indication.String() != expected {
+		t.Errorf("[TestTypedIndicationPreservesUnknownFlags] expected String() = (%s), got (%s)", expected, indication.String())
+	}
+
+	indication.Set(IndicationHI)
+	indication.Clear(IndicationDTF)
+	indication.Clear(IndicationFlag(200))
+
+	ie := indication.ToIE()
+
+	if err := compareByteArrays([]byte{0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x81}, ie.Data); err != nil {
+		t.Errorf("[TestTypedIndicationPreservesUnknownFlags] data in IE from ToIE does not match expected: %s", err.Error())
+	}
+
+	if _, err := NewIEWithRawData(Indication, []byte{}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedIndicationPreservesUnknownFlags] expected error on TypedData for empty data, but got none")
+	}
+
+	var zero TypedIndication
+	if zero.IsSet(IndicationDAF) || zero.String() != "" {
+		t.Errorf("[TestTypedIndicationPreservesUnknownFlags] expected zero value to have no flags set")
+	}
+}
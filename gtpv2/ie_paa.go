@@ -0,0 +1,208 @@
+package gtpv2
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// PDNTypeValue is the PDN type carried in the PAA and PDN Type IEs.  It
+// is actually a uint3 value.
+type PDNTypeValue uint8
+
+// PDN type values (TS 29.274 sections 8.14 and 8.34)
+const (
+	PDNTypeIPv4     PDNTypeValue = 1
+	PDNTypeIPv6     PDNTypeValue = 2
+	PDNTypeIPv4v6   PDNTypeValue = 3
+	PDNTypeNonIP    PDNTypeValue = 4
+	PDNTypeEthernet PDNTypeValue = 5
+)
+
+var pdnTypeNames = map[PDNTypeValue]string{
+	PDNTypeIPv4:     "IPv4",
+	PDNTypeIPv6:     "IPv6",
+	PDNTypeIPv4v6:   "IPv4v6",
+	PDNTypeNonIP:    "Non-IP",
+	PDNTypeEthernet: "Ethernet",
+}
+
+// String returns the name of the PDN type, or "Reserved" if the value
+// is not assigned
+func (value PDNTypeValue) String() string {
+	if name, valueIsAssigned := pdnTypeNames[value]; valueIsAssigned {
+		return name
+	}
+
+	return "Reserved"
+}
+
+// TypedPAA is a structured version of a PDN Address Allocation IE.  Which
+// of IPv4Address and IPv6Prefix must be set depends on PDNType: IPv4
+// requires only IPv4Address; IPv6 requires only IPv6Prefix; IPv4v6 requires
+// both; Non-IP and Ethernet require neither.  An unspecified address
+// (0.0.0.0 or ::) may be used to request dynamic allocation.  IPv6Prefix
+// carries both the IPv6 address and the prefix length, and its address
+// is not masked to the prefix length.
+type TypedPAA struct {
+	PDNType     PDNTypeValue
+	IPv4Address net.IP
+	IPv6Prefix  netip.Prefix
+}
+
+// ToIE creates an IE from the structured version of a PAA, and
+// panics if there is an error
+func (paa *TypedPAA) ToIE() *IE {
+	ie, err := paa.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+func (paa *TypedPAA) validate() error {
+	needsIPv4 := paa.PDNType == PDNTypeIPv4 || paa.PDNType == PDNTypeIPv4v6
+	needsIPv6 := paa.PDNType == PDNTypeIPv6 || paa.PDNType == PDNTypeIPv4v6
+
+	switch paa.PDNType {
+	case PDNTypeIPv4, PDNTypeIPv6, PDNTypeIPv4v6, PDNTypeNonIP, PDNTypeEthernet:
+	default:
+		return fmt.Errorf("PAA PDN type (%d) is not valid", paa.PDNType)
+	}
+
+	if needsIPv4 {
+		if paa.IPv4Address == nil || !ipAddressIsIPv4(paa.IPv4Address) {
+			return fmt.Errorf("PAA PDN type %s requires an IPv4 address", paa.PDNType)
+		}
+	} else if paa.IPv4Address != nil {
+		return fmt.Errorf("PAA PDN type %s must not have an IPv4 address", paa.PDNType)
+	}
+
+	if needsIPv6 {
+		if !paa.IPv6Prefix.IsValid() || !paa.IPv6Prefix.Addr().Is6() || paa.IPv6Prefix.Addr().Is4In6() {
+			return fmt.Errorf("PAA PDN type %s requires an IPv6 prefix", paa.PDNType)
+		}
+	} else if paa.IPv6Prefix.IsValid() {
+		return fmt.Errorf("PAA PDN type %s must not have an IPv6 prefix", paa.PDNType)
+	}
+
+	return nil
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (paa *TypedPAA) ToIEErrorable() (*IE, error) {
+	if err := paa.validate(); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 1, 22)
+	data[0] = byte(paa.PDNType)
+
+	if paa.IPv6Prefix.IsValid() {
+		ipv6Address := paa.IPv6Prefix.Addr().As16()
+		data = append(data, byte(paa.IPv6Prefix.Bits()))
+		data = append(data, ipv6Address[:]...)
+	}
+
+	if paa.IPv4Address != nil {
+		data = append(data, paa.IPv4Address.To4()...)
+	}
+
+	return NewIEWithRawDataErrorable(PAA, data)
+}
+
+func makeTypedPAA(fromIE *IE) (*TypedPAA, error) {
+	if fromIE.Type != PAA {
+		return nil, fmt.Errorf("supplied IE is not of type PAA")
+	}
+
+	data := fromIE.Data
+
+	if len(data) < 1 {
+		return nil, fmt.Errorf("length of IE data is not correct for PAA type")
+	}
+
+	paa := &TypedPAA{
+		PDNType: PDNTypeValue(data[0] & 0x07),
+	}
+
+	var requiredDataLength int
+
+	switch paa.PDNType {
+	case PDNTypeIPv4:
+		requiredDataLength = 5
+	case PDNTypeIPv6:
+		requiredDataLength = 18
+	case PDNTypeIPv4v6:
+		requiredDataLength = 22
+	case PDNTypeNonIP, PDNTypeEthernet:
+		requiredDataLength = 1
+	default:
+		return nil, fmt.Errorf("PAA PDN type (%d) is not valid", paa.PDNType)
+	}
+
+	if len(data) != requiredDataLength {
+		return nil, fmt.Errorf("length of IE data (%d) is not correct for PAA with PDN type %s", len(data), paa.PDNType)
+	}
+
+	if paa.PDNType == PDNTypeIPv6 || paa.PDNType == PDNTypeIPv4v6 {
+		if data[1] > 128 {
+			return nil, fmt.Errorf("PAA IPv6 prefix length (%d) exceeds 128", data[1])
+		}
+
+		paa.IPv6Prefix = netip.PrefixFrom(netip.AddrFrom16(*(*[16]byte)(data[2:18])), int(data[1]))
+	}
+
+	switch paa.PDNType {
+	case PDNTypeIPv4:
+		paa.IPv4Address = net.IP(data[1:5])
+	case PDNTypeIPv4v6:
+		paa.IPv4Address = net.IP(data[18:22])
+	}
+
+	return paa, nil
+}
+
+// TypedPDNType is a structured version of a PDN Type IE
+type TypedPDNType struct {
+	Value PDNTypeValue
+}
+
+// ToIE creates an IE from the structured version of a PDN Type, and
+// panics if there is an error
+func (pdnType *TypedPDNType) ToIE() *IE {
+	ie, err := pdnType.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (pdnType *TypedPDNType) ToIEErrorable() (*IE, error) {
+	if pdnType.Value > 0x07 {
+		return nil, fmt.Errorf("PDN type value (%d) exceeds maximum 3-bit value", pdnType.Value)
+	}
+
+	return NewIEWithRawDataErrorable(PDNType, []byte{byte(pdnType.Value)})
+}
+
+func makeTypedPDNType(fromIE *IE) (*TypedPDNType, error) {
+	if fromIE.Type != PDNType {
+		return nil, fmt.Errorf("supplied IE is not of type PDN Type")
+	}
+
+	if len(fromIE.Data) != 1 {
+		return nil, fmt.Errorf("length of IE data is not correct for PDN Type type")
+	}
+
+	return &TypedPDNType{
+		Value: PDNTypeValue(fromIE.Data[0] & 0x07),
+	}, nil
+}
@@ -0,0 +1,132 @@
+package gtpv2
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+)
+
+type TypedPAAComparable struct {
+	paa               *TypedPAA
+	expectedDataBytes []byte
+}
+
+func TestTypedPAA(t *testing.T) {
+	testCases := []TypedPAAComparable{
+		{
+			paa:               &TypedPAA{PDNType: PDNTypeIPv4, IPv4Address: net.ParseIP("10.1.2.3")},
+			expectedDataBytes: []byte{0x01, 0x0a, 0x01, 0x02, 0x03},
+		},
+		{
+			paa:               &TypedPAA{PDNType: PDNTypeIPv4, IPv4Address: net.IPv4zero},
+			expectedDataBytes: []byte{0x01, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			paa:               &TypedPAA{PDNType: PDNTypeIPv6, IPv6Prefix: netip.MustParsePrefix("2001:db8:1:2::1/64")},
+			expectedDataBytes: []byte{0x02, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		},
+		{
+			paa: &TypedPAA{PDNType: PDNTypeIPv4v6, IPv4Address: net.ParseIP("192.168.0.1"), IPv6Prefix: netip.MustParsePrefix("2001:db8::/56")},
+			expectedDataBytes: []byte{
+				0x03, 0x38,
+				0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0xc0, 0xa8, 0x00, 0x01,
+			},
+		},
+		{
+			paa:               &TypedPAA{PDNType: PDNTypeNonIP},
+			expectedDataBytes: []byte{0x04},
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := testCase.paa.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedPAA] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedPAA] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typedPAA, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedPAA] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		paa := typedPAA.(*TypedPAA)
+
+		if paa.PDNType != testCase.paa.PDNType {
+			t.Errorf("[TestTypedPAA] on test number [%d] expected PDNType (%s), got (%s)", testNumber, testCase.paa.PDNType, paa.PDNType)
+		}
+
+		if (paa.IPv4Address == nil) != (testCase.paa.IPv4Address == nil) || !paa.IPv4Address.Equal(testCase.paa.IPv4Address) {
+			t.Errorf("[TestTypedPAA] on test number [%d] expected IPv4Address (%s), got (%s)", testNumber, testCase.paa.IPv4Address, paa.IPv4Address)
+		}
+
+		if paa.IPv6Prefix != testCase.paa.IPv6Prefix {
+			t.Errorf("[TestTypedPAA] on test number [%d] expected IPv6Prefix (%s), got (%s)", testNumber, testCase.paa.IPv6Prefix, paa.IPv6Prefix)
+		}
+	}
+}
+
+func TestTypedPAAInvalidCases(t *testing.T) {
+	invalidPAAs := []*TypedPAA{
+		{PDNType: 0},
+		{PDNType: PDNTypeIPv4},
+		{PDNType: PDNTypeIPv4, IPv4Address: net.ParseIP("2001:db8::1")},
+		{PDNType: PDNTypeIPv4, IPv4Address: net.ParseIP("10.1.1.1"), IPv6Prefix: netip.MustParsePrefix("2001:db8::/64")},
+		{PDNType: PDNTypeIPv6},
+		{PDNType: PDNTypeIPv6, IPv6Prefix: netip.MustParsePrefix("10.0.0.0/8")},
+		{PDNType: PDNTypeIPv4v6, IPv4Address: net.ParseIP("10.1.1.1")},
+		{PDNType: PDNTypeEthernet, IPv4Address: net.ParseIP("10.1.1.1")},
+	}
+
+	for testIndex, paa := range invalidPAAs {
+		if _, err := paa.ToIEErrorable(); err == nil {
+			t.Errorf("[TestTypedPAAInvalidCases] on ToIE test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+
+	invalidData := [][]byte{
+		{},
+		{0x01, 0x0a, 0x01, 0x02},
+		{0x02, 0x40, 0x20, 0x01},
+		{0x02, 0x81, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		{0x06},
+	}
+
+	for testIndex, data := range invalidData {
+		if _, err := NewIEWithRawData(PAA, data).TypedDataErrorable(); err == nil {
+			t.Errorf("[TestTypedPAAInvalidCases] on TypedData test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+}
+
+func TestTypedPDNType(t *testing.T) {
+	ie, err := (&TypedPDNType{Value: PDNTypeIPv4v6}).ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedPDNType] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays([]byte{0x03}, ie.Data); err != nil {
+		t.Errorf("[TestTypedPDNType] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typedPDNType, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedPDNType] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if got := typedPDNType.(*TypedPDNType).Value; got != PDNTypeIPv4v6 {
+		t.Errorf("[TestTypedPDNType] expected Value (IPv4v6), got (%s)", got)
+	}
+
+	if _, err := NewIEWithRawData(PDNType, []byte{0x01, 0x00}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedPDNType] expected error on TypedData for two octets of data, but got none")
+	}
+}
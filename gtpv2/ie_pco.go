@@ -0,0 +1,145 @@
+package gtpv2
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/pco"
+)
+
+// TypedPCO is a structured version of a Protocol Configuration Options IE
+type TypedPCO struct {
+	Container *pco.Container
+}
+
+// ToIE creates an IE from the structured version of a PCO, and
+// panics if there is an error
+func (typedPCO *TypedPCO) ToIE() *IE {
+	ie, err := typedPCO.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (typedPCO *TypedPCO) ToIEErrorable() (*IE, error) {
+	data, err := encodePCOContainer(typedPCO.Container, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PCO: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(ProtocolConfigurationOptions, data)
+}
+
+func makeTypedPCO(fromIE *IE) (*TypedPCO, error) {
+	if fromIE.Type != ProtocolConfigurationOptions {
+		return nil, fmt.Errorf("supplied IE is not of type PCO")
+	}
+
+	container, err := pco.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PCO encoding: %s", err)
+	}
+
+	return &TypedPCO{Container: container}, nil
+}
+
+// TypedAPCO is a structured version of an Additional Protocol Configuration
+// Options IE.  It has the same encoding as a PCO.
+type TypedAPCO struct {
+	Container *pco.Container
+}
+
+// ToIE creates an IE from the structured version of an APCO, and
+// panics if there is an error
+func (apco *TypedAPCO) ToIE() *IE {
+	ie, err := apco.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (apco *TypedAPCO) ToIEErrorable() (*IE, error) {
+	data, err := encodePCOContainer(apco.Container, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APCO: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(APCO, data)
+}
+
+func makeTypedAPCO(fromIE *IE) (*TypedAPCO, error) {
+	if fromIE.Type != APCO {
+		return nil, fmt.Errorf("supplied IE is not of type APCO")
+	}
+
+	container, err := pco.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APCO encoding: %s", err)
+	}
+
+	return &TypedAPCO{Container: container}, nil
+}
+
+// TypedEPCO is a structured version of an Extended Protocol Configuration
+// Options IE.  It differs from a PCO in that each entry has a two octet
+// length.
+type TypedEPCO struct {
+	Container *pco.Container
+}
+
+// ToIE creates an IE from the structured version of an ePCO, and
+// panics if there is an error
+func (epco *TypedEPCO) ToIE() *IE {
+	ie, err := epco.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (epco *TypedEPCO) ToIEErrorable() (*IE, error) {
+	data, err := encodePCOContainer(epco.Container, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ePCO: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(ePCO, data)
+}
+
+func makeTypedEPCO(fromIE *IE) (*TypedEPCO, error) {
+	if fromIE.Type != ePCO {
+		return nil, fmt.Errorf("supplied IE is not of type ePCO")
+	}
+
+	container, err := pco.DecodeExtended(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ePCO encoding: %s", err)
+	}
+
+	return &TypedEPCO{Container: container}, nil
+}
+
+func encodePCOContainer(container *pco.Container, extended bool) ([]byte, error) {
+	if container == nil {
+		return nil, fmt.Errorf("container is nil")
+	}
+
+	if extended {
+		return container.EncodeExtended()
+	}
+
+	return container.Encode()
+}
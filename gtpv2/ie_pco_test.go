@@ -0,0 +1,72 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blorticus-go/gtp/pco"
+	"github.com/go-test/deep"
+)
+
+func TestTypedPCO(t *testing.T) {
+	dns, _ := pco.NewIPAddressEntry(pco.IDDNSServerIPv4Address, net.ParseIP("10.0.0.53"))
+	container := pco.NewContainer(dns, pco.NewIPv4LinkMTUEntry(1400))
+
+	testCases := []struct {
+		typed             TypedIE
+		expectedType      IEType
+		expectedDataBytes []byte
+	}{
+		{
+			typed:             &TypedPCO{Container: container},
+			expectedType:      ProtocolConfigurationOptions,
+			expectedDataBytes: []byte{0x80, 0x00, 0x0d, 0x04, 10, 0, 0, 53, 0x00, 0x10, 0x02, 0x05, 0x78},
+		},
+		{
+			typed:             &TypedAPCO{Container: container},
+			expectedType:      APCO,
+			expectedDataBytes: []byte{0x80, 0x00, 0x0d, 0x04, 10, 0, 0, 53, 0x00, 0x10, 0x02, 0x05, 0x78},
+		},
+		{
+			typed:             &TypedEPCO{Container: container},
+			expectedType:      ePCO,
+			expectedDataBytes: []byte{0x80, 0x00, 0x0d, 0x00, 0x04, 10, 0, 0, 53, 0x00, 0x10, 0x00, 0x02, 0x05, 0x78},
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := testCase.typed.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedPCO] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if ie.Type != testCase.expectedType {
+			t.Errorf("[TestTypedPCO] on test number [%d] expected IE type (%d), got (%d)", testNumber, testCase.expectedType, ie.Type)
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedPCO] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typed, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedPCO] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if diff := deep.Equal(testCase.typed, typed); diff != nil {
+			t.Errorf("[TestTypedPCO] on test number [%d] on TypedData: %s", testNumber, diff)
+		}
+	}
+
+	if _, err := (&TypedPCO{}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedPCO] expected error on ToIEErrorable with nil container, but got none")
+	}
+
+	if _, err := NewIEWithRawData(ePCO, []byte{0x80, 0x00, 0x0d, 0x04}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedPCO] expected error on TypedData for truncated ePCO, but got none")
+	}
+}
@@ -0,0 +1,97 @@
+package gtpv2
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/plmn"
+)
+
+// TypedServingNetwork is a structured version of a Serving Network IE
+type TypedServingNetwork struct {
+	PLMN plmn.PLMN
+}
+
+// ToIE creates an IE from the structured version of a Serving Network, and
+// panics if there is an error
+func (servingNetwork *TypedServingNetwork) ToIE() *IE {
+	ie, err := servingNetwork.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (servingNetwork *TypedServingNetwork) ToIEErrorable() (*IE, error) {
+	data, err := servingNetwork.PLMN.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Serving Network: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(ServingNetwork, data)
+}
+
+func makeTypedServingNetwork(fromIE *IE) (*TypedServingNetwork, error) {
+	if fromIE.Type != ServingNetwork {
+		return nil, fmt.Errorf("supplied IE is not of type Serving Network")
+	}
+
+	if len(fromIE.Data) != plmn.EncodedLength {
+		return nil, fmt.Errorf("length of IE data is not correct for Serving Network type")
+	}
+
+	decoded, err := plmn.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Serving Network encode value: %s", err)
+	}
+
+	return &TypedServingNetwork{PLMN: decoded}, nil
+}
+
+// TypedPLMNID is a structured version of a PLMN ID IE
+type TypedPLMNID struct {
+	PLMN plmn.PLMN
+}
+
+// ToIE creates an IE from the structured version of a PLMN ID, and
+// panics if there is an error
+func (plmnID *TypedPLMNID) ToIE() *IE {
+	ie, err := plmnID.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (plmnID *TypedPLMNID) ToIEErrorable() (*IE, error) {
+	data, err := plmnID.PLMN.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid PLMN ID: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(PLMNID, data)
+}
+
+func makeTypedPLMNID(fromIE *IE) (*TypedPLMNID, error) {
+	if fromIE.Type != PLMNID {
+		return nil, fmt.Errorf("supplied IE is not of type PLMN ID")
+	}
+
+	if len(fromIE.Data) != plmn.EncodedLength {
+		return nil, fmt.Errorf("length of IE data is not correct for PLMN ID type")
+	}
+
+	decoded, err := plmn.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PLMN ID encode value: %s", err)
+	}
+
+	return &TypedPLMNID{PLMN: decoded}, nil
+}
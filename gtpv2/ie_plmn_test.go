@@ -0,0 +1,86 @@
+package gtpv2
+
+import (
+	"testing"
+
+	"github.com/blorticus-go/gtp/plmn"
+)
+
+func TestTypedServingNetwork(t *testing.T) {
+	testCases := []struct {
+		servingNetwork    *TypedServingNetwork
+		expectedDataBytes []byte
+	}{
+		{
+			servingNetwork:    &TypedServingNetwork{PLMN: plmn.PLMN{MCC: "001", MNC: "01"}},
+			expectedDataBytes: []byte{0x00, 0xf1, 0x10},
+		},
+		{
+			servingNetwork:    &TypedServingNetwork{PLMN: plmn.PLMN{MCC: "310", MNC: "260"}},
+			expectedDataBytes: []byte{0x13, 0x00, 0x62},
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := testCase.servingNetwork.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedServingNetwork] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedServingNetwork] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typed, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedServingNetwork] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if got := typed.(*TypedServingNetwork).PLMN; got != testCase.servingNetwork.PLMN {
+			t.Errorf("[TestTypedServingNetwork] on test number [%d] expected PLMN = (%s), got (%s)", testNumber, testCase.servingNetwork.PLMN, got)
+		}
+	}
+
+	if _, err := (&TypedServingNetwork{PLMN: plmn.PLMN{MCC: "001", MNC: "1"}}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedServingNetwork] expected error on ToIEErrorable for one digit MNC, but got none")
+	}
+
+	if _, err := NewIEWithRawData(ServingNetwork, []byte{0x00, 0xf1}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedServingNetwork] expected error on TypedData for short data, but got none")
+	}
+}
+
+func TestTypedPLMNID(t *testing.T) {
+	plmnID := &TypedPLMNID{PLMN: plmn.PLMN{MCC: "234", MNC: "15"}}
+	expectedDataBytes := []byte{0x32, 0xf4, 0x51}
+
+	ie, err := plmnID.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedPLMNID] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if ie.Type != PLMNID {
+		t.Errorf("[TestTypedPLMNID] expected IE type (%d), got (%d)", PLMNID, ie.Type)
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedPLMNID] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedPLMNID] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if got := typed.(*TypedPLMNID).PLMN; got != plmnID.PLMN {
+		t.Errorf("[TestTypedPLMNID] expected PLMN = (%s), got (%s)", plmnID.PLMN, got)
+	}
+
+	if _, err := NewIEWithRawData(PLMNID, []byte{0x3a, 0xf4, 0x51}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedPLMNID] expected error on TypedData for invalid digit, but got none")
+	}
+}
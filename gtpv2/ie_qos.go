@@ -0,0 +1,246 @@
+package gtpv2
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// BitRate is a bit rate expressed in kilobits per second (1 kbps = 1000 bps),
+// which is the unit used for the MBR and GBR fields of QoS IEs.  In QoS IEs,
+// a BitRate is actually a uint40 value.
+type BitRate uint64
+
+// MaximumEncodableBitRate is the largest BitRate that fits in the 40-bit
+// rate fields of QoS IEs
+const MaximumEncodableBitRate BitRate = 0xffffffffff
+
+// BitRateFromBitsPerSecond converts a rate in bits per second to a BitRate,
+// rounding up to the next whole kbps
+func BitRateFromBitsPerSecond(bitsPerSecond uint64) BitRate {
+	return BitRate((bitsPerSecond + 999) / 1000)
+}
+
+// Kbps returns the rate in kilobits per second
+func (rate BitRate) Kbps() uint64 {
+	return uint64(rate)
+}
+
+// BitsPerSecond returns the rate in bits per second
+func (rate BitRate) BitsPerSecond() uint64 {
+	return uint64(rate) * 1000
+}
+
+// String returns the rate in the largest whole unit that represents
+// it exactly (e.g., "10 Mbps", "1500 kbps")
+func (rate BitRate) String() string {
+	switch {
+	case rate != 0 && rate%1000000 == 0:
+		return fmt.Sprintf("%d Gbps", rate/1000000)
+	case rate != 0 && rate%1000 == 0:
+		return fmt.Sprintf("%d Mbps", rate/1000)
+	default:
+		return fmt.Sprintf("%d kbps", uint64(rate))
+	}
+}
+
+func appendBitRates(data []byte, rates ...BitRate) ([]byte, error) {
+	for _, rate := range rates {
+		if rate > MaximumEncodableBitRate {
+			return nil, fmt.Errorf("bit rate (%d kbps) exceeds maximum 40-bit value", uint64(rate))
+		}
+
+		data = append(data, byte(rate>>32), byte(rate>>24), byte(rate>>16), byte(rate>>8), byte(rate))
+	}
+
+	return data, nil
+}
+
+func extractBitRate(fiveOctets []byte) BitRate {
+	return BitRate(fiveOctets[0])<<32 | BitRate(fiveOctets[1])<<24 | BitRate(fiveOctets[2])<<16 | BitRate(fiveOctets[3])<<8 | BitRate(fiveOctets[4])
+}
+
+// TypedBearerQoS is a structured version of a Bearer Level Quality of Service IE.
+// PreemptionCapability and PreemptionVulnerability are true when the capability
+// or vulnerability is enabled, which is encoded as a PCI or PVI flag value of 0.
+// PriorityLevel must be between 1 and 15.
+type TypedBearerQoS struct {
+	PreemptionCapability      bool
+	PriorityLevel             uint8
+	PreemptionVulnerability   bool
+	QCI                       uint8
+	MaximumBitRateUplink      BitRate
+	MaximumBitRateDownlink    BitRate
+	GuaranteedBitRateUplink   BitRate
+	GuaranteedBitRateDownlink BitRate
+}
+
+// ToIE creates an IE from the structured version of a Bearer QoS, and
+// panics if there is an error
+func (qos *TypedBearerQoS) ToIE() *IE {
+	ie, err := qos.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (qos *TypedBearerQoS) ToIEErrorable() (*IE, error) {
+	if qos.PriorityLevel < 1 || qos.PriorityLevel > 15 {
+		return nil, fmt.Errorf("bearer QoS priority level (%d) must be between 1 and 15", qos.PriorityLevel)
+	}
+
+	arp := qos.PriorityLevel << 2
+
+	if !qos.PreemptionCapability {
+		arp |= 0x40
+	}
+	if !qos.PreemptionVulnerability {
+		arp |= 0x01
+	}
+
+	data := make([]byte, 2, 22)
+	data[0] = arp
+	data[1] = qos.QCI
+
+	data, err := appendBitRates(data, qos.MaximumBitRateUplink, qos.MaximumBitRateDownlink, qos.GuaranteedBitRateUplink, qos.GuaranteedBitRateDownlink)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIEWithRawDataErrorable(BearerQoS, data)
+}
+
+func makeTypedBearerQoS(fromIE *IE) (*TypedBearerQoS, error) {
+	if fromIE.Type != BearerQoS {
+		return nil, fmt.Errorf("supplied IE is not of type Bearer QoS")
+	}
+
+	data := fromIE.Data
+
+	if len(data) != 22 {
+		return nil, fmt.Errorf("length of IE data (%d) is not correct for Bearer QoS type", len(data))
+	}
+
+	qos := &TypedBearerQoS{
+		PreemptionCapability:      data[0]&0x40 == 0,
+		PriorityLevel:             (data[0] & 0x3c) >> 2,
+		PreemptionVulnerability:   data[0]&0x01 == 0,
+		QCI:                       data[1],
+		MaximumBitRateUplink:      extractBitRate(data[2:7]),
+		MaximumBitRateDownlink:    extractBitRate(data[7:12]),
+		GuaranteedBitRateUplink:   extractBitRate(data[12:17]),
+		GuaranteedBitRateDownlink: extractBitRate(data[17:22]),
+	}
+
+	if qos.PriorityLevel == 0 {
+		return nil, fmt.Errorf("bearer QoS priority level must be between 1 and 15, but is 0")
+	}
+
+	return qos, nil
+}
+
+// TypedFlowQoS is a structured version of a Flow Quality of Service IE
+type TypedFlowQoS struct {
+	QCI                       uint8
+	MaximumBitRateUplink      BitRate
+	MaximumBitRateDownlink    BitRate
+	GuaranteedBitRateUplink   BitRate
+	GuaranteedBitRateDownlink BitRate
+}
+
+// ToIE creates an IE from the structured version of a Flow QoS, and
+// panics if there is an error
+func (qos *TypedFlowQoS) ToIE() *IE {
+	ie, err := qos.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (qos *TypedFlowQoS) ToIEErrorable() (*IE, error) {
+	data, err := appendBitRates([]byte{qos.QCI}, qos.MaximumBitRateUplink, qos.MaximumBitRateDownlink, qos.GuaranteedBitRateUplink, qos.GuaranteedBitRateDownlink)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIEWithRawDataErrorable(FlowQoS, data)
+}
+
+func makeTypedFlowQoS(fromIE *IE) (*TypedFlowQoS, error) {
+	if fromIE.Type != FlowQoS {
+		return nil, fmt.Errorf("supplied IE is not of type Flow QoS")
+	}
+
+	data := fromIE.Data
+
+	if len(data) != 21 {
+		return nil, fmt.Errorf("length of IE data (%d) is not correct for Flow QoS type", len(data))
+	}
+
+	return &TypedFlowQoS{
+		QCI:                       data[0],
+		MaximumBitRateUplink:      extractBitRate(data[1:6]),
+		MaximumBitRateDownlink:    extractBitRate(data[6:11]),
+		GuaranteedBitRateUplink:   extractBitRate(data[11:16]),
+		GuaranteedBitRateDownlink: extractBitRate(data[16:21]),
+	}, nil
+}
+
+// MaximumEncodableAMBR is the largest BitRate that fits in the 32-bit rate
+// fields of an AMBR IE
+const MaximumEncodableAMBR BitRate = 0xffffffff
+
+// TypedAMBR is a structured version of an Aggregate Maximum Bit Rate IE
+// (e.g., the APN-AMBR)
+type TypedAMBR struct {
+	Uplink   BitRate
+	Downlink BitRate
+}
+
+// ToIE creates an IE from the structured version of an AMBR, and
+// panics if there is an error
+func (ambr *TypedAMBR) ToIE() *IE {
+	ie, err := ambr.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (ambr *TypedAMBR) ToIEErrorable() (*IE, error) {
+	if ambr.Uplink > MaximumEncodableAMBR || ambr.Downlink > MaximumEncodableAMBR {
+		return nil, fmt.Errorf("AMBR bit rates (%d, %d kbps) exceed maximum 32-bit value", uint64(ambr.Uplink), uint64(ambr.Downlink))
+	}
+
+	data := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(make([]byte, 0, 8), uint32(ambr.Uplink)), uint32(ambr.Downlink))
+
+	return NewIEWithRawDataErrorable(AMBR, data)
+}
+
+func makeTypedAMBR(fromIE *IE) (*TypedAMBR, error) {
+	if fromIE.Type != AMBR {
+		return nil, fmt.Errorf("supplied IE is not of type AMBR")
+	}
+
+	if len(fromIE.Data) != 8 {
+		return nil, fmt.Errorf("length of IE data (%d) is not correct for AMBR type", len(fromIE.Data))
+	}
+
+	return &TypedAMBR{
+		Uplink:   BitRate(binary.BigEndian.Uint32(fromIE.Data[0:4])),
+		Downlink: BitRate(binary.BigEndian.Uint32(fromIE.Data[4:8])),
+	}, nil
+}
@@ -0,0 +1,180 @@
+package gtpv2
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTypedBearerQoS(t *testing.T) {
+	testCases := []struct {
+		qos               *TypedBearerQoS
+		expectedDataBytes []byte
+	}{
+		{
+			qos:               &TypedBearerQoS{PriorityLevel: 9, QCI: 9},
+			expectedDataBytes: []byte{0x65, 0x09, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			qos: &TypedBearerQoS{
+				PreemptionCapability:      true,
+				PriorityLevel:             2,
+				PreemptionVulnerability:   true,
+				QCI:                       1,
+				MaximumBitRateUplink:      128,
+				MaximumBitRateDownlink:    256,
+				GuaranteedBitRateUplink:   64,
+				GuaranteedBitRateDownlink: MaximumEncodableBitRate,
+			},
+			expectedDataBytes: []byte{
+				0x08, 0x01,
+				0x00, 0x00, 0x00, 0x00, 0x80,
+				0x00, 0x00, 0x00, 0x01, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x40,
+				0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := testCase.qos.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedBearerQoS] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedBearerQoS] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typedQoS, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedBearerQoS] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if diff := deep.Equal(testCase.qos, typedQoS.(*TypedBearerQoS)); diff != nil {
+			t.Errorf("[TestTypedBearerQoS] on test number [%d]: %s", testNumber, diff)
+		}
+	}
+}
+
+func TestTypedBearerQoSInvalidCases(t *testing.T) {
+	invalidQoS := []*TypedBearerQoS{
+		{PriorityLevel: 0, QCI: 9},
+		{PriorityLevel: 16, QCI: 9},
+		{PriorityLevel: 1, QCI: 9, MaximumBitRateDownlink: MaximumEncodableBitRate + 1},
+	}
+
+	for testIndex, qos := range invalidQoS {
+		if _, err := qos.ToIEErrorable(); err == nil {
+			t.Errorf("[TestTypedBearerQoSInvalidCases] on ToIE test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+
+	for testIndex, data := range [][]byte{make([]byte, 21), make([]byte, 22)} {
+		if _, err := NewIEWithRawData(BearerQoS, data).TypedDataErrorable(); err == nil {
+			t.Errorf("[TestTypedBearerQoSInvalidCases] on TypedData test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+}
+
+func TestTypedFlowQoS(t *testing.T) {
+	qos := &TypedFlowQoS{
+		QCI:                       5,
+		MaximumBitRateUplink:      0x0102030405,
+		MaximumBitRateDownlink:    10000,
+		GuaranteedBitRateUplink:   0,
+		GuaranteedBitRateDownlink: 1,
+	}
+
+	expectedDataBytes := []byte{
+		0x05,
+		0x01, 0x02, 0x03, 0x04, 0x05,
+		0x00, 0x00, 0x00, 0x27, 0x10,
+		0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x01,
+	}
+
+	ie, err := qos.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedFlowQoS] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedFlowQoS] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typedQoS, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedFlowQoS] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(qos, typedQoS.(*TypedFlowQoS)); diff != nil {
+		t.Errorf("[TestTypedFlowQoS] %s", diff)
+	}
+
+	if _, err := NewIEWithRawData(FlowQoS, expectedDataBytes[:20]).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedFlowQoS] expected error on TypedData for short data, but got none")
+	}
+}
+
+func TestBitRate(t *testing.T) {
+	testCases := []struct {
+		rate                  BitRate
+		expectedBitsPerSecond uint64
+		expectedString        string
+	}{
+		{0, 0, "0 kbps"},
+		{1500, 1500000, "1500 kbps"},
+		{10000, 10000000, "10 Mbps"},
+		{2000000, 2000000000, "2 Gbps"},
+	}
+
+	for _, testCase := range testCases {
+		if got := testCase.rate.BitsPerSecond(); got != testCase.expectedBitsPerSecond {
+			t.Errorf("[TestBitRate] for rate (%d) expected BitsPerSecond() = (%d), got (%d)", testCase.rate, testCase.expectedBitsPerSecond, got)
+		}
+
+		if got := testCase.rate.String(); got != testCase.expectedString {
+			t.Errorf("[TestBitRate] for rate (%d) expected String() = (%s), got (%s)", testCase.rate, testCase.expectedString, got)
+		}
+	}
+
+	if got := BitRateFromBitsPerSecond(1001); got != 2 {
+		t.Errorf("[TestBitRate] expected BitRateFromBitsPerSecond(1001) = 2, got (%d)", got)
+	}
+}
+
+func TestTypedAMBR(t *testing.T) {
+	ambr := &TypedAMBR{Uplink: 50000, Downlink: 150000}
+	expectedDataBytes := []byte{0x00, 0x00, 0xc3, 0x50, 0x00, 0x02, 0x49, 0xf0}
+
+	ie, err := ambr.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedAMBR] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedAMBR] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedAMBR] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(ambr, typed.(*TypedAMBR)); diff != nil {
+		t.Errorf("[TestTypedAMBR] on TypedData: %s", diff)
+	}
+
+	if _, err := (&TypedAMBR{Uplink: MaximumEncodableAMBR + 1}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedAMBR] expected error on ToIEErrorable for uplink exceeding 32 bits, but got none")
+	}
+
+	if _, err := NewIEWithRawData(AMBR, []byte{0x00, 0x01}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedAMBR] expected error on TypedData for short data, but got none")
+	}
+}
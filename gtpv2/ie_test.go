@@ -3,6 +3,7 @@ package gtpv2
 import (
 	"fmt"
 	"net"
+	"strings"
 	"testing"
 )
 
@@ -251,6 +252,16 @@ func TestTypedIMSI(t *testing.T) {
 			}
 		}
 	}
+
+	if _, err := (&TypedIMSI{AsString: "0010027890a2345"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedIMSI] expected error on ToIEErrorable for invalid digit, but got none")
+	} else if !strings.Contains(err.Error(), "position (11)") {
+		t.Errorf("[TestTypedIMSI] expected error on ToIEErrorable to identify position (11), got (%s)", err.Error())
+	}
+
+	if _, err := (&TypedIMSI{AsString: "0010027890123456"}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedIMSI] expected error on ToIEErrorable for 16 digits, but got none")
+	}
 }
 
 func TestGroupIECreation(t *testing.T) {
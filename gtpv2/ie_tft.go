@@ -0,0 +1,56 @@
+package gtpv2
+
+import (
+	"fmt"
+
+	"github.com/blorticus-go/gtp/tft"
+)
+
+// TypedBearerTFT is a structured version of an EPS Bearer Level Traffic Flow
+// Template IE
+type TypedBearerTFT struct {
+	TFT *tft.TFT
+}
+
+// ToIE creates an IE from the structured version of a Bearer TFT, and
+// panics if there is an error
+func (bearerTFT *TypedBearerTFT) ToIE() *IE {
+	ie, err := bearerTFT.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing.  The TFT is validated (see tft.TFT.Validate).
+func (bearerTFT *TypedBearerTFT) ToIEErrorable() (*IE, error) {
+	if bearerTFT.TFT == nil {
+		return nil, fmt.Errorf("invalid Bearer TFT: TFT is nil")
+	}
+
+	data, err := bearerTFT.TFT.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Bearer TFT: %s", err)
+	}
+
+	return NewIEWithRawDataErrorable(BearerTFT, data)
+}
+
+// makeTypedBearerTFT does not validate the TFT, so that a receiver can
+// decide how to respond to a TFT that is structurally sound but breaks the
+// operation rules
+func makeTypedBearerTFT(fromIE *IE) (*TypedBearerTFT, error) {
+	if fromIE.Type != BearerTFT {
+		return nil, fmt.Errorf("supplied IE is not of type Bearer TFT")
+	}
+
+	decoded, err := tft.Decode(fromIE.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Bearer TFT encoding: %s", err)
+	}
+
+	return &TypedBearerTFT{TFT: decoded}, nil
+}
@@ -0,0 +1,59 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blorticus-go/gtp/tft"
+	"github.com/go-test/deep"
+)
+
+func TestTypedBearerTFT(t *testing.T) {
+	bearerTFT := &TypedBearerTFT{
+		TFT: &tft.TFT{
+			Operation: tft.OperationCreateNewTFT,
+			PacketFilters: []*tft.PacketFilter{
+				{
+					Identifier: 1,
+					Direction:  tft.DirectionUplinkOnly,
+					Precedence: 255,
+					Components: []tft.Component{
+						&tft.IPv4RemoteAddress{Address: net.IP{10, 0, 0, 1}, Mask: net.IPMask{0xff, 0xff, 0xff, 0xff}},
+					},
+				},
+			},
+			Parameters: []*tft.Parameter{},
+		},
+	}
+	expectedDataBytes := []byte{0x21, 0x21, 0xff, 0x09, 0x10, 10, 0, 0, 1, 0xff, 0xff, 0xff, 0xff}
+
+	ie, err := bearerTFT.ToIEErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedBearerTFT] did not expect error, but got error = (%s)", err.Error())
+	}
+
+	if ie.Type != BearerTFT {
+		t.Errorf("[TestTypedBearerTFT] expected IE type (%d), got (%d)", BearerTFT, ie.Type)
+	}
+
+	if err := compareByteArrays(expectedDataBytes, ie.Data); err != nil {
+		t.Errorf("[TestTypedBearerTFT] data in IE from ToIEErrorable does not match expected: %s", err.Error())
+	}
+
+	typed, err := ie.TypedDataErrorable()
+	if err != nil {
+		t.Fatalf("[TestTypedBearerTFT] expected no error on TypedData but got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(bearerTFT, typed.(*TypedBearerTFT)); diff != nil {
+		t.Errorf("[TestTypedBearerTFT] on TypedData: %s", diff)
+	}
+
+	if _, err := (&TypedBearerTFT{TFT: &tft.TFT{Operation: tft.OperationCreateNewTFT}}).ToIEErrorable(); err == nil {
+		t.Errorf("[TestTypedBearerTFT] expected error on ToIEErrorable for create with no packet filters, but got none")
+	}
+
+	if _, err := NewIEWithRawData(BearerTFT, []byte{0x21, 0x21}).TypedDataErrorable(); err == nil {
+		t.Errorf("[TestTypedBearerTFT] expected error on TypedData for truncated TFT, but got none")
+	}
+}
@@ -0,0 +1,295 @@
+package gtpv2
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blorticus-go/gtp/plmn"
+)
+
+// CGI is a Cell Global Identifier, as carried in a ULI IE
+type CGI struct {
+	MCC              string
+	MNC              string
+	LocationAreaCode uint16
+	CellIdentity     uint16
+}
+
+// SAI is a Service Area Identifier, as carried in a ULI IE
+type SAI struct {
+	MCC              string
+	MNC              string
+	LocationAreaCode uint16
+	ServiceAreaCode  uint16
+}
+
+// RAI is a Routeing Area Identifier, as carried in a ULI IE.  The
+// RoutingAreaCode is the two-octet field from the IE.
+type RAI struct {
+	MCC              string
+	MNC              string
+	LocationAreaCode uint16
+	RoutingAreaCode  uint16
+}
+
+// TAI is a Tracking Area Identity, as carried in a ULI IE
+type TAI struct {
+	MCC              string
+	MNC              string
+	TrackingAreaCode uint16
+}
+
+// ECGI is an E-UTRAN Cell Global Identifier, as carried in a ULI IE.
+// ECI is actually a uint28 value.
+type ECGI struct {
+	MCC string
+	MNC string
+	ECI uint32
+}
+
+// LAI is a Location Area Identifier, as carried in a ULI IE
+type LAI struct {
+	MCC              string
+	MNC              string
+	LocationAreaCode uint16
+}
+
+// MacroENodeBID is a Macro eNodeB ID, as carried in a ULI IE.  ID is
+// actually a uint20 value.
+type MacroENodeBID struct {
+	MCC string
+	MNC string
+	ID  uint32
+}
+
+// ExtendedMacroENodeBID is an Extended Macro eNodeB ID, as carried in a
+// ULI IE.  If ShortMacro is true (the SMeNB flag), ID is a uint18 value
+// (a Short Macro eNodeB ID); otherwise it is a uint21 value (a Long
+// Macro eNodeB ID).
+type ExtendedMacroENodeBID struct {
+	MCC        string
+	MNC        string
+	ShortMacro bool
+	ID         uint32
+}
+
+// TypedULI is a structured version of a User Location Information IE.
+// Each location component is optional, and is nil when absent.
+type TypedULI struct {
+	CGI                   *CGI
+	SAI                   *SAI
+	RAI                   *RAI
+	TAI                   *TAI
+	ECGI                  *ECGI
+	LAI                   *LAI
+	MacroENodeBID         *MacroENodeBID
+	ExtendedMacroENodeBID *ExtendedMacroENodeBID
+}
+
+// ULI flag bits, in the first octet of the IE data
+const (
+	uliFlagCGI                   = 0x01
+	uliFlagSAI                   = 0x02
+	uliFlagRAI                   = 0x04
+	uliFlagTAI                   = 0x08
+	uliFlagECGI                  = 0x10
+	uliFlagLAI                   = 0x20
+	uliFlagMacroENodeBID         = 0x40
+	uliFlagExtendedMacroENodeBID = 0x80
+)
+
+// ToIE creates an IE from the structured version of a ULI, and
+// panics if there is an error
+func (uli *TypedULI) ToIE() *IE {
+	ie, err := uli.ToIEErrorable()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ie
+}
+
+// ToIEErrorable is the same as ToIE, but returns an error if one
+// occurs, rather than panicing
+func (uli *TypedULI) ToIEErrorable() (*IE, error) {
+	data := make([]byte, 1, 53)
+
+	appendComponent := func(flag byte, mcc string, mnc string, fields ...byte) error {
+		encodedPLMN, err := plmn.PLMN{MCC: mcc, MNC: mnc}.Encode()
+		if err != nil {
+			return err
+		}
+
+		data[0] |= flag
+		data = append(data, encodedPLMN...)
+		data = append(data, fields...)
+
+		return nil
+	}
+
+	if c := uli.CGI; c != nil {
+		if err := appendComponent(uliFlagCGI, c.MCC, c.MNC, uint16Octets(c.LocationAreaCode, c.CellIdentity)...); err != nil {
+			return nil, fmt.Errorf("on CGI: %s", err)
+		}
+	}
+
+	if c := uli.SAI; c != nil {
+		if err := appendComponent(uliFlagSAI, c.MCC, c.MNC, uint16Octets(c.LocationAreaCode, c.ServiceAreaCode)...); err != nil {
+			return nil, fmt.Errorf("on SAI: %s", err)
+		}
+	}
+
+	if c := uli.RAI; c != nil {
+		if err := appendComponent(uliFlagRAI, c.MCC, c.MNC, uint16Octets(c.LocationAreaCode, c.RoutingAreaCode)...); err != nil {
+			return nil, fmt.Errorf("on RAI: %s", err)
+		}
+	}
+
+	if c := uli.TAI; c != nil {
+		if err := appendComponent(uliFlagTAI, c.MCC, c.MNC, uint16Octets(c.TrackingAreaCode)...); err != nil {
+			return nil, fmt.Errorf("on TAI: %s", err)
+		}
+	}
+
+	if c := uli.ECGI; c != nil {
+		if c.ECI > 0x0fffffff {
+			return nil, fmt.Errorf("on ECGI: ECI (%d) exceeds maximum 28-bit value", c.ECI)
+		}
+
+		eci := make([]byte, 4)
+		binary.BigEndian.PutUint32(eci, c.ECI)
+
+		if err := appendComponent(uliFlagECGI, c.MCC, c.MNC, eci...); err != nil {
+			return nil, fmt.Errorf("on ECGI: %s", err)
+		}
+	}
+
+	if c := uli.LAI; c != nil {
+		if err := appendComponent(uliFlagLAI, c.MCC, c.MNC, uint16Octets(c.LocationAreaCode)...); err != nil {
+			return nil, fmt.Errorf("on LAI: %s", err)
+		}
+	}
+
+	if c := uli.MacroENodeBID; c != nil {
+		if c.ID > 0x0fffff {
+			return nil, fmt.Errorf("on Macro eNodeB ID: ID (%d) exceeds maximum 20-bit value", c.ID)
+		}
+
+		if err := appendComponent(uliFlagMacroENodeBID, c.MCC, c.MNC, byte(c.ID>>16), byte(c.ID>>8), byte(c.ID)); err != nil {
+			return nil, fmt.Errorf("on Macro eNodeB ID: %s", err)
+		}
+	}
+
+	if c := uli.ExtendedMacroENodeBID; c != nil {
+		firstOctet := byte(c.ID >> 16)
+
+		if c.ShortMacro {
+			if c.ID > 0x03ffff {
+				return nil, fmt.Errorf("on Extended Macro eNodeB ID: Short Macro ID (%d) exceeds maximum 18-bit value", c.ID)
+			}
+			firstOctet |= 0x80
+		} else if c.ID > 0x1fffff {
+			return nil, fmt.Errorf("on Extended Macro eNodeB ID: Long Macro ID (%d) exceeds maximum 21-bit value", c.ID)
+		}
+
+		if err := appendComponent(uliFlagExtendedMacroENodeBID, c.MCC, c.MNC, firstOctet, byte(c.ID>>8), byte(c.ID)); err != nil {
+			return nil, fmt.Errorf("on Extended Macro eNodeB ID: %s", err)
+		}
+	}
+
+	return NewIEWithRawDataErrorable(ULI, data)
+}
+
+func uint16Octets(values ...uint16) []byte {
+	octets := make([]byte, len(values)*2)
+
+	for i, value := range values {
+		binary.BigEndian.PutUint16(octets[i*2:], value)
+	}
+
+	return octets
+}
+
+var uliComponentLengths = []struct {
+	flag   byte
+	name   string
+	length int
+}{
+	{uliFlagCGI, "CGI", 7},
+	{uliFlagSAI, "SAI", 7},
+	{uliFlagRAI, "RAI", 7},
+	{uliFlagTAI, "TAI", 5},
+	{uliFlagECGI, "ECGI", 7},
+	{uliFlagLAI, "LAI", 5},
+	{uliFlagMacroENodeBID, "Macro eNodeB ID", 6},
+	{uliFlagExtendedMacroENodeBID, "Extended Macro eNodeB ID", 6},
+}
+
+func makeTypedULI(fromIE *IE) (*TypedULI, error) {
+	if fromIE.Type != ULI {
+		return nil, fmt.Errorf("supplied IE is not of type ULI")
+	}
+
+	data := fromIE.Data
+
+	if len(data) < 1 {
+		return nil, fmt.Errorf("length of IE data is not correct for ULI type")
+	}
+
+	flags := data[0]
+	remaining := data[1:]
+	uli := &TypedULI{}
+
+	for _, component := range uliComponentLengths {
+		if flags&component.flag == 0 {
+			continue
+		}
+
+		if len(remaining) < component.length {
+			return nil, fmt.Errorf("ULI flags indicate %s is present, but insufficient octets remain", component.name)
+		}
+
+		octets := remaining[:component.length]
+		remaining = remaining[component.length:]
+
+		decodedPLMN, err := plmn.Decode(octets[:plmn.EncodedLength])
+		if err != nil {
+			return nil, fmt.Errorf("on ULI %s: %s", component.name, err)
+		}
+
+		mcc, mnc := decodedPLMN.MCC, decodedPLMN.MNC
+		fields := octets[plmn.EncodedLength:]
+
+		switch component.flag {
+		case uliFlagCGI:
+			uli.CGI = &CGI{MCC: mcc, MNC: mnc, LocationAreaCode: binary.BigEndian.Uint16(fields[0:2]), CellIdentity: binary.BigEndian.Uint16(fields[2:4])}
+		case uliFlagSAI:
+			uli.SAI = &SAI{MCC: mcc, MNC: mnc, LocationAreaCode: binary.BigEndian.Uint16(fields[0:2]), ServiceAreaCode: binary.BigEndian.Uint16(fields[2:4])}
+		case uliFlagRAI:
+			uli.RAI = &RAI{MCC: mcc, MNC: mnc, LocationAreaCode: binary.BigEndian.Uint16(fields[0:2]), RoutingAreaCode: binary.BigEndian.Uint16(fields[2:4])}
+		case uliFlagTAI:
+			uli.TAI = &TAI{MCC: mcc, MNC: mnc, TrackingAreaCode: binary.BigEndian.Uint16(fields[0:2])}
+		case uliFlagECGI:
+			uli.ECGI = &ECGI{MCC: mcc, MNC: mnc, ECI: binary.BigEndian.Uint32(fields[0:4]) & 0x0fffffff}
+		case uliFlagLAI:
+			uli.LAI = &LAI{MCC: mcc, MNC: mnc, LocationAreaCode: binary.BigEndian.Uint16(fields[0:2])}
+		case uliFlagMacroENodeBID:
+			uli.MacroENodeBID = &MacroENodeBID{MCC: mcc, MNC: mnc, ID: uint32(fields[0]&0x0f)<<16 | uint32(fields[1])<<8 | uint32(fields[2])}
+		case uliFlagExtendedMacroENodeBID:
+			extended := &ExtendedMacroENodeBID{MCC: mcc, MNC: mnc, ShortMacro: fields[0]&0x80 != 0}
+			if extended.ShortMacro {
+				extended.ID = uint32(fields[0]&0x03)<<16 | uint32(fields[1])<<8 | uint32(fields[2])
+			} else {
+				extended.ID = uint32(fields[0]&0x1f)<<16 | uint32(fields[1])<<8 | uint32(fields[2])
+			}
+			uli.ExtendedMacroENodeBID = extended
+		}
+	}
+
+	if len(remaining) != 0 {
+		return nil, fmt.Errorf("ULI contains (%d) octets beyond those indicated by its flags", len(remaining))
+	}
+
+	return uli, nil
+}
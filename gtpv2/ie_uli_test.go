@@ -0,0 +1,116 @@
+package gtpv2
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+type TypedULIComparable struct {
+	uli               *TypedULI
+	expectedDataBytes []byte
+}
+
+func TestTypedULI(t *testing.T) {
+	testCases := []TypedULIComparable{
+		{
+			uli: &TypedULI{
+				TAI:  &TAI{MCC: "001", MNC: "001", TrackingAreaCode: 0xff00},
+				ECGI: &ECGI{MCC: "001", MNC: "001", ECI: 0x0f424d00},
+			},
+			expectedDataBytes: []byte{0x18, 0x00, 0x11, 0x00, 0xff, 0x00, 0x00, 0x11, 0x00, 0x0f, 0x42, 0x4d, 0x00},
+		},
+		{
+			uli: &TypedULI{
+				TAI:  &TAI{MCC: "310", MNC: "26", TrackingAreaCode: 0x0001},
+				ECGI: &ECGI{MCC: "310", MNC: "26", ECI: 0x00000102},
+			},
+			expectedDataBytes: []byte{0x18, 0x13, 0xf0, 0x62, 0x00, 0x01, 0x13, 0xf0, 0x62, 0x00, 0x00, 0x01, 0x02},
+		},
+		{
+			uli: &TypedULI{
+				CGI: &CGI{MCC: "234", MNC: "150", LocationAreaCode: 0x1234, CellIdentity: 0x5678},
+				SAI: &SAI{MCC: "234", MNC: "15", LocationAreaCode: 0x1234, ServiceAreaCode: 0x9abc},
+				RAI: &RAI{MCC: "234", MNC: "15", LocationAreaCode: 0x1234, RoutingAreaCode: 0x00ff},
+				LAI: &LAI{MCC: "234", MNC: "15", LocationAreaCode: 0x4321},
+			},
+			expectedDataBytes: []byte{
+				0x27,
+				0x32, 0x04, 0x51, 0x12, 0x34, 0x56, 0x78,
+				0x32, 0xf4, 0x51, 0x12, 0x34, 0x9a, 0xbc,
+				0x32, 0xf4, 0x51, 0x12, 0x34, 0x00, 0xff,
+				0x32, 0xf4, 0x51, 0x43, 0x21,
+			},
+		},
+		{
+			uli: &TypedULI{
+				MacroENodeBID:         &MacroENodeBID{MCC: "001", MNC: "01", ID: 0x0abcde},
+				ExtendedMacroENodeBID: &ExtendedMacroENodeBID{MCC: "001", MNC: "01", ShortMacro: true, ID: 0x03abcd},
+			},
+			expectedDataBytes: []byte{
+				0xc0,
+				0x00, 0xf1, 0x10, 0x0a, 0xbc, 0xde,
+				0x00, 0xf1, 0x10, 0x83, 0xab, 0xcd,
+			},
+		},
+		{
+			uli: &TypedULI{
+				ExtendedMacroENodeBID: &ExtendedMacroENodeBID{MCC: "001", MNC: "01", ID: 0x1fabcd},
+			},
+			expectedDataBytes: []byte{0x80, 0x00, 0xf1, 0x10, 0x1f, 0xab, 0xcd},
+		},
+	}
+
+	for testIndex, testCase := range testCases {
+		testNumber := testIndex + 1
+
+		ie, err := testCase.uli.ToIEErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedULI] on test number [%d] did not expect error, but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if err := compareByteArrays(testCase.expectedDataBytes, ie.Data); err != nil {
+			t.Errorf("[TestTypedULI] on test number [%d] data in IE from ToIEErrorable does not match expected: %s", testNumber, err.Error())
+		}
+
+		typedULI, err := ie.TypedDataErrorable()
+		if err != nil {
+			t.Errorf("[TestTypedULI] on test number [%d] expected no error on TypedData but got error = (%s)", testNumber, err.Error())
+			continue
+		}
+
+		if diff := deep.Equal(testCase.uli, typedULI.(*TypedULI)); diff != nil {
+			t.Errorf("[TestTypedULI] on test number [%d]: %s", testNumber, diff)
+		}
+	}
+}
+
+func TestTypedULIInvalidCases(t *testing.T) {
+	invalidULIs := []*TypedULI{
+		{TAI: &TAI{MCC: "01", MNC: "01"}},
+		{TAI: &TAI{MCC: "001", MNC: "1"}},
+		{ECGI: &ECGI{MCC: "001", MNC: "01", ECI: 0x10000000}},
+		{MacroENodeBID: &MacroENodeBID{MCC: "001", MNC: "01", ID: 0x100000}},
+		{ExtendedMacroENodeBID: &ExtendedMacroENodeBID{MCC: "001", MNC: "01", ShortMacro: true, ID: 0x040000}},
+	}
+
+	for testIndex, uli := range invalidULIs {
+		if _, err := uli.ToIEErrorable(); err == nil {
+			t.Errorf("[TestTypedULIInvalidCases] on ToIE test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+
+	invalidData := [][]byte{
+		{},
+		{0x08, 0x00, 0x11},
+		{0x08, 0x00, 0x11, 0x00, 0xff, 0x00, 0x01},
+		{0x08, 0xa0, 0x11, 0x00, 0xff, 0x00},
+	}
+
+	for testIndex, data := range invalidData {
+		if _, err := NewIEWithRawData(ULI, data).TypedDataErrorable(); err == nil {
+			t.Errorf("[TestTypedULIInvalidCases] on TypedData test number [%d] expected error, but got none", testIndex+1)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package gtpv2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// TypedMessage is a structured version of a GTPv2 message.  Each TypedMessage
+// has fields for the header values and for the IEs it models.  Marshal creates
+// a PDU from the fields, and Unmarshal populates the fields from a PDU.
+//
+// Neither Marshal nor Unmarshal checks for the presence of mandatory IEs.  IEs
+// in a PDU that the message does not model (or that are repeated, when the
+// message allows only one) are kept in the message's AdditionalIEs field by
+// Unmarshal, and Marshal appends AdditionalIEs after the modelled IEs, so a
+// PDU that is unmarshaled then marshaled has the same IEs, though possibly in
+// a different order.
+type TypedMessage interface {
+	MessageType() MessageType
+	Marshal() (*PDU, error)
+	Unmarshal(pdu *PDU) error
+}
+
+// messageEncoder accumulates IEs for a TypedMessage Marshal.  The first
+// error encountered is kept, and later additions are ignored.
+type messageEncoder struct {
+	ies []*IE
+	err error
+}
+
+func isNilTypedIE(typed TypedIE) bool {
+	if typed == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(typed)
+
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+func (encoder *messageEncoder) add(name string, typed TypedIE, instance uint8) {
+	if encoder.err != nil || isNilTypedIE(typed) {
+		return
+	}
+
+	ie, err := typed.ToIEErrorable()
+	if err != nil {
+		encoder.err = fmt.Errorf("on %s: %s", name, err)
+		return
+	}
+
+	ie.InstanceNumber = instance
+	encoder.ies = append(encoder.ies, ie)
+}
+
+func (encoder *messageEncoder) addBearerContexts(name string, bearerContexts []*TypedBearerContext, instance uint8) {
+	for _, bearerContext := range bearerContexts {
+		encoder.add(name, bearerContext, instance)
+	}
+}
+
+func (encoder *messageEncoder) addUint8(ieType IEType, value *uint8, instance uint8) {
+	if value != nil {
+		encoder.addRaw(ieType, []byte{*value}, instance)
+	}
+}
+
+func (encoder *messageEncoder) addUint16(ieType IEType, value *uint16, instance uint8) {
+	if value != nil {
+		encoder.addRaw(ieType, binary.BigEndian.AppendUint16(nil, *value), instance)
+	}
+}
+
+func (encoder *messageEncoder) addUint32(ieType IEType, value *uint32, instance uint8) {
+	if value != nil {
+		encoder.addRaw(ieType, binary.BigEndian.AppendUint32(nil, *value), instance)
+	}
+}
+
+func (encoder *messageEncoder) addUint8List(ieType IEType, values []uint8, instance uint8) {
+	for _, value := range values {
+		encoder.addRaw(ieType, []byte{value}, instance)
+	}
+}
+
+func (encoder *messageEncoder) addRaw(ieType IEType, data []byte, instance uint8) {
+	ie := NewIEWithRawData(ieType, data)
+	ie.InstanceNumber = instance
+	encoder.ies = append(encoder.ies, ie)
+}
+
+// pdu returns a PDU, with the TEID field present, containing the accumulated
+// IEs followed by additionalIEs
+func (encoder *messageEncoder) pdu(messageType MessageType, teid uint32, sequenceNumber uint32, additionalIEs []*IE) (*PDU, error) {
+	if encoder.err != nil {
+		return nil, encoder.err
+	}
+
+	if sequenceNumber > 0x00ffffff {
+		return nil, fmt.Errorf("sequence number (%d) exceeds maximum 24-bit value", sequenceNumber)
+	}
+
+	ies := append(encoder.ies, additionalIEs...)
+
+	pduLength := 12
+	for _, ie := range ies {
+		pduLength += len(ie.Data) + 4
+	}
+
+	if pduLength > 0xffff {
+		return nil, fmt.Errorf("combined IE lengths exceed maximum PDU length")
+	}
+
+	return NewPDU(messageType, sequenceNumber, ies).AddTEID(teid), nil
+}
+
+// messageDecoder hands out the IEs in a PDU for a TypedMessage Unmarshal.  Each
+// IE is handed out at most once, and the IEs that are never handed out are the
+// message's AdditionalIEs.  The first error encountered is kept.
+type messageDecoder struct {
+	remaining []*IE
+	err       error
+}
+
+func newMessageDecoder(pdu *PDU, expectedType MessageType) (*messageDecoder, error) {
+	if pdu.Type != expectedType {
+		return nil, fmt.Errorf("PDU message type (%s) is not (%s)", NameOfMessageForType(pdu.Type), NameOfMessageForType(expectedType))
+	}
+
+	remaining := make([]*IE, len(pdu.InformationElements))
+	copy(remaining, pdu.InformationElements)
+
+	return &messageDecoder{remaining: remaining}, nil
+}
+
+// take removes and returns the first remaining IE with the type and
+// instance, or returns nil if there is none
+func (decoder *messageDecoder) take(ieType IEType, instance uint8) *IE {
+	for i, ie := range decoder.remaining {
+		if ie.Type == ieType && ie.InstanceNumber == instance {
+			decoder.remaining = append(decoder.remaining[:i], decoder.remaining[i+1:]...)
+			return ie
+		}
+	}
+
+	return nil
+}
+
+// takeAll removes and returns every remaining IE with the type and instance
+func (decoder *messageDecoder) takeAll(ieType IEType, instance uint8) []*IE {
+	taken := make([]*IE, 0)
+
+	for ie := decoder.take(ieType, instance); ie != nil; ie = decoder.take(ieType, instance) {
+		taken = append(taken, ie)
+	}
+
+	return taken
+}
+
+func (decoder *messageDecoder) uint8Value(ieType IEType, instance uint8) *uint8 {
+	ie := decoder.take(ieType, instance)
+	if ie == nil || decoder.err != nil {
+		return nil
+	}
+
+	if len(ie.Data) < 1 {
+		decoder.err = fmt.Errorf("length of %s IE data is not correct", NameOfIEForType(ieType))
+		return nil
+	}
+
+	value := ie.Data[0]
+
+	return &value
+}
+
+// uint8Values returns the values of every remaining IE with the type and
+// instance, or nil if there are none
+func (decoder *messageDecoder) uint8Values(ieType IEType, instance uint8) []uint8 {
+	var values []uint8
+
+	for value := decoder.uint8Value(ieType, instance); value != nil; value = decoder.uint8Value(ieType, instance) {
+		values = append(values, *value)
+	}
+
+	return values
+}
+
+func (decoder *messageDecoder) uint16Value(ieType IEType, instance uint8) *uint16 {
+	ie := decoder.take(ieType, instance)
+	if ie == nil || decoder.err != nil {
+		return nil
+	}
+
+	if len(ie.Data) < 2 {
+		decoder.err = fmt.Errorf("length of %s IE data is not correct", NameOfIEForType(ieType))
+		return nil
+	}
+
+	value := binary.BigEndian.Uint16(ie.Data)
+
+	return &value
+}
+
+func (decoder *messageDecoder) uint32Value(ieType IEType, instance uint8) *uint32 {
+	ie := decoder.take(ieType, instance)
+	if ie == nil || decoder.err != nil {
+		return nil
+	}
+
+	if len(ie.Data) < 4 {
+		decoder.err = fmt.Errorf("length of %s IE data is not correct", NameOfIEForType(ieType))
+		return nil
+	}
+
+	value := binary.BigEndian.Uint32(ie.Data)
+
+	return &value
+}
+
+func (decoder *messageDecoder) bearerContexts(messageType MessageType, instance uint8) []*TypedBearerContext {
+	bearerContexts := make([]*TypedBearerContext, 0)
+
+	for _, ie := range decoder.takeAll(BearerContext, instance) {
+		if decoder.err != nil {
+			return nil
+		}
+
+		bearerContext, err := DecodeBearerContext(ie, messageType)
+		if err != nil {
+			decoder.err = err
+			return nil
+		}
+
+		bearerContexts = append(bearerContexts, bearerContext)
+	}
+
+	return bearerContexts
+}
+
+// decodeTyped takes the first remaining IE with the type and instance and
+// converts it using makeTyped.  Returns the zero value of T (a nil pointer,
+// for the makeTypedX functions) if the IE is absent or on error.
+func decodeTyped[T any](decoder *messageDecoder, ieType IEType, instance uint8, makeTyped func(*IE) (T, error)) T {
+	var typed T
+
+	ie := decoder.take(ieType, instance)
+	if ie == nil || decoder.err != nil {
+		return typed
+	}
+
+	typed, err := makeTyped(ie)
+	if err != nil {
+		decoder.err = fmt.Errorf("on %s IE with instance (%d): %s", NameOfIEForType(ieType), instance, err)
+	}
+
+	return typed
+}
+
+// newBearerContextIn creates a TypedBearerContext for a message and
+// Bearer Context instance, and appends it to list
+func newBearerContextIn(list *[]*TypedBearerContext, messageType MessageType, instance uint8, ebi uint8) *TypedBearerContext {
+	bearerContext := &TypedBearerContext{
+		MessageType: messageType,
+		Instance:    instance,
+		EBI:         ebi,
+		FTEIDs:      make(map[uint8]*TypedFTEID),
+		UnknownIEs:  make([]*IE, 0),
+	}
+
+	*list = append(*list, bearerContext)
+
+	return bearerContext
+}
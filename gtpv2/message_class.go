@@ -0,0 +1,220 @@
+package gtpv2
+
+import (
+	"fmt"
+	"net"
+)
+
+// MessageClass is the part that a message plays in a transaction (TS 29.274
+// section 7.6).  A transaction starts with an Initial message (a request,
+// command or notification).  A Triggered message (a response, a failure
+// indication, or a request triggered by a command) answers it.  A Triggered
+// Reply answers a Triggered message that is itself a request, such as a Create
+// Bearer Response to a Create Bearer Request triggered by a Bearer Resource
+// Command, or a Context Acknowledge to a Context Response.  All of the
+// messages in a transaction have the same sequence number.
+type MessageClass uint8
+
+// Message classes
+const (
+	MessageClassInitial MessageClass = iota + 1
+	MessageClassTriggered
+	MessageClassTriggeredReply
+)
+
+var messageClassNames = []string{"", "Initial", "Triggered", "Triggered Reply"}
+
+// String returns the name of the message class
+func (class MessageClass) String() string {
+	if class > 0 && int(class) < len(messageClassNames) {
+		return messageClassNames[class]
+	}
+
+	return fmt.Sprintf("MessageClass(%d)", uint8(class))
+}
+
+// CommandSequenceNumberFlag is set in the sequence number of a Command
+// message, and so in the sequence number of a request that the Command
+// triggers (TS 29.274 section 7.6)
+const CommandSequenceNumberFlag uint32 = 0x00800000
+
+// responseTypeForRequest maps each message type that expects an answer to the
+// type of the answer.  A command maps to its failure indication.  A response
+// that is itself answered, like a Context Response, is both a request and a
+// response.
+var responseTypeForRequest = map[MessageType]MessageType{
+	EchoRequest:                               EchoResponse,
+	CreateSessionRequest:                      CreateSessionResponse,
+	ModifyBearerRequest:                       ModifyBearerResponse,
+	DeleteSessionRequest:                      DeleteSessionResponse,
+	ChangeNotificationRequest:                 ChangeNotificationResponse,
+	RemoteUEReportNotification:                RemoteUEReportAcknowlegement,
+	ModifyBearerCommand:                       ModifyBearerFailureIndication,
+	DeleteBearerCommand:                       DeleteBearerFailureIndication,
+	BearerResourceCommand:                     BearerResourceFailureIndication,
+	CreateBearerRequest:                       CreateBearerResponse,
+	UpdateBearerRequest:                       UpdateBearerResponse,
+	DeleteBearerRequest:                       DeleteBearerResponse,
+	DeletePDNConnectionSetRequest:             DeletePDNConnectionSetResponse,
+	PGWDownlinkTriggeringNotification:         PGWDownlinkTriggeringAcknowledge,
+	IdentificationRequest:                     IdentificationResponse,
+	ContextRequest:                            ContextResponse,
+	ContextResponse:                           ContextAcknowledge,
+	ForwardRelocationRequest:                  ForwardRelocationResponse,
+	ForwardRelocationCompleteNotification:     ForwardRelocationCompleteAcknowledge,
+	ForwardAccessContextNotification:          ForwardAccessContextAcknowledge,
+	RelocationCancelRequest:                   RelocationCancelResponse,
+	DetachNotification:                        DetachAcknowledge,
+	AlertMMENotification:                      AlertMMEAcknowledge,
+	UEActivityNotification:                    UEActivityAcknowledge,
+	UERegistrationQueryRequest:                UERegistrationQueryResponse,
+	CreateForwardingTunnelRequest:             CreateForwardingTunnelResponse,
+	SuspendNotification:                       SuspendAcknowledge,
+	ResumeNotification:                        ResumeAcknowledge,
+	CreateIndirectDataForwardingTunnelRequest: CreateIndirectDataForwardingTunnelResponse,
+	DeleteIndirectDataForwardingTunnelRequest: DeleteIndirectDataForwardingTunnelResponse,
+	ReleaseAccessBearersRequest:               ReleaseAccessBearersResponse,
+	DownlinkDataNotification:                  DownlinkDataNotificationAcknowledge,
+	PGWRestartNotification:                    PGWRestartNotificationAcknowledge,
+	UpdatePDNConnectionSetRequest:             UpdatePDNConnectionSetResponse,
+}
+
+var requestTypeForResponse = func() map[MessageType]MessageType {
+	reverse := make(map[MessageType]MessageType)
+	for request, response := range responseTypeForRequest {
+		reverse[response] = request
+	}
+	return reverse
+}()
+
+// triggeredRequestTypesForCommand provides, for each command, the requests
+// that a successful command triggers (TS 29.274 sections 7.2.5, 7.2.14 and
+// 7.2.17.1)
+var triggeredRequestTypesForCommand = map[MessageType][]MessageType{
+	ModifyBearerCommand:   {UpdateBearerRequest},
+	DeleteBearerCommand:   {DeleteBearerRequest},
+	BearerResourceCommand: {CreateBearerRequest, UpdateBearerRequest, DeleteBearerRequest},
+}
+
+// ResponseTypeFor returns the message type that answers a request, command or
+// notification (e.g., CreateSessionResponse for CreateSessionRequest, and
+// ModifyBearerFailureIndication for ModifyBearerCommand).  A Context Response
+// is answered by a Context Acknowledge.  Returns false if nothing answers
+// messageType.
+func ResponseTypeFor(messageType MessageType) (MessageType, bool) {
+	responseType, isRequest := responseTypeForRequest[messageType]
+	return responseType, isRequest
+}
+
+// RequestTypeFor returns the message type that a response answers.  Returns
+// false if messageType is not a response.
+func RequestTypeFor(messageType MessageType) (MessageType, bool) {
+	requestType, isResponse := requestTypeForResponse[messageType]
+	return requestType, isResponse
+}
+
+// TriggeredRequestTypesFor returns the request types that a command may
+// trigger, or nil if messageType is not a command
+func TriggeredRequestTypesFor(messageType MessageType) []MessageType {
+	return triggeredRequestTypesForCommand[messageType]
+}
+
+// IsRequest returns true if the message type is a request, command or
+// notification that is answered by another message.  This includes a response
+// that is itself answered, like a Context Response.
+func IsRequest(messageType MessageType) bool {
+	_, isRequest := responseTypeForRequest[messageType]
+	return isRequest
+}
+
+// IsResponse returns true if the message type is a response, acknowledgement
+// or failure indication that answers another message.  This includes a
+// response that is itself answered, like a Context Response.
+func IsResponse(messageType MessageType) bool {
+	_, isResponse := requestTypeForResponse[messageType]
+	return isResponse
+}
+
+// IsCommand returns true if the message type is a command
+func IsCommand(messageType MessageType) bool {
+	_, isCommand := triggeredRequestTypesForCommand[messageType]
+	return isCommand
+}
+
+// IsTriggered returns true if a message of the type may be sent as a Triggered
+// message or Triggered Reply, that is, if it is a response or a request that a
+// command may trigger
+func IsTriggered(messageType MessageType) bool {
+	if IsResponse(messageType) {
+		return true
+	}
+
+	for _, triggeredTypes := range triggeredRequestTypesForCommand {
+		for _, triggeredType := range triggeredTypes {
+			if triggeredType == messageType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ClassOf returns the class of a message.  A request that a command may
+// trigger is Triggered when CommandSequenceNumberFlag is set in its sequence
+// number, and a response to such a request is then a Triggered Reply.  A
+// Version Not Supported Indication is Triggered, because it is sent in answer
+// to a message with the sequence number of that message.
+func ClassOf(messageType MessageType, sequenceNumber uint32) MessageClass {
+	sequenceIsFromCommand := sequenceNumber&CommandSequenceNumberFlag != 0
+
+	switch messageType {
+	case ContextAcknowledge:
+		return MessageClassTriggeredReply
+	case VersionNotSupportedIndication:
+		return MessageClassTriggered
+	}
+
+	if requestType, isResponse := RequestTypeFor(messageType); isResponse {
+		if sequenceIsFromCommand && !IsCommand(requestType) && IsTriggered(requestType) {
+			return MessageClassTriggeredReply
+		}
+		return MessageClassTriggered
+	}
+
+	if sequenceIsFromCommand && IsTriggered(messageType) {
+		return MessageClassTriggered
+	}
+
+	return MessageClassInitial
+}
+
+// CorrelationKey identifies a transaction with a peer.  All of the messages
+// in a transaction have the same CorrelationKey, so it can be used to match
+// a received response to the request that was sent, or to recognize a
+// retransmitted request.  Peer is the string form of the peer address.
+// InitiatedLocally is true for a transaction that starts with an Initial
+// message sent to the peer, and false for one that starts with an Initial
+// message received from the peer.  This is needed because each side chooses
+// sequence numbers for its own transactions, so the same sequence number may
+// be in use for a transaction in each direction.
+type CorrelationKey struct {
+	Peer             string
+	SequenceNumber   uint32
+	InitiatedLocally bool
+}
+
+// CorrelationKeyFor returns the CorrelationKey for a message that is sent to
+// (isOutgoing is true) or received from (isOutgoing is false) a peer.  The
+// direction of the transaction follows from the direction and class of the
+// message: an Initial message or Triggered Reply comes from the side that
+// started the transaction, and a Triggered message comes from the other side.
+func CorrelationKeyFor(peer net.Addr, pdu *PDU, isOutgoing bool) CorrelationKey {
+	sentByInitiator := ClassOf(pdu.Type, pdu.SequenceNumber) != MessageClassTriggered
+
+	return CorrelationKey{
+		Peer:             peer.String(),
+		SequenceNumber:   pdu.SequenceNumber,
+		InitiatedLocally: sentByInitiator == isOutgoing,
+	}
+}
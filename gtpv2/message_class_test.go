@@ -0,0 +1,79 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+)
+
+func TestMessageClass(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		messageType    MessageType
+		responseType   MessageType
+		hasResponse    bool
+		isRequest      bool
+		isResponse     bool
+		isTriggered    bool
+		sequenceNumber uint32
+		class          MessageClass
+	}{
+		{CreateSessionRequest, CreateSessionResponse, true, true, false, false, 0x000001, MessageClassInitial},
+		{CreateSessionResponse, 0, false, false, true, true, 0x000001, MessageClassTriggered},
+		{BearerResourceCommand, BearerResourceFailureIndication, true, true, false, false, 0x800001, MessageClassInitial},
+		{BearerResourceFailureIndication, 0, false, false, true, true, 0x800001, MessageClassTriggered},
+		{CreateBearerRequest, CreateBearerResponse, true, true, false, true, 0x000001, MessageClassInitial},
+		{CreateBearerRequest, CreateBearerResponse, true, true, false, true, 0x800001, MessageClassTriggered},
+		{CreateBearerResponse, 0, false, false, true, true, 0x000001, MessageClassTriggered},
+		{CreateBearerResponse, 0, false, false, true, true, 0x800001, MessageClassTriggeredReply},
+		{ContextResponse, ContextAcknowledge, true, true, true, true, 0x000001, MessageClassTriggered},
+		{ContextAcknowledge, 0, false, false, true, true, 0x000001, MessageClassTriggeredReply},
+		{StopPagingIndication, 0, false, false, false, false, 0x000001, MessageClassInitial},
+		{VersionNotSupportedIndication, 0, false, false, false, false, 0x000001, MessageClassTriggered},
+	} {
+		responseType, hasResponse := ResponseTypeFor(testCase.messageType)
+		if hasResponse != testCase.hasResponse || responseType != testCase.responseType {
+			t.Errorf("[TestMessageClass] on test number [%d] expected ResponseTypeFor = (%s, %t), got (%s, %t)", testIndex+1, NameOfMessageForType(testCase.responseType), testCase.hasResponse, NameOfMessageForType(responseType), hasResponse)
+		}
+
+		if IsRequest(testCase.messageType) != testCase.isRequest {
+			t.Errorf("[TestMessageClass] on test number [%d] expected IsRequest = (%t)", testIndex+1, testCase.isRequest)
+		}
+
+		if IsResponse(testCase.messageType) != testCase.isResponse {
+			t.Errorf("[TestMessageClass] on test number [%d] expected IsResponse = (%t)", testIndex+1, testCase.isResponse)
+		}
+
+		if IsTriggered(testCase.messageType) != testCase.isTriggered {
+			t.Errorf("[TestMessageClass] on test number [%d] expected IsTriggered = (%t)", testIndex+1, testCase.isTriggered)
+		}
+
+		if class := ClassOf(testCase.messageType, testCase.sequenceNumber); class != testCase.class {
+			t.Errorf("[TestMessageClass] on test number [%d] expected class (%s), got (%s)", testIndex+1, testCase.class, class)
+		}
+	}
+
+	if triggered := TriggeredRequestTypesFor(ModifyBearerCommand); len(triggered) != 1 || triggered[0] != UpdateBearerRequest {
+		t.Errorf("[TestMessageClass] expected Modify Bearer Command to trigger Update Bearer Request")
+	}
+
+	peer := &net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: 2123}
+
+	command := NewPDU(BearerResourceCommand, 0x800010, []*IE{}).AddTEID(1)
+	triggeredRequest := NewPDU(CreateBearerRequest, 0x800010, []*IE{}).AddTEID(2)
+	triggeredReply := NewPDU(CreateBearerResponse, 0x800010, []*IE{}).AddTEID(3)
+	peerRequest := NewPDU(CreateBearerRequest, 0x000010, []*IE{}).AddTEID(2)
+	reply := NewPDU(CreateBearerResponse, 0x000010, []*IE{}).AddTEID(3)
+
+	commandKey := CorrelationKeyFor(peer, command, true)
+	if !commandKey.InitiatedLocally || commandKey.SequenceNumber != 0x800010 || commandKey.Peer != "10.0.0.1:2123" {
+		t.Errorf("[TestMessageClass] key for sent command is not correct")
+	}
+
+	if CorrelationKeyFor(peer, triggeredRequest, false) != commandKey || CorrelationKeyFor(peer, triggeredReply, true) != commandKey {
+		t.Errorf("[TestMessageClass] expected triggered request and reply to have key of command")
+	}
+
+	peerKey := CorrelationKeyFor(peer, peerRequest, false)
+	if peerKey.InitiatedLocally || CorrelationKeyFor(peer, reply, true) != peerKey {
+		t.Errorf("[TestMessageClass] expected received request and sent response to have the same, remotely initiated, key")
+	}
+}
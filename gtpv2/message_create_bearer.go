@@ -0,0 +1,137 @@
+package gtpv2
+
+// TypedCreateBearerRequest is a structured version of a Create Bearer Request
+// (TS 29.274 Table 7.2.3-1).  The field conventions are the same as for
+// TypedCreateSessionRequest.
+type TypedCreateBearerRequest struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	PTI             *uint8
+	LinkedEBI       *uint8
+	PCO             *TypedPCO
+	BearerContexts  []*TypedBearerContext
+	IndicationFlags *TypedIndication
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns CreateBearerRequest
+func (message *TypedCreateBearerRequest) MessageType() MessageType {
+	return CreateBearerRequest
+}
+
+// NewBearerContext appends a Bearer Context to BearerContexts, and returns
+// it.  The Bearer Context has its MessageType and Instance set, so that
+// F-TEIDs can be set by role.
+func (message *TypedCreateBearerRequest) NewBearerContext(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContexts, CreateBearerRequest, 0, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedCreateBearerRequest) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.addUint8(ProcedureTransactionID, message.PTI, 0)
+	encoder.addUint8(EBI, message.LinkedEBI, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.addBearerContexts("Bearer Context", message.BearerContexts, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+
+	return encoder.pdu(CreateBearerRequest, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Create Bearer Request, or if a modelled IE cannot be decoded.
+func (message *TypedCreateBearerRequest) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, CreateBearerRequest)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedCreateBearerRequest{
+		TEID:            pdu.TEID,
+		SequenceNumber:  pdu.SequenceNumber,
+		PTI:             decoder.uint8Value(ProcedureTransactionID, 0),
+		LinkedEBI:       decoder.uint8Value(EBI, 0),
+		PCO:             decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		BearerContexts:  decoder.bearerContexts(CreateBearerRequest, 0),
+		IndicationFlags: decodeTyped(decoder, Indication, 0, makeTypedIndication),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
+
+// TypedCreateBearerResponse is a structured version of a Create Bearer
+// Response (TS 29.274 Table 7.2.4-1).  The field conventions are the same as
+// for TypedCreateSessionRequest.
+type TypedCreateBearerResponse struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause          *TypedCause
+	BearerContexts []*TypedBearerContext
+	Recovery       *uint8
+	PCO            *TypedPCO
+	ULI            *TypedULI
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns CreateBearerResponse
+func (message *TypedCreateBearerResponse) MessageType() MessageType {
+	return CreateBearerResponse
+}
+
+// NewBearerContext appends a Bearer Context to BearerContexts, and returns
+// it.  The Bearer Context has its MessageType and Instance set, so that
+// F-TEIDs can be set by role.
+func (message *TypedCreateBearerResponse) NewBearerContext(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContexts, CreateBearerResponse, 0, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedCreateBearerResponse) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.addBearerContexts("Bearer Context", message.BearerContexts, 0)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.add("ULI", message.ULI, 0)
+
+	return encoder.pdu(CreateBearerResponse, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Create Bearer Response, or if a modelled IE cannot be decoded.
+func (message *TypedCreateBearerResponse) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, CreateBearerResponse)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedCreateBearerResponse{
+		TEID:           pdu.TEID,
+		SequenceNumber: pdu.SequenceNumber,
+		Cause:          decodeTyped(decoder, Cause, 0, makeTypedCause),
+		BearerContexts: decoder.bearerContexts(CreateBearerResponse, 0),
+		Recovery:       decoder.uint8Value(RecoveryRestartCounter, 0),
+		PCO:            decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		ULI:            decodeTyped(decoder, ULI, 0, makeTypedULI),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
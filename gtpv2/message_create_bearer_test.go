@@ -0,0 +1,93 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blorticus-go/gtp/tft"
+	"github.com/go-test/deep"
+)
+
+func TestTypedCreateBearerRequest(t *testing.T) {
+	chargingID := uint32(0x00000101)
+
+	request := &TypedCreateBearerRequest{
+		TEID:           0x01010101,
+		SequenceNumber: 0x00000030,
+		PTI:            uint8Pointer(0),
+		LinkedEBI:      uint8Pointer(5),
+		AdditionalIEs:  []*IE{},
+	}
+
+	for _, ebi := range []uint8{6, 7} {
+		bearerContext := request.NewBearerContext(ebi)
+		bearerContext.BearerQoS = &TypedBearerQoS{PriorityLevel: 2, QCI: 1, MaximumBitRateUplink: 64, MaximumBitRateDownlink: 64, GuaranteedBitRateUplink: 64, GuaranteedBitRateDownlink: 64}
+		bearerContext.ChargingID = &chargingID
+		bearerContext.TFT = &tft.TFT{
+			Operation: tft.OperationCreateNewTFT,
+			PacketFilters: []*tft.PacketFilter{
+				{
+					Identifier: 1,
+					Direction:  tft.DirectionBidirectional,
+					Precedence: ebi,
+					Components: []tft.Component{
+						&tft.IPv4RemoteAddress{Address: net.IP{10, 0, 0, ebi}, Mask: net.IPMask{0xff, 0xff, 0xff, 0xff}},
+					},
+				},
+			},
+			Parameters: []*tft.Parameter{},
+		}
+
+		if err := bearerContext.SetFTEID(FTEIDRoleS1USGW, &TypedFTEID{InterfaceType: 1, Key: uint32(ebi), IPv4Addr: net.IP{10, 0, 0, 3}}); err != nil {
+			t.Fatalf("[TestTypedCreateBearerRequest] expected no error on SetFTEID, got error = (%s)", err.Error())
+		}
+	}
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedCreateBearerRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	decodedPDU, _, err := DecodePDU(pdu.Encode())
+	if err != nil {
+		t.Fatalf("[TestTypedCreateBearerRequest] expected no error on DecodePDU, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedCreateBearerRequest{}
+	if err := unmarshaled.Unmarshal(decodedPDU); err != nil {
+		t.Fatalf("[TestTypedCreateBearerRequest] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(request, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedCreateBearerRequest] on Unmarshal: %s", diff)
+	}
+}
+
+func TestTypedCreateBearerResponse(t *testing.T) {
+	response := &TypedCreateBearerResponse{
+		TEID:           0x02020202,
+		SequenceNumber: 0x00000030,
+		Cause:          &TypedCause{Value: CauseRequestAccepted},
+		AdditionalIEs:  []*IE{},
+	}
+
+	bearerContext := response.NewBearerContext(6)
+	bearerContext.Cause = &TypedCause{Value: CauseRequestAccepted}
+	if err := bearerContext.SetFTEID(FTEIDRoleS1UENodeB, &TypedFTEID{InterfaceType: 0, Key: 0x06060606, IPv4Addr: net.IP{172, 19, 1, 178}}); err != nil {
+		t.Fatalf("[TestTypedCreateBearerResponse] expected no error on SetFTEID, got error = (%s)", err.Error())
+	}
+
+	pdu, err := response.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedCreateBearerResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedCreateBearerResponse{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedCreateBearerResponse] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(response, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedCreateBearerResponse] on Unmarshal: %s", diff)
+	}
+}
@@ -0,0 +1,253 @@
+package gtpv2
+
+// TypedCreateSessionRequest is a structured version of a Create Session
+// Request (TS 29.274 Table 7.2.1-1).  The IE fields are pointers (or slices)
+// and are nil when the IE is absent.  Fields for IEs that carry a single
+// integer (e.g., RATType) hold the value of the IE data.  Where the instance
+// number of an IE is not 0, it is noted on the field.
+type TypedCreateSessionRequest struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	IMSI           *TypedIMSI
+	MSISDN         *TypedMSISDN
+	MEI            *TypedMEI
+	ULI            *TypedULI
+	ServingNetwork *TypedServingNetwork
+	RATType        *uint8
+	Indication     *TypedIndication
+
+	SenderFTEIDForControlPlane *TypedFTEID
+	// PGWS5S8AddressForControlPlane has instance 1
+	PGWS5S8AddressForControlPlane *TypedFTEID
+
+	APN                   *TypedAPN
+	SelectionMode         *uint8
+	PDNType               *TypedPDNType
+	PAA                   *TypedPAA
+	MaximumAPNRestriction *uint8
+	APNAMBR               *TypedAMBR
+	LinkedEBI             *uint8
+	PCO                   *TypedPCO
+	APCO                  *TypedAPCO
+	EPCO                  *TypedEPCO
+
+	BearerContextsToBeCreated []*TypedBearerContext
+	// BearerContextsToBeRemoved have instance 1
+	BearerContextsToBeRemoved []*TypedBearerContext
+
+	Recovery                *uint8
+	ChargingCharacteristics *uint16
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns CreateSessionRequest
+func (message *TypedCreateSessionRequest) MessageType() MessageType {
+	return CreateSessionRequest
+}
+
+// NewBearerContextToBeCreated appends a Bearer Context to
+// BearerContextsToBeCreated, and returns it.  The Bearer Context has its
+// MessageType and Instance set, so that F-TEIDs can be set by role.
+func (message *TypedCreateSessionRequest) NewBearerContextToBeCreated(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsToBeCreated, CreateSessionRequest, 0, ebi)
+}
+
+// NewBearerContextToBeRemoved appends a Bearer Context to
+// BearerContextsToBeRemoved, and returns it
+func (message *TypedCreateSessionRequest) NewBearerContextToBeRemoved(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsToBeRemoved, CreateSessionRequest, 1, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedCreateSessionRequest) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("IMSI", message.IMSI, 0)
+	encoder.add("MSISDN", message.MSISDN, 0)
+	encoder.add("MEI", message.MEI, 0)
+	encoder.add("ULI", message.ULI, 0)
+	encoder.add("Serving Network", message.ServingNetwork, 0)
+	encoder.addUint8(RATType, message.RATType, 0)
+	encoder.add("Indication", message.Indication, 0)
+	encoder.add("Sender F-TEID for Control Plane", message.SenderFTEIDForControlPlane, 0)
+	encoder.add("PGW S5/S8 Address for Control Plane", message.PGWS5S8AddressForControlPlane, 1)
+	encoder.add("APN", message.APN, 0)
+	encoder.addUint8(SelectionMode, message.SelectionMode, 0)
+	encoder.add("PDN Type", message.PDNType, 0)
+	encoder.add("PAA", message.PAA, 0)
+	encoder.addUint8(APNRestriction, message.MaximumAPNRestriction, 0)
+	encoder.add("APN-AMBR", message.APNAMBR, 0)
+	encoder.addUint8(EBI, message.LinkedEBI, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.addBearerContexts("Bearer Context to be created", message.BearerContextsToBeCreated, 0)
+	encoder.addBearerContexts("Bearer Context to be removed", message.BearerContextsToBeRemoved, 1)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.addUint16(ChargingCharacteristics, message.ChargingCharacteristics, 0)
+	encoder.add("APCO", message.APCO, 0)
+	encoder.add("ePCO", message.EPCO, 0)
+
+	return encoder.pdu(CreateSessionRequest, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Create Session Request, or if a modelled IE cannot be decoded.
+func (message *TypedCreateSessionRequest) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, CreateSessionRequest)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedCreateSessionRequest{
+		TEID:                          pdu.TEID,
+		SequenceNumber:                pdu.SequenceNumber,
+		IMSI:                          decodeTyped(decoder, IMSI, 0, makeTypedIMSI),
+		MSISDN:                        decodeTyped(decoder, MSISDN, 0, makeTypedMSISDN),
+		MEI:                           decodeTyped(decoder, MEI, 0, makeTypedMEI),
+		ULI:                           decodeTyped(decoder, ULI, 0, makeTypedULI),
+		ServingNetwork:                decodeTyped(decoder, ServingNetwork, 0, makeTypedServingNetwork),
+		RATType:                       decoder.uint8Value(RATType, 0),
+		Indication:                    decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		SenderFTEIDForControlPlane:    decodeTyped(decoder, FTEID, 0, makeTypedFTEID),
+		PGWS5S8AddressForControlPlane: decodeTyped(decoder, FTEID, 1, makeTypedFTEID),
+		APN:                           decodeTyped(decoder, APN, 0, makeTypedAPN),
+		SelectionMode:                 decoder.uint8Value(SelectionMode, 0),
+		PDNType:                       decodeTyped(decoder, PDNType, 0, makeTypedPDNType),
+		PAA:                           decodeTyped(decoder, PAA, 0, makeTypedPAA),
+		MaximumAPNRestriction:         decoder.uint8Value(APNRestriction, 0),
+		APNAMBR:                       decodeTyped(decoder, AMBR, 0, makeTypedAMBR),
+		LinkedEBI:                     decoder.uint8Value(EBI, 0),
+		PCO:                           decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		APCO:                          decodeTyped(decoder, APCO, 0, makeTypedAPCO),
+		EPCO:                          decodeTyped(decoder, ePCO, 0, makeTypedEPCO),
+		BearerContextsToBeCreated:     decoder.bearerContexts(CreateSessionRequest, 0),
+		BearerContextsToBeRemoved:     decoder.bearerContexts(CreateSessionRequest, 1),
+		Recovery:                      decoder.uint8Value(RecoveryRestartCounter, 0),
+		ChargingCharacteristics:       decoder.uint16Value(ChargingCharacteristics, 0),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
+
+// TypedCreateSessionResponse is a structured version of a Create Session
+// Response (TS 29.274 Table 7.2.2-1).  The field conventions are the same as
+// for TypedCreateSessionRequest.
+type TypedCreateSessionResponse struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause *TypedCause
+
+	SenderFTEIDForControlPlane *TypedFTEID
+	// PGWS5S8S2bFTEIDForControlPlane has instance 1
+	PGWS5S8S2bFTEIDForControlPlane *TypedFTEID
+
+	PAA            *TypedPAA
+	APNRestriction *uint8
+	APNAMBR        *TypedAMBR
+	LinkedEBI      *uint8
+	PCO            *TypedPCO
+	APCO           *TypedAPCO
+	EPCO           *TypedEPCO
+
+	BearerContextsCreated []*TypedBearerContext
+	// BearerContextsMarkedForRemoval have instance 1
+	BearerContextsMarkedForRemoval []*TypedBearerContext
+
+	Recovery                   *uint8
+	ChargingGatewayName        *TypedFQDN
+	IndicationFlags            *TypedIndication
+	PGWNodeName                *TypedFQDN
+	ChargingIDForDefaultBearer *uint32
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns CreateSessionResponse
+func (message *TypedCreateSessionResponse) MessageType() MessageType {
+	return CreateSessionResponse
+}
+
+// NewBearerContextCreated appends a Bearer Context to BearerContextsCreated,
+// and returns it.  The Bearer Context has its MessageType and Instance set,
+// so that F-TEIDs can be set by role.
+func (message *TypedCreateSessionResponse) NewBearerContextCreated(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsCreated, CreateSessionResponse, 0, ebi)
+}
+
+// NewBearerContextMarkedForRemoval appends a Bearer Context to
+// BearerContextsMarkedForRemoval, and returns it
+func (message *TypedCreateSessionResponse) NewBearerContextMarkedForRemoval(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsMarkedForRemoval, CreateSessionResponse, 1, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedCreateSessionResponse) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.add("Sender F-TEID for Control Plane", message.SenderFTEIDForControlPlane, 0)
+	encoder.add("PGW S5/S8/S2b F-TEID for Control Plane", message.PGWS5S8S2bFTEIDForControlPlane, 1)
+	encoder.add("PAA", message.PAA, 0)
+	encoder.addUint8(APNRestriction, message.APNRestriction, 0)
+	encoder.add("APN-AMBR", message.APNAMBR, 0)
+	encoder.addUint8(EBI, message.LinkedEBI, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.addBearerContexts("Bearer Context created", message.BearerContextsCreated, 0)
+	encoder.addBearerContexts("Bearer Context marked for removal", message.BearerContextsMarkedForRemoval, 1)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.add("Charging Gateway Name", message.ChargingGatewayName, 0)
+	encoder.add("APCO", message.APCO, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+	encoder.add("PGW Node Name", message.PGWNodeName, 1)
+	encoder.addUint32(ChargingID, message.ChargingIDForDefaultBearer, 0)
+	encoder.add("ePCO", message.EPCO, 0)
+
+	return encoder.pdu(CreateSessionResponse, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Create Session Response, or if a modelled IE cannot be decoded.
+func (message *TypedCreateSessionResponse) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, CreateSessionResponse)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedCreateSessionResponse{
+		TEID:                           pdu.TEID,
+		SequenceNumber:                 pdu.SequenceNumber,
+		Cause:                          decodeTyped(decoder, Cause, 0, makeTypedCause),
+		SenderFTEIDForControlPlane:     decodeTyped(decoder, FTEID, 0, makeTypedFTEID),
+		PGWS5S8S2bFTEIDForControlPlane: decodeTyped(decoder, FTEID, 1, makeTypedFTEID),
+		PAA:                            decodeTyped(decoder, PAA, 0, makeTypedPAA),
+		APNRestriction:                 decoder.uint8Value(APNRestriction, 0),
+		APNAMBR:                        decodeTyped(decoder, AMBR, 0, makeTypedAMBR),
+		LinkedEBI:                      decoder.uint8Value(EBI, 0),
+		PCO:                            decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		APCO:                           decodeTyped(decoder, APCO, 0, makeTypedAPCO),
+		EPCO:                           decodeTyped(decoder, ePCO, 0, makeTypedEPCO),
+		BearerContextsCreated:          decoder.bearerContexts(CreateSessionResponse, 0),
+		BearerContextsMarkedForRemoval: decoder.bearerContexts(CreateSessionResponse, 1),
+		Recovery:                       decoder.uint8Value(RecoveryRestartCounter, 0),
+		ChargingGatewayName:            decodeTyped(decoder, FQDN, 0, makeTypedFQDN),
+		IndicationFlags:                decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		PGWNodeName:                    decodeTyped(decoder, FQDN, 1, makeTypedFQDN),
+		ChargingIDForDefaultBearer:     decoder.uint32Value(ChargingID, 0),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
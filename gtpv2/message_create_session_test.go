@@ -0,0 +1,148 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func uint8Pointer(value uint8) *uint8 {
+	return &value
+}
+
+func TestTypedCreateSessionRequest(t *testing.T) {
+	request := &TypedCreateSessionRequest{
+		TEID:                          0,
+		SequenceNumber:                0x000a0b0c,
+		IMSI:                          &TypedIMSI{AsString: "001010123456789"},
+		MSISDN:                        &TypedMSISDN{AsString: "15555550100"},
+		ServingNetwork:                &TypedServingNetwork{},
+		RATType:                       uint8Pointer(6),
+		Indication:                    NewTypedIndication(IndicationMSV),
+		SenderFTEIDForControlPlane:    &TypedFTEID{InterfaceType: 10, Key: 0x01010101, IPv4Addr: net.IP{10, 0, 0, 1}},
+		PGWS5S8AddressForControlPlane: &TypedFTEID{InterfaceType: 7, Key: 0, IPv4Addr: net.IP{10, 0, 0, 2}},
+		APN:                           &TypedAPN{AsString: "internet"},
+		SelectionMode:                 uint8Pointer(0),
+		PDNType:                       &TypedPDNType{Value: PDNTypeIPv4},
+		PAA:                           &TypedPAA{PDNType: PDNTypeIPv4, IPv4Address: net.IP{0, 0, 0, 0}},
+		MaximumAPNRestriction:         uint8Pointer(0),
+		APNAMBR:                       &TypedAMBR{Uplink: 50000, Downlink: 100000},
+		Recovery:                      uint8Pointer(0x95),
+		AdditionalIEs:                 []*IE{NewIEWithRawData(DelayValue, []byte{0x00})},
+	}
+
+	request.ServingNetwork.PLMN.MCC = "001"
+	request.ServingNetwork.PLMN.MNC = "01"
+
+	bearerContext := request.NewBearerContextToBeCreated(5)
+	bearerContext.BearerQoS = &TypedBearerQoS{PriorityLevel: 9, QCI: 9}
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedCreateSessionRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	if pdu.Type != CreateSessionRequest || !pdu.TEIDFieldIsPresent || pdu.SequenceNumber != 0x000a0b0c {
+		t.Errorf("[TestTypedCreateSessionRequest] PDU header from Marshal is not correct")
+	}
+
+	if len(pdu.InformationElements) != 16 {
+		t.Errorf("[TestTypedCreateSessionRequest] expected (16) IEs in PDU, got (%d)", len(pdu.InformationElements))
+	}
+
+	lastIE := pdu.InformationElements[len(pdu.InformationElements)-1]
+	if lastIE.Type != DelayValue {
+		t.Errorf("[TestTypedCreateSessionRequest] expected last IE to be additional IE (Delay Value), got (%s)", NameOfIEForType(lastIE.Type))
+	}
+
+	decodedPDU, _, err := DecodePDU(pdu.Encode())
+	if err != nil {
+		t.Fatalf("[TestTypedCreateSessionRequest] expected no error on DecodePDU, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedCreateSessionRequest{}
+	if err := unmarshaled.Unmarshal(decodedPDU); err != nil {
+		t.Fatalf("[TestTypedCreateSessionRequest] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	request.BearerContextsToBeRemoved = []*TypedBearerContext{}
+
+	if diff := deep.Equal(request, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedCreateSessionRequest] on Unmarshal: %s", diff)
+	}
+
+	if unmarshaled.BearerContextsToBeCreated[0].BearerQoS.QCI != 9 {
+		t.Errorf("[TestTypedCreateSessionRequest] expected Bearer Context to be created QCI (9), got (%d)", unmarshaled.BearerContextsToBeCreated[0].BearerQoS.QCI)
+	}
+
+	if !unmarshaled.Indication.IsSet(IndicationMSV) {
+		t.Errorf("[TestTypedCreateSessionRequest] expected MSV indication flag to be set after Unmarshal")
+	}
+
+	if err := (&TypedCreateSessionRequest{}).Unmarshal(NewPDU(CreateSessionResponse, 1, []*IE{})); err == nil {
+		t.Errorf("[TestTypedCreateSessionRequest] expected error on Unmarshal of Create Session Response, got none")
+	}
+
+	malformed := NewPDU(CreateSessionRequest, 1, []*IE{NewIEWithRawData(ServingNetwork, []byte{0x00, 0xf1})})
+	if err := (&TypedCreateSessionRequest{}).Unmarshal(malformed); err == nil {
+		t.Errorf("[TestTypedCreateSessionRequest] expected error on Unmarshal with short Serving Network, got none")
+	}
+
+	if _, err := (&TypedCreateSessionRequest{SequenceNumber: 0x01000000}).Marshal(); err == nil {
+		t.Errorf("[TestTypedCreateSessionRequest] expected error on Marshal with 25-bit sequence number, got none")
+	}
+
+	if _, err := (&TypedCreateSessionRequest{APN: &TypedAPN{AsString: ".."}}).Marshal(); err == nil {
+		t.Errorf("[TestTypedCreateSessionRequest] expected error on Marshal with invalid APN, got none")
+	}
+}
+
+func TestTypedCreateSessionResponse(t *testing.T) {
+	response := &TypedCreateSessionResponse{
+		TEID:                           0x01010101,
+		SequenceNumber:                 0x000a0b0c,
+		Cause:                          &TypedCause{Value: CauseRequestAccepted},
+		SenderFTEIDForControlPlane:     &TypedFTEID{InterfaceType: 11, Key: 0x02020202, IPv4Addr: net.IP{10, 0, 0, 3}},
+		PGWS5S8S2bFTEIDForControlPlane: &TypedFTEID{InterfaceType: 7, Key: 0x03030303, IPv4Addr: net.IP{10, 0, 0, 2}},
+		PAA:                            &TypedPAA{PDNType: PDNTypeIPv4, IPv4Address: net.IP{192, 0, 2, 10}},
+		APNRestriction:                 uint8Pointer(0),
+		Recovery:                       uint8Pointer(0x20),
+		AdditionalIEs:                  []*IE{},
+	}
+
+	bearerContext := response.NewBearerContextCreated(5)
+	bearerContext.Cause = &TypedCause{Value: CauseRequestAccepted}
+	if err := bearerContext.SetFTEID(FTEIDRoleS1USGW, &TypedFTEID{InterfaceType: 1, Key: 0x04040404, IPv4Addr: net.IP{10, 0, 0, 3}}); err != nil {
+		t.Fatalf("[TestTypedCreateSessionResponse] expected no error on SetFTEID, got error = (%s)", err.Error())
+	}
+
+	pdu, err := response.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedCreateSessionResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	if pdu.TEID != 0x01010101 {
+		t.Errorf("[TestTypedCreateSessionResponse] expected PDU TEID (0x01010101), got (0x%08x)", pdu.TEID)
+	}
+
+	decodedPDU, _, err := DecodePDU(pdu.Encode())
+	if err != nil {
+		t.Fatalf("[TestTypedCreateSessionResponse] expected no error on DecodePDU, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedCreateSessionResponse{}
+	if err := unmarshaled.Unmarshal(decodedPDU); err != nil {
+		t.Fatalf("[TestTypedCreateSessionResponse] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	response.BearerContextsMarkedForRemoval = []*TypedBearerContext{}
+
+	if diff := deep.Equal(response, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedCreateSessionResponse] on Unmarshal: %s", diff)
+	}
+
+	if fteid := unmarshaled.BearerContextsCreated[0].FTEID(FTEIDRoleS1USGW); fteid == nil || fteid.Key != 0x04040404 {
+		t.Errorf("[TestTypedCreateSessionResponse] expected S1-U SGW F-TEID with key (0x04040404) after Unmarshal")
+	}
+}
@@ -0,0 +1,151 @@
+package gtpv2
+
+// TypedDeleteBearerRequest is a structured version of a Delete Bearer Request
+// (TS 29.274 Table 7.2.9.2-1).  The field conventions are the same as for
+// TypedCreateSessionRequest.
+type TypedDeleteBearerRequest struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	LinkedEBI *uint8
+	// EPSBearerIDs holds the value of each EBI IE with instance 1
+	EPSBearerIDs         []uint8
+	FailedBearerContexts []*TypedBearerContext
+	PTI                  *uint8
+	PCO                  *TypedPCO
+	Cause                *TypedCause
+	IndicationFlags      *TypedIndication
+	EPCO                 *TypedEPCO
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns DeleteBearerRequest
+func (message *TypedDeleteBearerRequest) MessageType() MessageType {
+	return DeleteBearerRequest
+}
+
+// NewFailedBearerContext appends a Bearer Context to FailedBearerContexts,
+// and returns it.  The Bearer Context has its MessageType and Instance set.
+func (message *TypedDeleteBearerRequest) NewFailedBearerContext(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.FailedBearerContexts, DeleteBearerRequest, 0, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedDeleteBearerRequest) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.addUint8(EBI, message.LinkedEBI, 0)
+	encoder.addUint8List(EBI, message.EPSBearerIDs, 1)
+	encoder.addBearerContexts("Failed Bearer Context", message.FailedBearerContexts, 0)
+	encoder.addUint8(ProcedureTransactionID, message.PTI, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.add("Cause", message.Cause, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+	encoder.add("ePCO", message.EPCO, 0)
+
+	return encoder.pdu(DeleteBearerRequest, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Delete Bearer Request, or if a modelled IE cannot be decoded.
+func (message *TypedDeleteBearerRequest) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, DeleteBearerRequest)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedDeleteBearerRequest{
+		TEID:                 pdu.TEID,
+		SequenceNumber:       pdu.SequenceNumber,
+		LinkedEBI:            decoder.uint8Value(EBI, 0),
+		EPSBearerIDs:         decoder.uint8Values(EBI, 1),
+		FailedBearerContexts: decoder.bearerContexts(DeleteBearerRequest, 0),
+		PTI:                  decoder.uint8Value(ProcedureTransactionID, 0),
+		PCO:                  decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		Cause:                decodeTyped(decoder, Cause, 0, makeTypedCause),
+		IndicationFlags:      decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		EPCO:                 decodeTyped(decoder, ePCO, 0, makeTypedEPCO),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
+
+// TypedDeleteBearerResponse is a structured version of a Delete Bearer
+// Response (TS 29.274 Table 7.2.10.2-1).  The field conventions are the same
+// as for TypedCreateSessionRequest.
+type TypedDeleteBearerResponse struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause          *TypedCause
+	LinkedEBI      *uint8
+	BearerContexts []*TypedBearerContext
+	Recovery       *uint8
+	PCO            *TypedPCO
+	ULI            *TypedULI
+	EPCO           *TypedEPCO
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns DeleteBearerResponse
+func (message *TypedDeleteBearerResponse) MessageType() MessageType {
+	return DeleteBearerResponse
+}
+
+// NewBearerContext appends a Bearer Context to BearerContexts, and returns
+// it.  The Bearer Context has its MessageType and Instance set.
+func (message *TypedDeleteBearerResponse) NewBearerContext(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContexts, DeleteBearerResponse, 0, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedDeleteBearerResponse) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.addUint8(EBI, message.LinkedEBI, 0)
+	encoder.addBearerContexts("Bearer Context", message.BearerContexts, 0)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.add("ULI", message.ULI, 0)
+	encoder.add("ePCO", message.EPCO, 0)
+
+	return encoder.pdu(DeleteBearerResponse, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Delete Bearer Response, or if a modelled IE cannot be decoded.
+func (message *TypedDeleteBearerResponse) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, DeleteBearerResponse)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedDeleteBearerResponse{
+		TEID:           pdu.TEID,
+		SequenceNumber: pdu.SequenceNumber,
+		Cause:          decodeTyped(decoder, Cause, 0, makeTypedCause),
+		LinkedEBI:      decoder.uint8Value(EBI, 0),
+		BearerContexts: decoder.bearerContexts(DeleteBearerResponse, 0),
+		Recovery:       decoder.uint8Value(RecoveryRestartCounter, 0),
+		PCO:            decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		ULI:            decodeTyped(decoder, ULI, 0, makeTypedULI),
+		EPCO:           decodeTyped(decoder, ePCO, 0, makeTypedEPCO),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
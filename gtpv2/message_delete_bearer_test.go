@@ -0,0 +1,77 @@
+package gtpv2
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTypedDeleteBearerRequest(t *testing.T) {
+	request := &TypedDeleteBearerRequest{
+		TEID:           0x01010101,
+		SequenceNumber: 0x00000050,
+		EPSBearerIDs:   []uint8{6, 7},
+		PTI:            uint8Pointer(0),
+		Cause:          &TypedCause{Value: CauseReactivationRequested},
+		AdditionalIEs:  []*IE{},
+	}
+
+	request.NewFailedBearerContext(8).Cause = &TypedCause{Value: CauseContextNotFound}
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedDeleteBearerRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	ebiInstances := []uint8{}
+	for _, ie := range pdu.InformationElements {
+		if ie.Type == EBI {
+			ebiInstances = append(ebiInstances, ie.InstanceNumber)
+		}
+	}
+
+	if diff := deep.Equal(ebiInstances, []uint8{1, 1}); diff != nil {
+		t.Errorf("[TestTypedDeleteBearerRequest] EBI instances in PDU do not match expected: %s", diff)
+	}
+
+	decodedPDU, _, err := DecodePDU(pdu.Encode())
+	if err != nil {
+		t.Fatalf("[TestTypedDeleteBearerRequest] expected no error on DecodePDU, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedDeleteBearerRequest{}
+	if err := unmarshaled.Unmarshal(decodedPDU); err != nil {
+		t.Fatalf("[TestTypedDeleteBearerRequest] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(request, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedDeleteBearerRequest] on Unmarshal: %s", diff)
+	}
+}
+
+func TestTypedDeleteBearerResponse(t *testing.T) {
+	response := &TypedDeleteBearerResponse{
+		TEID:           0x02020202,
+		SequenceNumber: 0x00000050,
+		Cause:          &TypedCause{Value: CauseRequestAccepted},
+		Recovery:       uint8Pointer(1),
+		AdditionalIEs:  []*IE{},
+	}
+
+	response.NewBearerContext(6).Cause = &TypedCause{Value: CauseRequestAccepted}
+	response.NewBearerContext(7).Cause = &TypedCause{Value: CauseRequestAccepted}
+
+	pdu, err := response.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedDeleteBearerResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedDeleteBearerResponse{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedDeleteBearerResponse] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(response, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedDeleteBearerResponse] on Unmarshal: %s", diff)
+	}
+}
@@ -0,0 +1,135 @@
+package gtpv2
+
+// TypedDeleteSessionRequest is a structured version of a Delete Session
+// Request (TS 29.274 Table 7.2.9.1-1).  The field conventions are the same as
+// for TypedCreateSessionRequest.
+type TypedDeleteSessionRequest struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause                      *TypedCause
+	LinkedEBI                  *uint8
+	ULI                        *TypedULI
+	IndicationFlags            *TypedIndication
+	PCO                        *TypedPCO
+	OriginatingNode            *uint8
+	SenderFTEIDForControlPlane *TypedFTEID
+	EPCO                       *TypedEPCO
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns DeleteSessionRequest
+func (message *TypedDeleteSessionRequest) MessageType() MessageType {
+	return DeleteSessionRequest
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedDeleteSessionRequest) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.addUint8(EBI, message.LinkedEBI, 0)
+	encoder.add("ULI", message.ULI, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.addUint8(NodeType, message.OriginatingNode, 0)
+	encoder.add("Sender F-TEID for Control Plane", message.SenderFTEIDForControlPlane, 0)
+	encoder.add("ePCO", message.EPCO, 0)
+
+	return encoder.pdu(DeleteSessionRequest, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Delete Session Request, or if a modelled IE cannot be decoded.
+func (message *TypedDeleteSessionRequest) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, DeleteSessionRequest)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedDeleteSessionRequest{
+		TEID:                       pdu.TEID,
+		SequenceNumber:             pdu.SequenceNumber,
+		Cause:                      decodeTyped(decoder, Cause, 0, makeTypedCause),
+		LinkedEBI:                  decoder.uint8Value(EBI, 0),
+		ULI:                        decodeTyped(decoder, ULI, 0, makeTypedULI),
+		IndicationFlags:            decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		PCO:                        decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		OriginatingNode:            decoder.uint8Value(NodeType, 0),
+		SenderFTEIDForControlPlane: decodeTyped(decoder, FTEID, 0, makeTypedFTEID),
+		EPCO:                       decodeTyped(decoder, ePCO, 0, makeTypedEPCO),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
+
+// TypedDeleteSessionResponse is a structured version of a Delete Session
+// Response (TS 29.274 Table 7.2.10.1-1).  The field conventions are the same
+// as for TypedCreateSessionRequest.
+type TypedDeleteSessionResponse struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause           *TypedCause
+	Recovery        *uint8
+	PCO             *TypedPCO
+	IndicationFlags *TypedIndication
+	APCO            *TypedAPCO
+	EPCO            *TypedEPCO
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns DeleteSessionResponse
+func (message *TypedDeleteSessionResponse) MessageType() MessageType {
+	return DeleteSessionResponse
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedDeleteSessionResponse) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+	encoder.add("APCO", message.APCO, 0)
+	encoder.add("ePCO", message.EPCO, 0)
+
+	return encoder.pdu(DeleteSessionResponse, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Delete Session Response, or if a modelled IE cannot be decoded.
+func (message *TypedDeleteSessionResponse) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, DeleteSessionResponse)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedDeleteSessionResponse{
+		TEID:            pdu.TEID,
+		SequenceNumber:  pdu.SequenceNumber,
+		Cause:           decodeTyped(decoder, Cause, 0, makeTypedCause),
+		Recovery:        decoder.uint8Value(RecoveryRestartCounter, 0),
+		PCO:             decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		IndicationFlags: decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		APCO:            decodeTyped(decoder, APCO, 0, makeTypedAPCO),
+		EPCO:            decodeTyped(decoder, ePCO, 0, makeTypedEPCO),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
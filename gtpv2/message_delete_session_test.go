@@ -0,0 +1,68 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTypedDeleteSessionRequest(t *testing.T) {
+	request := &TypedDeleteSessionRequest{
+		TEID:                       0x01010101,
+		SequenceNumber:             0x00000010,
+		LinkedEBI:                  uint8Pointer(5),
+		IndicationFlags:            NewTypedIndication(IndicationOI),
+		OriginatingNode:            uint8Pointer(0),
+		SenderFTEIDForControlPlane: &TypedFTEID{InterfaceType: 10, Key: 0x04040404, IPv4Addr: net.IP{10, 0, 0, 1}},
+		AdditionalIEs:              []*IE{NewIEWithRawData(UETimeZone, []byte{0x40, 0x00})},
+	}
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedDeleteSessionRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	decodedPDU, _, err := DecodePDU(pdu.Encode())
+	if err != nil {
+		t.Fatalf("[TestTypedDeleteSessionRequest] expected no error on DecodePDU, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedDeleteSessionRequest{}
+	if err := unmarshaled.Unmarshal(decodedPDU); err != nil {
+		t.Fatalf("[TestTypedDeleteSessionRequest] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(request, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedDeleteSessionRequest] on Unmarshal: %s", diff)
+	}
+
+	malformed := NewPDU(DeleteSessionRequest, 1, []*IE{NewIEWithRawData(EBI, []byte{})})
+	if err := (&TypedDeleteSessionRequest{}).Unmarshal(malformed); err == nil {
+		t.Errorf("[TestTypedDeleteSessionRequest] expected error on Unmarshal with empty Linked EBI, got none")
+	}
+}
+
+func TestTypedDeleteSessionResponse(t *testing.T) {
+	response := &TypedDeleteSessionResponse{
+		TEID:           0x02020202,
+		SequenceNumber: 0x00000010,
+		Cause:          &TypedCause{Value: CauseRequestAccepted},
+		Recovery:       uint8Pointer(0x20),
+		AdditionalIEs:  []*IE{},
+	}
+
+	pdu, err := response.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedDeleteSessionResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedDeleteSessionResponse{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedDeleteSessionResponse] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(response, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedDeleteSessionResponse] on Unmarshal: %s", diff)
+	}
+}
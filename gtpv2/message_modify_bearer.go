@@ -0,0 +1,195 @@
+package gtpv2
+
+// TypedModifyBearerRequest is a structured version of a Modify Bearer Request
+// (TS 29.274 Table 7.2.7-1).  The field conventions are the same as for
+// TypedCreateSessionRequest.
+type TypedModifyBearerRequest struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	MEI                        *TypedMEI
+	ULI                        *TypedULI
+	ServingNetwork             *TypedServingNetwork
+	RATType                    *uint8
+	IndicationFlags            *TypedIndication
+	SenderFTEIDForControlPlane *TypedFTEID
+	APNAMBR                    *TypedAMBR
+	// DelayDownlinkPacketNotificationRequest is a Delay Value IE
+	DelayDownlinkPacketNotificationRequest *uint8
+
+	BearerContextsToBeModified []*TypedBearerContext
+	// BearerContextsToBeRemoved have instance 1
+	BearerContextsToBeRemoved []*TypedBearerContext
+
+	Recovery *uint8
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns ModifyBearerRequest
+func (message *TypedModifyBearerRequest) MessageType() MessageType {
+	return ModifyBearerRequest
+}
+
+// NewBearerContextToBeModified appends a Bearer Context to
+// BearerContextsToBeModified, and returns it.  The Bearer Context has its
+// MessageType and Instance set, so that F-TEIDs can be set by role.
+func (message *TypedModifyBearerRequest) NewBearerContextToBeModified(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsToBeModified, ModifyBearerRequest, 0, ebi)
+}
+
+// NewBearerContextToBeRemoved appends a Bearer Context to
+// BearerContextsToBeRemoved, and returns it
+func (message *TypedModifyBearerRequest) NewBearerContextToBeRemoved(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsToBeRemoved, ModifyBearerRequest, 1, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedModifyBearerRequest) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("MEI", message.MEI, 0)
+	encoder.add("ULI", message.ULI, 0)
+	encoder.add("Serving Network", message.ServingNetwork, 0)
+	encoder.addUint8(RATType, message.RATType, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+	encoder.add("Sender F-TEID for Control Plane", message.SenderFTEIDForControlPlane, 0)
+	encoder.add("APN-AMBR", message.APNAMBR, 0)
+	encoder.addUint8(DelayValue, message.DelayDownlinkPacketNotificationRequest, 0)
+	encoder.addBearerContexts("Bearer Context to be modified", message.BearerContextsToBeModified, 0)
+	encoder.addBearerContexts("Bearer Context to be removed", message.BearerContextsToBeRemoved, 1)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+
+	return encoder.pdu(ModifyBearerRequest, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Modify Bearer Request, or if a modelled IE cannot be decoded.
+func (message *TypedModifyBearerRequest) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, ModifyBearerRequest)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedModifyBearerRequest{
+		TEID:                                   pdu.TEID,
+		SequenceNumber:                         pdu.SequenceNumber,
+		MEI:                                    decodeTyped(decoder, MEI, 0, makeTypedMEI),
+		ULI:                                    decodeTyped(decoder, ULI, 0, makeTypedULI),
+		ServingNetwork:                         decodeTyped(decoder, ServingNetwork, 0, makeTypedServingNetwork),
+		RATType:                                decoder.uint8Value(RATType, 0),
+		IndicationFlags:                        decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		SenderFTEIDForControlPlane:             decodeTyped(decoder, FTEID, 0, makeTypedFTEID),
+		APNAMBR:                                decodeTyped(decoder, AMBR, 0, makeTypedAMBR),
+		DelayDownlinkPacketNotificationRequest: decoder.uint8Value(DelayValue, 0),
+		BearerContextsToBeModified:             decoder.bearerContexts(ModifyBearerRequest, 0),
+		BearerContextsToBeRemoved:              decoder.bearerContexts(ModifyBearerRequest, 1),
+		Recovery:                               decoder.uint8Value(RecoveryRestartCounter, 0),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
+
+// TypedModifyBearerResponse is a structured version of a Modify Bearer
+// Response (TS 29.274 Table 7.2.8-1).  The field conventions are the same as
+// for TypedCreateSessionRequest.
+type TypedModifyBearerResponse struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause          *TypedCause
+	MSISDN         *TypedMSISDN
+	LinkedEBI      *uint8
+	APNRestriction *uint8
+	PCO            *TypedPCO
+
+	BearerContextsModified []*TypedBearerContext
+	// BearerContextsMarkedForRemoval have instance 1
+	BearerContextsMarkedForRemoval []*TypedBearerContext
+
+	ChargingGatewayName *TypedFQDN
+	Recovery            *uint8
+	IndicationFlags     *TypedIndication
+	APCO                *TypedAPCO
+	EPCO                *TypedEPCO
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns ModifyBearerResponse
+func (message *TypedModifyBearerResponse) MessageType() MessageType {
+	return ModifyBearerResponse
+}
+
+// NewBearerContextModified appends a Bearer Context to
+// BearerContextsModified, and returns it.  The Bearer Context has its
+// MessageType and Instance set, so that F-TEIDs can be set by role.
+func (message *TypedModifyBearerResponse) NewBearerContextModified(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsModified, ModifyBearerResponse, 0, ebi)
+}
+
+// NewBearerContextMarkedForRemoval appends a Bearer Context to
+// BearerContextsMarkedForRemoval, and returns it
+func (message *TypedModifyBearerResponse) NewBearerContextMarkedForRemoval(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContextsMarkedForRemoval, ModifyBearerResponse, 1, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedModifyBearerResponse) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.add("MSISDN", message.MSISDN, 0)
+	encoder.addUint8(EBI, message.LinkedEBI, 0)
+	encoder.addUint8(APNRestriction, message.APNRestriction, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.addBearerContexts("Bearer Context modified", message.BearerContextsModified, 0)
+	encoder.addBearerContexts("Bearer Context marked for removal", message.BearerContextsMarkedForRemoval, 1)
+	encoder.add("Charging Gateway Name", message.ChargingGatewayName, 0)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+	encoder.add("APCO", message.APCO, 0)
+	encoder.add("ePCO", message.EPCO, 0)
+
+	return encoder.pdu(ModifyBearerResponse, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Modify Bearer Response, or if a modelled IE cannot be decoded.
+func (message *TypedModifyBearerResponse) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, ModifyBearerResponse)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedModifyBearerResponse{
+		TEID:                           pdu.TEID,
+		SequenceNumber:                 pdu.SequenceNumber,
+		Cause:                          decodeTyped(decoder, Cause, 0, makeTypedCause),
+		MSISDN:                         decodeTyped(decoder, MSISDN, 0, makeTypedMSISDN),
+		LinkedEBI:                      decoder.uint8Value(EBI, 0),
+		APNRestriction:                 decoder.uint8Value(APNRestriction, 0),
+		PCO:                            decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		BearerContextsModified:         decoder.bearerContexts(ModifyBearerResponse, 0),
+		BearerContextsMarkedForRemoval: decoder.bearerContexts(ModifyBearerResponse, 1),
+		ChargingGatewayName:            decodeTyped(decoder, FQDN, 0, makeTypedFQDN),
+		Recovery:                       decoder.uint8Value(RecoveryRestartCounter, 0),
+		IndicationFlags:                decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		APCO:                           decodeTyped(decoder, APCO, 0, makeTypedAPCO),
+		EPCO:                           decodeTyped(decoder, ePCO, 0, makeTypedEPCO),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTypedModifyBearerRequest(t *testing.T) {
+	request := &TypedModifyBearerRequest{
+		TEID:                                   0x01010101,
+		SequenceNumber:                         0x00001acc,
+		RATType:                                uint8Pointer(6),
+		IndicationFlags:                        NewTypedIndication(IndicationOI),
+		DelayDownlinkPacketNotificationRequest: uint8Pointer(0),
+		Recovery:                               uint8Pointer(0x95),
+		AdditionalIEs:                          []*IE{},
+	}
+
+	toBeModified := request.NewBearerContextToBeModified(5)
+	if err := toBeModified.SetFTEID(FTEIDRoleS1UENodeB, &TypedFTEID{InterfaceType: 0, Key: 0x00e403fb, IPv4Addr: net.IP{172, 19, 1, 178}}); err != nil {
+		t.Fatalf("[TestTypedModifyBearerRequest] expected no error on SetFTEID, got error = (%s)", err.Error())
+	}
+
+	request.NewBearerContextToBeRemoved(6)
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedModifyBearerRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	bearerContextInstances := []uint8{}
+	for _, ie := range pdu.InformationElements {
+		if ie.Type == BearerContext {
+			bearerContextInstances = append(bearerContextInstances, ie.InstanceNumber)
+		}
+	}
+
+	if diff := deep.Equal(bearerContextInstances, []uint8{0, 1}); diff != nil {
+		t.Errorf("[TestTypedModifyBearerRequest] Bearer Context instances in PDU do not match expected: %s", diff)
+	}
+
+	decodedPDU, _, err := DecodePDU(pdu.Encode())
+	if err != nil {
+		t.Fatalf("[TestTypedModifyBearerRequest] expected no error on DecodePDU, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedModifyBearerRequest{}
+	if err := unmarshaled.Unmarshal(decodedPDU); err != nil {
+		t.Fatalf("[TestTypedModifyBearerRequest] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(request, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedModifyBearerRequest] on Unmarshal: %s", diff)
+	}
+
+	if err := (&TypedModifyBearerRequest{}).Unmarshal(NewPDU(ModifyBearerResponse, 1, []*IE{})); err == nil {
+		t.Errorf("[TestTypedModifyBearerRequest] expected error on Unmarshal of Modify Bearer Response, got none")
+	}
+}
+
+func TestTypedModifyBearerResponse(t *testing.T) {
+	response := &TypedModifyBearerResponse{
+		TEID:           0x02020202,
+		SequenceNumber: 0x00001acc,
+		Cause:          &TypedCause{Value: CauseRequestAccepted},
+		MSISDN:         &TypedMSISDN{AsString: "15555550100"},
+		LinkedEBI:      uint8Pointer(5),
+		AdditionalIEs:  []*IE{NewIEWithRawData(FQCSID, []byte{0x01, 10, 0, 0, 1, 0x00, 0x01})},
+	}
+
+	modified := response.NewBearerContextModified(5)
+	modified.Cause = &TypedCause{Value: CauseRequestAccepted}
+	if err := modified.SetFTEID(FTEIDRoleS1USGW, &TypedFTEID{InterfaceType: 1, Key: 0x03030303, IPv4Addr: net.IP{10, 0, 0, 3}}); err != nil {
+		t.Fatalf("[TestTypedModifyBearerResponse] expected no error on SetFTEID, got error = (%s)", err.Error())
+	}
+
+	pdu, err := response.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedModifyBearerResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedModifyBearerResponse{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedModifyBearerResponse] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	response.BearerContextsMarkedForRemoval = []*TypedBearerContext{}
+
+	if diff := deep.Equal(response, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedModifyBearerResponse] on Unmarshal: %s", diff)
+	}
+}
@@ -0,0 +1,112 @@
+package gtpv2
+
+// TypedReleaseAccessBearersRequest is a structured version of a Release Access
+// Bearers Request (TS 29.274 Table 7.2.21-1).  The field conventions are the
+// same as for TypedCreateSessionRequest.
+type TypedReleaseAccessBearersRequest struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	// ListOfRABs holds the value of each EBI IE in the List of RABs
+	ListOfRABs      []uint8
+	OriginatingNode *uint8
+	IndicationFlags *TypedIndication
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns ReleaseAccessBearersRequest
+func (message *TypedReleaseAccessBearersRequest) MessageType() MessageType {
+	return ReleaseAccessBearersRequest
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedReleaseAccessBearersRequest) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.addUint8List(EBI, message.ListOfRABs, 0)
+	encoder.addUint8(NodeType, message.OriginatingNode, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+
+	return encoder.pdu(ReleaseAccessBearersRequest, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Release Access Bearers Request, or if a modelled IE cannot be decoded.
+func (message *TypedReleaseAccessBearersRequest) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, ReleaseAccessBearersRequest)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedReleaseAccessBearersRequest{
+		TEID:            pdu.TEID,
+		SequenceNumber:  pdu.SequenceNumber,
+		ListOfRABs:      decoder.uint8Values(EBI, 0),
+		OriginatingNode: decoder.uint8Value(NodeType, 0),
+		IndicationFlags: decodeTyped(decoder, Indication, 0, makeTypedIndication),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
+
+// TypedReleaseAccessBearersResponse is a structured version of a Release
+// Access Bearers Response (TS 29.274 Table 7.2.22-1).  The field conventions
+// are the same as for TypedCreateSessionRequest.
+type TypedReleaseAccessBearersResponse struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause           *TypedCause
+	Recovery        *uint8
+	IndicationFlags *TypedIndication
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns ReleaseAccessBearersResponse
+func (message *TypedReleaseAccessBearersResponse) MessageType() MessageType {
+	return ReleaseAccessBearersResponse
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedReleaseAccessBearersResponse) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+
+	return encoder.pdu(ReleaseAccessBearersResponse, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not a Release Access Bearers Response, or if a modelled IE cannot be decoded.
+func (message *TypedReleaseAccessBearersResponse) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, ReleaseAccessBearersResponse)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedReleaseAccessBearersResponse{
+		TEID:            pdu.TEID,
+		SequenceNumber:  pdu.SequenceNumber,
+		Cause:           decodeTyped(decoder, Cause, 0, makeTypedCause),
+		Recovery:        decoder.uint8Value(RecoveryRestartCounter, 0),
+		IndicationFlags: decodeTyped(decoder, Indication, 0, makeTypedIndication),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package gtpv2
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTypedReleaseAccessBearersRequest(t *testing.T) {
+	request := &TypedReleaseAccessBearersRequest{
+		TEID:            0x01010101,
+		SequenceNumber:  0x00000020,
+		ListOfRABs:      []uint8{5, 6},
+		OriginatingNode: uint8Pointer(0),
+		AdditionalIEs:   []*IE{},
+	}
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedReleaseAccessBearersRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	if len(pdu.InformationElements) != 3 {
+		t.Errorf("[TestTypedReleaseAccessBearersRequest] expected (3) IEs in PDU, got (%d)", len(pdu.InformationElements))
+	}
+
+	unmarshaled := &TypedReleaseAccessBearersRequest{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedReleaseAccessBearersRequest] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(request, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedReleaseAccessBearersRequest] on Unmarshal: %s", diff)
+	}
+}
+
+func TestTypedReleaseAccessBearersResponse(t *testing.T) {
+	response := &TypedReleaseAccessBearersResponse{
+		TEID:           0x02020202,
+		SequenceNumber: 0x00000020,
+		Cause:          &TypedCause{Value: CauseRequestAccepted},
+		AdditionalIEs:  []*IE{},
+	}
+
+	pdu, err := response.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedReleaseAccessBearersResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedReleaseAccessBearersResponse{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedReleaseAccessBearersResponse] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(response, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedReleaseAccessBearersResponse] on Unmarshal: %s", diff)
+	}
+
+	if err := unmarshaled.Unmarshal(NewPDU(ReleaseAccessBearersRequest, 1, []*IE{})); err == nil {
+		t.Errorf("[TestTypedReleaseAccessBearersResponse] expected error on Unmarshal of Release Access Bearers Request, got none")
+	}
+}
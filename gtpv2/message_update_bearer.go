@@ -0,0 +1,139 @@
+package gtpv2
+
+// TypedUpdateBearerRequest is a structured version of an Update Bearer Request
+// (TS 29.274 Table 7.2.15-1).  The field conventions are the same as for
+// TypedCreateSessionRequest.
+type TypedUpdateBearerRequest struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	BearerContexts  []*TypedBearerContext
+	PTI             *uint8
+	PCO             *TypedPCO
+	APNAMBR         *TypedAMBR
+	IndicationFlags *TypedIndication
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns UpdateBearerRequest
+func (message *TypedUpdateBearerRequest) MessageType() MessageType {
+	return UpdateBearerRequest
+}
+
+// NewBearerContext appends a Bearer Context to BearerContexts, and returns
+// it.  The Bearer Context has its MessageType and Instance set.
+func (message *TypedUpdateBearerRequest) NewBearerContext(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContexts, UpdateBearerRequest, 0, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedUpdateBearerRequest) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.addBearerContexts("Bearer Context", message.BearerContexts, 0)
+	encoder.addUint8(ProcedureTransactionID, message.PTI, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.add("APN-AMBR", message.APNAMBR, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+
+	return encoder.pdu(UpdateBearerRequest, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not an Update Bearer Request, or if a modelled IE cannot be decoded.
+func (message *TypedUpdateBearerRequest) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, UpdateBearerRequest)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedUpdateBearerRequest{
+		TEID:            pdu.TEID,
+		SequenceNumber:  pdu.SequenceNumber,
+		BearerContexts:  decoder.bearerContexts(UpdateBearerRequest, 0),
+		PTI:             decoder.uint8Value(ProcedureTransactionID, 0),
+		PCO:             decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		APNAMBR:         decodeTyped(decoder, AMBR, 0, makeTypedAMBR),
+		IndicationFlags: decodeTyped(decoder, Indication, 0, makeTypedIndication),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
+
+// TypedUpdateBearerResponse is a structured version of an Update Bearer
+// Response (TS 29.274 Table 7.2.16-1).  The field conventions are the same as
+// for TypedCreateSessionRequest.
+type TypedUpdateBearerResponse struct {
+	TEID           uint32
+	SequenceNumber uint32
+
+	Cause           *TypedCause
+	BearerContexts  []*TypedBearerContext
+	PCO             *TypedPCO
+	Recovery        *uint8
+	IndicationFlags *TypedIndication
+	ULI             *TypedULI
+
+	AdditionalIEs []*IE
+}
+
+// MessageType returns UpdateBearerResponse
+func (message *TypedUpdateBearerResponse) MessageType() MessageType {
+	return UpdateBearerResponse
+}
+
+// NewBearerContext appends a Bearer Context to BearerContexts, and returns
+// it.  The Bearer Context has its MessageType and Instance set, so that
+// F-TEIDs can be set by role.
+func (message *TypedUpdateBearerResponse) NewBearerContext(ebi uint8) *TypedBearerContext {
+	return newBearerContextIn(&message.BearerContexts, UpdateBearerResponse, 0, ebi)
+}
+
+// Marshal creates a PDU from the message
+func (message *TypedUpdateBearerResponse) Marshal() (*PDU, error) {
+	encoder := &messageEncoder{}
+
+	encoder.add("Cause", message.Cause, 0)
+	encoder.addBearerContexts("Bearer Context", message.BearerContexts, 0)
+	encoder.add("PCO", message.PCO, 0)
+	encoder.addUint8(RecoveryRestartCounter, message.Recovery, 0)
+	encoder.add("Indication Flags", message.IndicationFlags, 0)
+	encoder.add("ULI", message.ULI, 0)
+
+	return encoder.pdu(UpdateBearerResponse, message.TEID, message.SequenceNumber, message.AdditionalIEs)
+}
+
+// Unmarshal populates the message from a PDU.  Returns an error if the PDU is
+// not an Update Bearer Response, or if a modelled IE cannot be decoded.
+func (message *TypedUpdateBearerResponse) Unmarshal(pdu *PDU) error {
+	decoder, err := newMessageDecoder(pdu, UpdateBearerResponse)
+	if err != nil {
+		return err
+	}
+
+	*message = TypedUpdateBearerResponse{
+		TEID:            pdu.TEID,
+		SequenceNumber:  pdu.SequenceNumber,
+		Cause:           decodeTyped(decoder, Cause, 0, makeTypedCause),
+		BearerContexts:  decoder.bearerContexts(UpdateBearerResponse, 0),
+		PCO:             decodeTyped(decoder, ProtocolConfigurationOptions, 0, makeTypedPCO),
+		Recovery:        decoder.uint8Value(RecoveryRestartCounter, 0),
+		IndicationFlags: decodeTyped(decoder, Indication, 0, makeTypedIndication),
+		ULI:             decodeTyped(decoder, ULI, 0, makeTypedULI),
+	}
+
+	if decoder.err != nil {
+		return decoder.err
+	}
+
+	message.AdditionalIEs = decoder.remaining
+
+	return nil
+}
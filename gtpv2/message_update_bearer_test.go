@@ -0,0 +1,67 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTypedUpdateBearerRequest(t *testing.T) {
+	request := &TypedUpdateBearerRequest{
+		TEID:           0x01010101,
+		SequenceNumber: 0x00000040,
+		PTI:            uint8Pointer(3),
+		APNAMBR:        &TypedAMBR{Uplink: 1000, Downlink: 2000},
+		AdditionalIEs:  []*IE{},
+	}
+
+	request.NewBearerContext(5).BearerQoS = &TypedBearerQoS{PriorityLevel: 9, QCI: 8}
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedUpdateBearerRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedUpdateBearerRequest{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedUpdateBearerRequest] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(request, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedUpdateBearerRequest] on Unmarshal: %s", diff)
+	}
+}
+
+func TestTypedUpdateBearerResponse(t *testing.T) {
+	response := &TypedUpdateBearerResponse{
+		TEID:           0x02020202,
+		SequenceNumber: 0x00000040,
+		Cause:          &TypedCause{Value: CauseRequestAccepted},
+		AdditionalIEs:  []*IE{},
+	}
+
+	bearerContext := response.NewBearerContext(5)
+	bearerContext.Cause = &TypedCause{Value: CauseRequestAccepted}
+	if err := bearerContext.SetFTEID(FTEIDRoleS12RNC, &TypedFTEID{InterfaceType: 14, Key: 0x05050505, IPv4Addr: net.IP{10, 0, 0, 9}}); err != nil {
+		t.Fatalf("[TestTypedUpdateBearerResponse] expected no error on SetFTEID, got error = (%s)", err.Error())
+	}
+
+	pdu, err := response.Marshal()
+	if err != nil {
+		t.Fatalf("[TestTypedUpdateBearerResponse] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	unmarshaled := &TypedUpdateBearerResponse{}
+	if err := unmarshaled.Unmarshal(pdu); err != nil {
+		t.Fatalf("[TestTypedUpdateBearerResponse] expected no error on Unmarshal, got error = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(response, unmarshaled); diff != nil {
+		t.Errorf("[TestTypedUpdateBearerResponse] on Unmarshal: %s", diff)
+	}
+
+	if fteid := unmarshaled.BearerContexts[0].FTEIDs[1]; fteid == nil || fteid.Key != 0x05050505 {
+		t.Errorf("[TestTypedUpdateBearerResponse] expected S12 RNC F-TEID at instance (1) after Unmarshal")
+	}
+}
@@ -0,0 +1,322 @@
+package gtpv2
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultEchoInterval is the default time between Echo Requests sent to a
+// peer by a PathManager.  TS 29.274 section 7.8 requires that it be no less
+// than 60 seconds.
+const DefaultEchoInterval = 60 * time.Second
+
+// PathEventType identifies the kind of a PathEvent
+type PathEventType uint8
+
+// Path event types
+const (
+	// PathUp is raised when a peer answers an Echo Request (or sends one) for
+	// the first time, or after the path was down
+	PathUp PathEventType = iota + 1
+	// PathDown is raised when an Echo Request is not answered after
+	// N3Requests retransmissions
+	PathDown
+	// PeerRestarted is raised when the restart counter for a peer changes
+	PeerRestarted
+)
+
+var pathEventTypeNames = []string{"", "Path Up", "Path Down", "Peer Restarted"}
+
+// String returns the name of the event type
+func (eventType PathEventType) String() string {
+	if eventType > 0 && int(eventType) < len(pathEventTypeNames) {
+		return pathEventTypeNames[eventType]
+	}
+
+	return fmt.Sprintf("PathEventType(%d)", uint8(eventType))
+}
+
+// PathEvent is a change in the state of the path to a peer.  For a
+// PeerRestarted event, RestartCounter is the new restart counter value for the
+// peer, and PreviousRestartCounter is the value before the restart.
+type PathEvent struct {
+	Type                   PathEventType
+	Peer                   net.Addr
+	RestartCounter         uint8
+	PreviousRestartCounter uint8
+}
+
+// PathManagerConfig provides the settings for a PathManager
+type PathManagerConfig struct {
+	// EchoInterval is the time between Echo Requests sent to a peer.  The
+	// default is DefaultEchoInterval.
+	EchoInterval time.Duration
+
+	// RestartCounter is the local restart counter, which is sent in the
+	// Recovery IE of Echo Requests and Echo Responses
+	RestartCounter uint8
+
+	// SequenceNumbers allocates the sequence numbers for Echo Requests.  It
+	// should be shared with any other sender of requests over the Transport,
+	// so that sequence numbers are not reused.  If it is nil, the
+	// PathManager creates its own.
+	SequenceNumbers *SequenceNumberManager
+
+	// OnEvent is called for each PathEvent.  It is called from a goroutine of
+	// the PathManager, so it should not block.  It may be nil.
+	OnEvent func(event *PathEvent)
+}
+
+// PathManager maintains the path to each GTPv2 peer using Echo Requests (TS
+// 29.274 section 7.8).  An Echo Request is sent to each peer every
+// EchoInterval over a Transport, and the path is declared down if the request
+// is not answered.  The restart counter in the Recovery IE received from a
+// peer is tracked, and a change raises a PeerRestarted event.  Incoming Echo
+// Requests are not read from the Transport by the PathManager.  Instead, the
+// reader of the Transport should pass them to HandleEchoRequest.
+type PathManager struct {
+	transport *Transport
+	config    PathManagerConfig
+
+	lock   sync.Mutex
+	peers  map[string]*pathPeer
+	closed bool
+}
+
+type pathState uint8
+
+const (
+	pathStateUnknown pathState = iota
+	pathStateUp
+	pathStateDown
+)
+
+type pathPeer struct {
+	address               net.Addr
+	state                 pathState
+	restartCounter        uint8
+	restartCounterIsKnown bool
+	stopMonitoring        context.CancelFunc
+}
+
+// NewPathManager creates a PathManager that sends over transport.  It has no
+// peers until AddPeer is called.
+func NewPathManager(transport *Transport, config PathManagerConfig) *PathManager {
+	if config.EchoInterval <= 0 {
+		config.EchoInterval = DefaultEchoInterval
+	}
+
+	if config.SequenceNumbers == nil {
+		config.SequenceNumbers = NewSequenceNumberManager()
+	}
+
+	return &PathManager{
+		transport: transport,
+		config:    config,
+		peers:     make(map[string]*pathPeer),
+	}
+}
+
+// AddPeer starts sending Echo Requests to a peer.  The first is sent
+// immediately.  Does nothing if the peer is already monitored.
+func (manager *PathManager) AddPeer(peer net.Addr) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if manager.closed {
+		return
+	}
+
+	if _, isKnown := manager.peers[peer.String()]; isKnown {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pathToPeer := &pathPeer{address: peer, stopMonitoring: cancel}
+	manager.peers[peer.String()] = pathToPeer
+
+	go manager.monitor(ctx, pathToPeer)
+}
+
+// RemovePeer stops sending Echo Requests to a peer, and forgets its state
+func (manager *PathManager) RemovePeer(peer net.Addr) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if pathToPeer, isKnown := manager.peers[peer.String()]; isKnown {
+		pathToPeer.stopMonitoring()
+		delete(manager.peers, peer.String())
+	}
+}
+
+// Close stops sending Echo Requests to all peers.  It does not close the
+// Transport.
+func (manager *PathManager) Close() {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	manager.closed = true
+
+	for peerKey, pathToPeer := range manager.peers {
+		pathToPeer.stopMonitoring()
+		delete(manager.peers, peerKey)
+	}
+}
+
+// IsPathUp returns true if the last Echo Request sent to the peer was
+// answered, or if an Echo Request has been received from the peer since that
+// Echo Request was sent.  Returns false for a peer that was not added with
+// AddPeer.
+func (manager *PathManager) IsPathUp(peer net.Addr) bool {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	pathToPeer, isKnown := manager.peers[peer.String()]
+	return isKnown && pathToPeer.state == pathStateUp
+}
+
+// HandleEchoRequest answers an Echo Request received from a peer with an Echo
+// Response carrying the local restart counter.  If the peer was added with
+// AddPeer, the request is also taken as evidence that the path is up, and the
+// restart counter in it is tracked.  Returns an error if the message is not an
+// Echo Request, or if the response cannot be sent.
+func (manager *PathManager) HandleEchoRequest(request *IncomingMessage) error {
+	if request.PDU.Type != EchoRequest {
+		return fmt.Errorf("message type (%s) is not Echo Request", NameOfMessageForType(request.PDU.Type))
+	}
+
+	response := NewPDU(EchoResponse, request.PDU.SequenceNumber, []*IE{
+		NewIEWithRawData(RecoveryRestartCounter, []byte{manager.config.RestartCounter}),
+	})
+
+	if err := manager.transport.SendResponse(request.Peer, response); err != nil {
+		return err
+	}
+
+	manager.setPathState(request.Peer, pathStateUp)
+
+	if restartCounter, isPresent := RestartCounterIn(request.PDU); isPresent {
+		manager.UpdateRestartCounter(request.Peer, restartCounter)
+	}
+
+	return nil
+}
+
+// UpdateRestartCounter records the restart counter received from a peer, and
+// raises a PeerRestarted event if it differs from the last value recorded.
+// It is called for each Recovery IE in an Echo Request or Echo Response, and
+// may also be called for a Recovery IE in any other message from the peer.
+// Does nothing for a peer that was not added with AddPeer.
+func (manager *PathManager) UpdateRestartCounter(peer net.Addr, restartCounter uint8) {
+	manager.lock.Lock()
+
+	pathToPeer, isKnown := manager.peers[peer.String()]
+	if !isKnown {
+		manager.lock.Unlock()
+		return
+	}
+
+	previousRestartCounter := pathToPeer.restartCounter
+	peerHasRestarted := pathToPeer.restartCounterIsKnown && previousRestartCounter != restartCounter
+
+	pathToPeer.restartCounter = restartCounter
+	pathToPeer.restartCounterIsKnown = true
+
+	manager.lock.Unlock()
+
+	if peerHasRestarted {
+		manager.raise(&PathEvent{
+			Type:                   PeerRestarted,
+			Peer:                   peer,
+			RestartCounter:         restartCounter,
+			PreviousRestartCounter: previousRestartCounter,
+		})
+	}
+}
+
+// RestartCounterIn returns the value of the Recovery IE in a PDU, or false if
+// it has no well-formed Recovery IE
+func RestartCounterIn(pdu *PDU) (uint8, bool) {
+	for _, ie := range pdu.InformationElements {
+		if ie.Type == RecoveryRestartCounter && ie.InstanceNumber == 0 {
+			if len(ie.Data) != 1 {
+				return 0, false
+			}
+			return ie.Data[0], true
+		}
+	}
+
+	return 0, false
+}
+
+func (manager *PathManager) monitor(ctx context.Context, pathToPeer *pathPeer) {
+	ticker := time.NewTicker(manager.config.EchoInterval)
+	defer ticker.Stop()
+
+	for {
+		manager.sendEcho(ctx, pathToPeer.address)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (manager *PathManager) sendEcho(ctx context.Context, peer net.Addr) {
+	request := NewPDU(EchoRequest, manager.config.SequenceNumbers.Next(peer, EchoRequest), []*IE{
+		NewIEWithRawData(RecoveryRestartCounter, []byte{manager.config.RestartCounter}),
+	})
+
+	answer, err := manager.transport.SendRequest(ctx, peer, request)
+	if err != nil {
+		if ctx.Err() == nil {
+			manager.setPathState(peer, pathStateDown)
+		}
+		return
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	manager.setPathState(peer, pathStateUp)
+
+	if restartCounter, isPresent := RestartCounterIn(answer.PDU); isPresent {
+		manager.UpdateRestartCounter(peer, restartCounter)
+	}
+}
+
+func (manager *PathManager) setPathState(peer net.Addr, state pathState) {
+	manager.lock.Lock()
+
+	pathToPeer, isKnown := manager.peers[peer.String()]
+	if !isKnown {
+		manager.lock.Unlock()
+		return
+	}
+
+	stateHasChanged := pathToPeer.state != state
+	pathToPeer.state = state
+
+	manager.lock.Unlock()
+
+	if stateHasChanged {
+		eventType := PathUp
+		if state == pathStateDown {
+			eventType = PathDown
+		}
+
+		manager.raise(&PathEvent{Type: eventType, Peer: peer})
+	}
+}
+
+func (manager *PathManager) raise(event *PathEvent) {
+	if manager.config.OnEvent != nil {
+		manager.config.OnEvent(event)
+	}
+}
@@ -0,0 +1,119 @@
+package gtpv2
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func answerEchoRequests(transport *Transport, managers <-chan *PathManager) {
+	manager := <-managers
+
+	for {
+		incoming, err := transport.Receive(context.Background())
+		if err != nil {
+			return
+		}
+
+		select {
+		case manager = <-managers:
+		default:
+		}
+
+		if incoming.PDU.Type == EchoRequest {
+			manager.HandleEchoRequest(incoming)
+		}
+	}
+}
+
+func expectPathEvent(t *testing.T, events <-chan *PathEvent, eventType PathEventType) *PathEvent {
+	select {
+	case event := <-events:
+		if event.Type != eventType {
+			t.Fatalf("[TestPathManager] expected event (%s), got (%s)", eventType, event.Type)
+		}
+		return event
+
+	case <-time.After(2 * time.Second):
+		t.Fatalf("[TestPathManager] expected event (%s), got none", eventType)
+		return nil
+	}
+}
+
+func TestPathManager(t *testing.T) {
+	localConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open local socket: %s", err)
+	}
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open peer socket: %s", err)
+	}
+
+	transportConfig := TransportConfig{T3Response: 20 * time.Millisecond, N3Requests: 1}
+	localTransport := NewTransport(localConn, transportConfig)
+	peerTransport := NewTransport(peerConn, transportConfig)
+	defer localTransport.Close()
+	defer peerTransport.Close()
+
+	events := make(chan *PathEvent, 10)
+	localManager := NewPathManager(localTransport, PathManagerConfig{
+		EchoInterval:   50 * time.Millisecond,
+		RestartCounter: 7,
+		OnEvent:        func(event *PathEvent) { events <- event },
+	})
+	defer localManager.Close()
+
+	peerManagers := make(chan *PathManager, 1)
+	peerManagers <- NewPathManager(peerTransport, PathManagerConfig{RestartCounter: 1})
+	go answerEchoRequests(peerTransport, peerManagers)
+
+	localManager.AddPeer(peerConn.LocalAddr())
+
+	event := expectPathEvent(t, events, PathUp)
+	if event.Peer.String() != peerConn.LocalAddr().String() {
+		t.Errorf("[TestPathManager] expected event for peer (%s), got (%s)", peerConn.LocalAddr(), event.Peer)
+	}
+
+	if !localManager.IsPathUp(peerConn.LocalAddr()) {
+		t.Errorf("[TestPathManager] expected path to be up")
+	}
+
+	// the peer restarts with a new restart counter
+	peerManagers <- NewPathManager(peerTransport, PathManagerConfig{RestartCounter: 2})
+
+	event = expectPathEvent(t, events, PeerRestarted)
+	if event.RestartCounter != 2 || event.PreviousRestartCounter != 1 {
+		t.Errorf("[TestPathManager] expected restart counter to change from (1) to (2), got (%d) to (%d)", event.PreviousRestartCounter, event.RestartCounter)
+	}
+
+	// the peer stops answering
+	peerTransport.Close()
+
+	expectPathEvent(t, events, PathDown)
+
+	if localManager.IsPathUp(peerConn.LocalAddr()) {
+		t.Errorf("[TestPathManager] expected path to be down")
+	}
+
+	if err := localManager.HandleEchoRequest(&IncomingMessage{PDU: NewPDU(CreateSessionRequest, 1, []*IE{}), Peer: peerConn.LocalAddr()}); err == nil {
+		t.Errorf("[TestPathManager] expected error from HandleEchoRequest for Create Session Request, got none")
+	}
+
+	// a peer that was not added with AddPeer is not tracked
+	unknownPeer := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}
+	localManager.UpdateRestartCounter(unknownPeer, 3)
+	localManager.UpdateRestartCounter(unknownPeer, 4)
+
+	select {
+	case event := <-events:
+		t.Errorf("[TestPathManager] expected no event for a peer not added with AddPeer, got (%s)", event.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if localManager.IsPathUp(unknownPeer) {
+		t.Errorf("[TestPathManager] expected path to peer not added with AddPeer to be reported down")
+	}
+}
@@ -12,6 +12,7 @@ type MessageType uint8
 const (
 	EchoRequest                                MessageType = 1
 	EchoResponse                               MessageType = 2
+	VersionNotSupportedIndication              MessageType = 3
 	CreateSessionRequest                       MessageType = 32
 	CreateSessionResponse                      MessageType = 33
 	ModifyBearerRequest                        MessageType = 34
@@ -283,6 +284,10 @@ func DecodePDU(stream []byte) (pdu *PDU, piggybackedPdu *PDU, err error) {
 	} else {
 		piggybackedPduStream := stream[totalPduLength:]
 
+		if len(piggybackedPduStream) == 0 {
+			return nil, nil, fmt.Errorf("GTPv2 PDU has piggyback flag set, but stream contains no piggybacked PDU")
+		}
+
 		if (piggybackedPduStream[0] & 0x10) != 0 {
 			return nil, nil, fmt.Errorf("GTPv2 PDU has piggybacked PDU but the piggyback flag for that piggybacked PDU is not 0")
 		}
@@ -0,0 +1,96 @@
+package gtpv2
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+)
+
+// MaximumSequenceNumber is the largest value of the 24-bit sequence number
+// field
+const MaximumSequenceNumber uint32 = 0x00ffffff
+
+// SequenceNumberManager allocates sequence numbers for messages sent to peers.
+// Each peer has its own sequence number space, and numbers are allocated from
+// it in a cyclic manner.  Because the most significant bit is set only for a
+// command and the requests that it triggers (TS 29.274 section 7.6), the
+// allocated value cycles through the lower 23 bits, and the command flag is
+// added for a command.  The first value for a peer is random, so that the
+// numbers used after a restart are unlikely to match those used before it.  A
+// SequenceNumberManager is safe for concurrent use.
+type SequenceNumberManager struct {
+	lock sync.Mutex
+	next map[string]uint32
+}
+
+// NewSequenceNumberManager creates a SequenceNumberManager with no peers
+func NewSequenceNumberManager() *SequenceNumberManager {
+	return &SequenceNumberManager{
+		next: make(map[string]uint32),
+	}
+}
+
+// Next allocates the sequence number for an Initial message of messageType
+// sent to peer.  CommandSequenceNumberFlag is set if messageType is a command.
+func (manager *SequenceNumberManager) Next(peer net.Addr, messageType MessageType) uint32 {
+	peerKey := peer.String()
+
+	manager.lock.Lock()
+	next, peerIsKnown := manager.next[peerKey]
+	if !peerIsKnown {
+		next = uint32(rand.Int31n(int32(CommandSequenceNumberFlag)))
+	}
+	manager.next[peerKey] = (next + 1) & (CommandSequenceNumberFlag - 1)
+	manager.lock.Unlock()
+
+	if IsCommand(messageType) {
+		return next | CommandSequenceNumberFlag
+	}
+
+	return next
+}
+
+// SequenceNumberFor returns the sequence number for a message of messageType
+// sent to peer.  If triggeringMessage is nil, the message is an Initial
+// message, and the sequence number is allocated as with Next.  Otherwise, the
+// message is a Triggered message or Triggered Reply, and the sequence number
+// is that of triggeringMessage.  Returns an error if messageType cannot be
+// sent in answer to triggeringMessage, or if triggeringMessage is nil and
+// messageType is only sent in answer to another message.
+func (manager *SequenceNumberManager) SequenceNumberFor(peer net.Addr, messageType MessageType, triggeringMessage *PDU) (uint32, error) {
+	if triggeringMessage == nil {
+		if IsResponse(messageType) {
+			return 0, fmt.Errorf("message type (%s) is only sent in answer to another message", NameOfMessageForType(messageType))
+		}
+		return manager.Next(peer, messageType), nil
+	}
+
+	if !CanAnswer(messageType, triggeringMessage) {
+		return 0, fmt.Errorf("message type (%s) cannot be sent in answer to message type (%s) with sequence number (%d)", NameOfMessageForType(messageType), NameOfMessageForType(triggeringMessage.Type), triggeringMessage.SequenceNumber)
+	}
+
+	return triggeringMessage.SequenceNumber, nil
+}
+
+// CanAnswer returns true if a message of messageType may be sent in answer to
+// triggeringMessage, that is, if it is the response to triggeringMessage, or
+// triggeringMessage is a command (with CommandSequenceNumberFlag set in its
+// sequence number) and messageType is a request that the command may trigger
+func CanAnswer(messageType MessageType, triggeringMessage *PDU) bool {
+	if responseType, isRequest := ResponseTypeFor(triggeringMessage.Type); isRequest && responseType == messageType {
+		return true
+	}
+
+	if triggeringMessage.SequenceNumber&CommandSequenceNumberFlag == 0 {
+		return false
+	}
+
+	for _, triggeredType := range TriggeredRequestTypesFor(triggeringMessage.Type) {
+		if triggeredType == messageType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package gtpv2
+
+import (
+	"net"
+	"sync"
+	"testing"
+)
+
+func TestSequenceNumberManager(t *testing.T) {
+	manager := NewSequenceNumberManager()
+
+	peerA := &net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: 2123}
+	peerB := &net.UDPAddr{IP: net.IP{10, 0, 0, 2}, Port: 2123}
+
+	manager.next[peerA.String()] = 0x7ffffe
+
+	for testIndex, testCase := range []struct {
+		messageType    MessageType
+		sequenceNumber uint32
+	}{
+		{CreateSessionRequest, 0x7ffffe},
+		{ModifyBearerRequest, 0x7fffff},
+		{DeleteSessionRequest, 0x000000},
+		{BearerResourceCommand, 0x800001},
+		{CreateBearerRequest, 0x000002},
+	} {
+		if sequenceNumber := manager.Next(peerA, testCase.messageType); sequenceNumber != testCase.sequenceNumber {
+			t.Errorf("[TestSequenceNumberManager] on test number [%d] expected sequence number (%06x), got (%06x)", testIndex+1, testCase.sequenceNumber, sequenceNumber)
+		}
+	}
+
+	firstForB := manager.Next(peerB, EchoRequest)
+	if firstForB > MaximumSequenceNumber || firstForB&CommandSequenceNumberFlag != 0 {
+		t.Errorf("[TestSequenceNumberManager] first sequence number for peer B (%08x) is out of range", firstForB)
+	}
+
+	if next := manager.Next(peerB, EchoRequest); next != (firstForB+1)&0x7fffff {
+		t.Errorf("[TestSequenceNumberManager] expected peer B to have its own sequence number space")
+	}
+
+	command := NewPDU(ModifyBearerCommand, 0x800010, []*IE{}).AddTEID(1)
+	request := NewPDU(ModifyBearerRequest, 0x000010, []*IE{}).AddTEID(1)
+	triggeredRequest := NewPDU(UpdateBearerRequest, 0x800010, []*IE{}).AddTEID(1)
+
+	for testIndex, testCase := range []struct {
+		messageType       MessageType
+		triggeringMessage *PDU
+		expectError       bool
+	}{
+		{UpdateBearerRequest, command, false},
+		{ModifyBearerFailureIndication, command, false},
+		{CreateBearerRequest, command, true},
+		{ModifyBearerResponse, request, false},
+		{DeleteBearerResponse, request, true},
+		{UpdateBearerResponse, triggeredRequest, false},
+		{UpdateBearerRequest, request, true},
+		{ModifyBearerResponse, nil, true},
+	} {
+		sequenceNumber, err := manager.SequenceNumberFor(peerA, testCase.messageType, testCase.triggeringMessage)
+		if testCase.expectError {
+			if err == nil {
+				t.Errorf("[TestSequenceNumberManager] on test number [%d] expected error, got none", testIndex+1)
+			}
+		} else if err != nil {
+			t.Errorf("[TestSequenceNumberManager] on test number [%d] expected no error, got (%s)", testIndex+1, err)
+		} else if sequenceNumber != testCase.triggeringMessage.SequenceNumber {
+			t.Errorf("[TestSequenceNumberManager] on test number [%d] expected sequence number (%06x), got (%06x)", testIndex+1, testCase.triggeringMessage.SequenceNumber, sequenceNumber)
+		}
+	}
+
+	allocated := make(chan uint32, 1000)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for j := 0; j < 100; j++ {
+				allocated <- manager.Next(peerB, CreateSessionRequest)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	close(allocated)
+
+	seen := make(map[uint32]bool)
+	for sequenceNumber := range allocated {
+		if seen[sequenceNumber] {
+			t.Fatalf("[TestSequenceNumberManager] sequence number (%06x) allocated more than once", sequenceNumber)
+		}
+		seen[sequenceNumber] = true
+	}
+}
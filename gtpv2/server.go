@@ -0,0 +1,269 @@
+package gtpv2
+
+import (
+	"context"
+	"log"
+	"net"
+	"sync"
+)
+
+// DefaultServerAddress is the address on which ListenAndServe listens if
+// ServerConfig.Address is empty.  2123 is the GTPv2-C port (TS 29.274 section
+// 4.2).
+const DefaultServerAddress = ":2123"
+
+// DefaultServerWorkers is the default maximum number of requests that a
+// Server handles at the same time
+const DefaultServerWorkers = 16
+
+// Handler handles a request received by a Server.  It returns the response
+// PDU, or nil if there is no response.  The Server sets the sequence number of
+// the response to that of the request, so the handler need not.  If an error
+// is returned, the Server sends the response that NewErrorResponse creates
+// for the request and error, so that returning a *RequestError rejects the
+// request with its Cause.
+type Handler interface {
+	ServeGTP(ctx context.Context, request *IncomingMessage) (*PDU, error)
+}
+
+// HandlerFunc is an ordinary function that is a Handler
+type HandlerFunc func(ctx context.Context, request *IncomingMessage) (*PDU, error)
+
+// ServeGTP calls handlerFunc(ctx, request)
+func (handlerFunc HandlerFunc) ServeGTP(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+	return handlerFunc(ctx, request)
+}
+
+// Middleware wraps a Handler to add behavior before or after it, such as
+// logging or validation
+type Middleware func(next Handler) Handler
+
+// ServerConfig provides the settings for a Server
+type ServerConfig struct {
+	// Address is the UDP address on which ListenAndServe listens.  The
+	// default is DefaultServerAddress.
+	Address string
+
+	// Transport configures the Transport created by ListenAndServe
+	Transport TransportConfig
+
+	// Workers is the maximum number of requests that are handled at the same
+	// time.  The default is DefaultServerWorkers.
+	Workers int
+
+	// RestartCounter is sent in the Recovery IE of the Echo Response when no
+	// handler is registered for EchoRequest
+	RestartCounter uint8
+
+	// ErrorLog receives errors that cannot be returned to a caller, such as a
+	// failure to send a response.  If it is nil, such errors are discarded.
+	ErrorLog *log.Logger
+}
+
+// Server receives GTPv2 requests and dispatches each to the Handler registered
+// for its message type.  A request of a type with no Handler is discarded,
+// except for an Echo Request, which is answered with the RestartCounter.  A
+// request that cannot be decoded is rejected (see DecodeRequest) without
+// reaching a Handler.  A request piggybacked on another message is dispatched
+// in the same way as any other request.
+type Server struct {
+	config ServerConfig
+
+	lock       sync.RWMutex
+	handlers   map[MessageType]Handler
+	middleware []Middleware
+}
+
+// NewServer creates a Server with no Handlers
+func NewServer(config ServerConfig) *Server {
+	if config.Address == "" {
+		config.Address = DefaultServerAddress
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = DefaultServerWorkers
+	}
+
+	return &Server{
+		config:   config,
+		handlers: make(map[MessageType]Handler),
+	}
+}
+
+// Handle registers the Handler for a message type, replacing any Handler
+// previously registered for it
+func (server *Server) Handle(messageType MessageType, handler Handler) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.handlers[messageType] = handler
+}
+
+// HandleFunc registers a function as the Handler for a message type
+func (server *Server) HandleFunc(messageType MessageType, handlerFunc func(ctx context.Context, request *IncomingMessage) (*PDU, error)) {
+	server.Handle(messageType, HandlerFunc(handlerFunc))
+}
+
+// Use adds middleware that wraps every Handler.  The first middleware added
+// is the outermost, so it sees each request first.
+func (server *Server) Use(middleware ...Middleware) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+
+	server.middleware = append(server.middleware, middleware...)
+}
+
+// ListenAndServe listens on the UDP Address, and calls Serve with a Transport
+// for the socket.  The socket is closed when Serve returns.
+func (server *Server) ListenAndServe(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", server.config.Address)
+	if err != nil {
+		return err
+	}
+
+	transport := NewTransport(conn, server.config.Transport)
+	defer transport.Close()
+
+	return server.Serve(ctx, transport)
+}
+
+// Serve receives requests from transport and handles them, until ctx is done
+// or transport is closed.  When ctx is done, Serve stops receiving, waits for
+// the requests being handled to finish, and returns nil.  Otherwise, it
+// returns the error from Transport.Receive.  The transport is not closed, so
+// it may also be used to send requests.
+func (server *Server) Serve(ctx context.Context, transport *Transport) error {
+	workers := make(chan struct{}, server.config.Workers)
+	var handling sync.WaitGroup
+	defer handling.Wait()
+
+	for {
+		request, err := transport.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+
+		handling.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				handling.Done()
+			}()
+
+			server.handle(ctx, transport, request)
+		}()
+	}
+}
+
+func (server *Server) handle(ctx context.Context, transport *Transport, request *IncomingMessage) {
+	var response *PDU
+	var err error
+
+	if request.Err != nil {
+		err = request.Err
+	} else {
+		handler := server.handlerFor(request.PDU.Type)
+		if handler == nil {
+			return
+		}
+
+		response, err = handler.ServeGTP(ctx, request)
+	}
+
+	if err != nil {
+		var responseErr error
+		if response, responseErr = NewErrorResponse(request.PDU, err); responseErr != nil {
+			server.logError("cannot reject (%s) from (%s) after error (%s): %s", NameOfMessageForType(request.PDU.Type), request.Peer, err, responseErr)
+			return
+		}
+	}
+
+	if response == nil {
+		return
+	}
+
+	response.SequenceNumber = request.PDU.SequenceNumber
+
+	if err := transport.SendResponse(request.Peer, response); err != nil {
+		server.logError("failed to send (%s) to (%s): %s", NameOfMessageForType(response.Type), request.Peer, err)
+	}
+}
+
+// handlerFor returns the registered Handler for messageType, wrapped in the
+// middleware, or nil if there is none
+func (server *Server) handlerFor(messageType MessageType) Handler {
+	server.lock.RLock()
+	defer server.lock.RUnlock()
+
+	handler, isRegistered := server.handlers[messageType]
+	if !isRegistered {
+		if messageType != EchoRequest {
+			return nil
+		}
+		handler = server.echoHandler()
+	}
+
+	for i := len(server.middleware) - 1; i >= 0; i-- {
+		handler = server.middleware[i](handler)
+	}
+
+	return handler
+}
+
+func (server *Server) echoHandler() Handler {
+	return HandlerFunc(func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+		return NewPDU(EchoResponse, request.PDU.SequenceNumber, []*IE{
+			NewIEWithRawData(RecoveryRestartCounter, []byte{server.config.RestartCounter}),
+		}), nil
+	})
+}
+
+func (server *Server) logError(format string, values ...interface{}) {
+	if server.config.ErrorLog != nil {
+		server.config.ErrorLog.Printf(format, values...)
+	}
+}
+
+// LoggingMiddleware logs each request, and the response or error from the
+// Handler, to logger
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+			logger.Printf("received (%s) with sequence number (%d) from (%s)", NameOfMessageForType(request.PDU.Type), request.PDU.SequenceNumber, request.Peer)
+
+			response, err := next.ServeGTP(ctx, request)
+
+			switch {
+			case err != nil:
+				logger.Printf("handler for (%s) from (%s) returned error: %s", NameOfMessageForType(request.PDU.Type), request.Peer, err)
+			case response != nil:
+				logger.Printf("responding to (%s) from (%s) with (%s)", NameOfMessageForType(request.PDU.Type), request.Peer, NameOfMessageForType(response.Type))
+			}
+
+			return response, err
+		})
+	}
+}
+
+// ValidationMiddleware applies ValidateRequest to each request, and rejects
+// a request that has a violation without calling the Handler
+func ValidationMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+			if err := ValidateRequest(request.PDU); err != nil {
+				return nil, err
+			}
+
+			return next.ServeGTP(ctx, request)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package gtpv2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func startLoopbackServer(t *testing.T, server *Server) (*Transport, *Transport) {
+	serverConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open server socket: %s", err)
+	}
+
+	clientConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open client socket: %s", err)
+	}
+
+	serverTransport := NewTransport(serverConn, TransportConfig{})
+	clientTransport := NewTransport(clientConn, TransportConfig{T3Response: 50 * time.Millisecond, N3Requests: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go server.Serve(ctx, serverTransport)
+
+	t.Cleanup(func() {
+		cancel()
+		serverTransport.Close()
+		clientTransport.Close()
+	})
+
+	return serverTransport, clientTransport
+}
+
+func TestServerDispatch(t *testing.T) {
+	server := NewServer(ServerConfig{RestartCounter: 9})
+
+	var logged bytes.Buffer
+	var logLock sync.Mutex
+	server.Use(LoggingMiddleware(log.New(&lockedWriter{&logged, &logLock}, "", 0)), ValidationMiddleware())
+
+	server.HandleFunc(DeleteSessionRequest, func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+		return NewPDU(DeleteSessionResponse, 0, []*IE{NewIEWithRawData(Cause, []byte{byte(CauseRequestAccepted), 0})}).AddTEID(0x99), nil
+	})
+
+	server.HandleFunc(ReleaseAccessBearersRequest, func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+		return nil, &RequestError{Cause: CauseContextNotFound, Reason: errors.New("no session for TEID")}
+	})
+
+	serverTransport, clientTransport := startLoopbackServer(t, server)
+	serverAddr := serverTransport.LocalAddr()
+
+	answer, err := clientTransport.SendRequest(context.Background(), serverAddr, NewPDU(DeleteSessionRequest, 0x000501, []*IE{}).AddTEID(0x10))
+	if err != nil {
+		t.Fatalf("[TestServerDispatch] expected no error on Delete Session Request, got (%s)", err)
+	}
+
+	if answer.PDU.Type != DeleteSessionResponse || answer.PDU.SequenceNumber != 0x000501 || answer.PDU.TEID != 0x99 {
+		t.Errorf("[TestServerDispatch] Delete Session Response is not correct")
+	}
+
+	answer, err = clientTransport.SendRequest(context.Background(), serverAddr, NewPDU(ReleaseAccessBearersRequest, 0x000502, []*IE{}).AddTEID(0x10))
+	if err != nil {
+		t.Fatalf("[TestServerDispatch] expected no error on Release Access Bearers Request, got (%s)", err)
+	}
+
+	if cause, isPresent := causeValueIn(answer.PDU); answer.PDU.Type != ReleaseAccessBearersResponse || !isPresent || cause != CauseContextNotFound {
+		t.Errorf("[TestServerDispatch] expected Release Access Bearers Response with cause (%s)", CauseContextNotFound)
+	}
+
+	// validation rejects a Create Session Request without mandatory IEs before
+	// it reaches the handler
+	server.HandleFunc(CreateSessionRequest, func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+		t.Errorf("[TestServerDispatch] handler for invalid Create Session Request should not be called")
+		return nil, nil
+	})
+
+	answer, err = clientTransport.SendRequest(context.Background(), serverAddr, NewPDU(CreateSessionRequest, 0x000503, []*IE{}).AddTEID(0))
+	if err != nil {
+		t.Fatalf("[TestServerDispatch] expected no error on Create Session Request, got (%s)", err)
+	}
+
+	if cause, isPresent := causeValueIn(answer.PDU); answer.PDU.Type != CreateSessionResponse || !isPresent || cause != CauseMandatoryIEMissing {
+		t.Errorf("[TestServerDispatch] expected Create Session Response with cause (%s)", CauseMandatoryIEMissing)
+	}
+
+	answer, err = clientTransport.SendRequest(context.Background(), serverAddr, NewPDU(EchoRequest, 0x000504, []*IE{NewIEWithRawData(RecoveryRestartCounter, []byte{1})}))
+	if err != nil {
+		t.Fatalf("[TestServerDispatch] expected no error on Echo Request, got (%s)", err)
+	}
+
+	if restartCounter, isPresent := RestartCounterIn(answer.PDU); answer.PDU.Type != EchoResponse || !isPresent || restartCounter != 9 {
+		t.Errorf("[TestServerDispatch] expected Echo Response with restart counter (9)")
+	}
+
+	if _, err := clientTransport.SendRequest(context.Background(), serverAddr, NewPDU(ModifyBearerRequest, 0x000505, []*IE{}).AddTEID(0x10)); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("[TestServerDispatch] expected request with no handler to be discarded, got (%v)", err)
+	}
+
+	logLock.Lock()
+	defer logLock.Unlock()
+
+	if !strings.Contains(logged.String(), "received (Delete Session Request) with sequence number (1281)") {
+		t.Errorf("[TestServerDispatch] expected logging middleware to log Delete Session Request, log is: %s", logged.String())
+	}
+}
+
+func TestServerPiggybackedRequest(t *testing.T) {
+	server := NewServer(ServerConfig{})
+
+	handled := make(chan *IncomingMessage, 2)
+	server.HandleFunc(CreateBearerRequest, func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+		handled <- request
+		return NewPDU(CreateBearerResponse, 0, []*IE{NewIEWithRawData(Cause, []byte{byte(CauseRequestAccepted), 0})}).AddTEID(0x70), nil
+	})
+
+	serverTransport, _ := startLoopbackServer(t, server)
+
+	pgwConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open PGW socket: %s", err)
+	}
+	defer pgwConn.Close()
+
+	// the Create Session Response carries a piggybacked Create Bearer Request
+	csResponse := NewPDU(CreateSessionResponse, 0x000601, []*IE{NewIEWithRawData(Cause, []byte{byte(CauseRequestAccepted), 0})}).AddTEID(0x10)
+	cbRequest := NewPDU(CreateBearerRequest, 0x000701, []*IE{}).AddTEID(0x10)
+	stream := append(csResponse.Encode(), cbRequest.Encode()...)
+	stream[0] |= 0x10
+
+	go func() {
+		if request, from := readPDUFromConn(t, pgwConn, time.Second); request != nil {
+			pgwConn.WriteTo(stream, from)
+		}
+	}()
+
+	answer, err := serverTransport.SendRequest(context.Background(), pgwConn.LocalAddr(), NewPDU(CreateSessionRequest, 0x000601, []*IE{}).AddTEID(0))
+	if err != nil {
+		t.Fatalf("[TestServerPiggybackedRequest] expected no error on Create Session Request, got (%s)", err)
+	}
+
+	if answer.PDU.Type != CreateSessionResponse || answer.PiggybackedPDU == nil || answer.PiggybackedPDU.Type != CreateBearerRequest {
+		t.Errorf("[TestServerPiggybackedRequest] expected Create Session Response with piggybacked Create Bearer Request as answer")
+	}
+
+	select {
+	case request := <-handled:
+		if request.PDU.SequenceNumber != 0x000701 || request.PiggybackedPDU != nil {
+			t.Errorf("[TestServerPiggybackedRequest] handler received incorrect Create Bearer Request")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("[TestServerPiggybackedRequest] expected handler for piggybacked Create Bearer Request to be called")
+	}
+
+	if pdu, _ := readPDUFromConn(t, pgwConn, time.Second); pdu == nil || pdu.Type != CreateBearerResponse || pdu.SequenceNumber != 0x000701 {
+		t.Fatalf("[TestServerPiggybackedRequest] expected PGW to receive Create Bearer Response")
+	}
+
+	// a retransmission is answered with the cached response, without calling
+	// the handler again
+	pgwConn.WriteTo(stream, serverTransport.LocalAddr())
+
+	if pdu, _ := readPDUFromConn(t, pgwConn, time.Second); pdu == nil || pdu.Type != CreateBearerResponse || pdu.TEID != 0x70 {
+		t.Errorf("[TestServerPiggybackedRequest] expected PGW to receive cached Create Bearer Response")
+	}
+
+	select {
+	case <-handled:
+		t.Errorf("[TestServerPiggybackedRequest] expected retransmitted Create Bearer Request not to reach the handler")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServerShutdownAndWorkers(t *testing.T) {
+	server := NewServer(ServerConfig{Workers: 2})
+
+	var lock sync.Mutex
+	running, maximumRunning := 0, 0
+
+	server.HandleFunc(DeleteSessionRequest, func(ctx context.Context, request *IncomingMessage) (*PDU, error) {
+		lock.Lock()
+		running++
+		if running > maximumRunning {
+			maximumRunning = running
+		}
+		lock.Unlock()
+
+		time.Sleep(30 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+
+		return NewPDU(DeleteSessionResponse, 0, []*IE{NewIEWithRawData(Cause, []byte{byte(CauseRequestAccepted), 0})}).AddTEID(1), nil
+	})
+
+	serverConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open server socket: %s", err)
+	}
+	clientConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open client socket: %s", err)
+	}
+
+	serverTransport := NewTransport(serverConn, TransportConfig{})
+	clientTransport := NewTransport(clientConn, TransportConfig{T3Response: time.Second})
+	defer serverTransport.Close()
+	defer clientTransport.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveResult := make(chan error, 1)
+	go func() {
+		serveResult <- server.Serve(ctx, serverTransport)
+	}()
+
+	var requests sync.WaitGroup
+	for i := uint32(1); i <= 6; i++ {
+		requests.Add(1)
+		go func(sequenceNumber uint32) {
+			defer requests.Done()
+			if _, err := clientTransport.SendRequest(context.Background(), serverTransport.LocalAddr(), NewPDU(DeleteSessionRequest, sequenceNumber, []*IE{}).AddTEID(1)); err != nil {
+				t.Errorf("[TestServerShutdownAndWorkers] expected no error on request (%d), got (%s)", sequenceNumber, err)
+			}
+		}(i)
+	}
+	requests.Wait()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if maximumRunning != 2 {
+		t.Errorf("[TestServerShutdownAndWorkers] expected (2) handlers to run at the same time, got (%d)", maximumRunning)
+	}
+
+	cancel()
+
+	select {
+	case err := <-serveResult:
+		if err != nil {
+			t.Errorf("[TestServerShutdownAndWorkers] expected Serve to return nil on shutdown, got (%s)", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("[TestServerShutdownAndWorkers] Serve did not return after shutdown")
+	}
+}
+
+type lockedWriter struct {
+	buffer *bytes.Buffer
+	lock   *sync.Mutex
+}
+
+func (writer *lockedWriter) Write(p []byte) (int, error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+	return writer.buffer.Write(p)
+}
+
+func causeValueIn(pdu *PDU) (CauseValue, bool) {
+	for _, ie := range pdu.InformationElements {
+		if ie.Type == Cause {
+			if cause, err := makeTypedCause(ie); err == nil {
+				return cause.Value, true
+			}
+		}
+	}
+
+	return 0, false
+}
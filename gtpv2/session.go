@@ -0,0 +1,693 @@
+package gtpv2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blorticus-go/gtp/tft"
+)
+
+// ErrIllegalTransition is returned (wrapped) by Session.Apply when a message
+// is not valid for the state of the session, such as a Modify Bearer Request
+// for an EBI that the session does not have
+var ErrIllegalTransition = errors.New("illegal session state transition")
+
+// Minimum and maximum EPS Bearer ID values that identify a bearer (TS 24.007
+// section 11.2.3.1.5)
+const (
+	MinimumEBI uint8 = 5
+	MaximumEBI uint8 = 15
+)
+
+// SessionSide identifies which end of a GTPv2 session a Session represents
+type SessionSide uint8
+
+// Session sides
+const (
+	// SessionSideRequester is the node that sends the Create Session Request
+	// (the MME on S11, and the SGW on S5/S8)
+	SessionSideRequester SessionSide = iota + 1
+	// SessionSideResponder is the node that answers the Create Session
+	// Request (the SGW on S11, and the PGW on S5/S8)
+	SessionSideResponder
+)
+
+// SessionState is the state of a PDN connection or bearer
+type SessionState uint8
+
+// Session states
+const (
+	// SessionStateCreating is the state between the request that creates a
+	// PDN connection or bearer and the response
+	SessionStateCreating SessionState = iota + 1
+	// SessionStateActive is the state after a PDN connection or bearer is
+	// created
+	SessionStateActive
+	// SessionStateDeleting is the state between the request that deletes a
+	// PDN connection or bearer and the response
+	SessionStateDeleting
+)
+
+var sessionStateNames = []string{"", "Creating", "Active", "Deleting"}
+
+// String returns the name of the state
+func (state SessionState) String() string {
+	if state > 0 && int(state) < len(sessionStateNames) {
+		return sessionStateNames[state]
+	}
+
+	return fmt.Sprintf("SessionState(%d)", uint8(state))
+}
+
+// Bearer is an EPS bearer in a PDN connection.  FTEIDs holds the user plane
+// F-TEIDs that have been exchanged for the bearer, by role.
+type Bearer struct {
+	EBI        uint8
+	State      SessionState
+	FTEIDs     map[FTEIDRole]*TypedFTEID
+	QoS        *TypedBearerQoS
+	TFT        *tft.TFT
+	ChargingID *uint32
+}
+
+// SessionPDNConnection is a PDN connection in a Session.  It is identified by
+// LinkedEBI, the EBI of its default bearer.
+type SessionPDNConnection struct {
+	LinkedEBI uint8
+	State     SessionState
+	APN       string
+	PAA       *TypedPAA
+	Bearers   map[uint8]*Bearer
+}
+
+// Session tracks the PDN connections and bearers of a UE on one GTPv2
+// control plane tunnel (e.g., S11 or S5/S8), from the point of view of Side.
+// It is updated by applying each message that is exchanged for the session,
+// in order, with Apply.  LocalFTEID and RemoteFTEID are the control plane
+// F-TEIDs of this end and the other end of the tunnel.  A Session is not safe
+// for concurrent use.
+type Session struct {
+	Side           SessionSide
+	IMSI           string
+	LocalFTEID     *TypedFTEID
+	RemoteFTEID    *TypedFTEID
+	PDNConnections map[uint8]*SessionPDNConnection
+
+	pendingRequests map[pendingSessionRequest]TypedMessage
+}
+
+type pendingSessionRequest struct {
+	messageType    MessageType
+	sequenceNumber uint32
+}
+
+// NewSession creates a Session with no PDN connections
+func NewSession(side SessionSide) *Session {
+	return &Session{
+		Side:            side,
+		PDNConnections:  make(map[uint8]*SessionPDNConnection),
+		pendingRequests: make(map[pendingSessionRequest]TypedMessage),
+	}
+}
+
+// Bearer returns the bearer with the EBI, and the PDN connection that has
+// it, or nil values if the Session has no such bearer
+func (session *Session) Bearer(ebi uint8) (*Bearer, *SessionPDNConnection) {
+	for _, pdnConnection := range session.PDNConnections {
+		if bearer, isPresent := pdnConnection.Bearers[ebi]; isPresent {
+			return bearer, pdnConnection
+		}
+	}
+
+	return nil, nil
+}
+
+// IsEmpty returns true if the Session has no PDN connections, as after the
+// last PDN connection is deleted
+func (session *Session) IsEmpty() bool {
+	return len(session.PDNConnections) == 0
+}
+
+// Apply updates the Session with a Create Session, Modify Bearer, Create
+// Bearer, Update Bearer, Delete Bearer or Delete Session Request or Response.
+// A request is checked against the current state and held until its response
+// (matched by sequence number) is applied, which is when the change takes
+// effect if the response Cause is an acceptance.  A request that will not be
+// answered must be dropped with Abandon.  Returns an error wrapping
+// ErrIllegalTransition if the message is not valid for the state, and an
+// error if the message cannot be unmarshalled or is of another type.
+func (session *Session) Apply(pdu *PDU) error {
+	switch pdu.Type {
+	case CreateSessionRequest:
+		return session.applyRequest(pdu, &TypedCreateSessionRequest{})
+	case ModifyBearerRequest:
+		return session.applyRequest(pdu, &TypedModifyBearerRequest{})
+	case CreateBearerRequest:
+		return session.applyRequest(pdu, &TypedCreateBearerRequest{})
+	case UpdateBearerRequest:
+		return session.applyRequest(pdu, &TypedUpdateBearerRequest{})
+	case DeleteBearerRequest:
+		return session.applyRequest(pdu, &TypedDeleteBearerRequest{})
+	case DeleteSessionRequest:
+		return session.applyRequest(pdu, &TypedDeleteSessionRequest{})
+	case CreateSessionResponse:
+		return session.applyResponse(pdu, &TypedCreateSessionResponse{})
+	case ModifyBearerResponse:
+		return session.applyResponse(pdu, &TypedModifyBearerResponse{})
+	case CreateBearerResponse:
+		return session.applyResponse(pdu, &TypedCreateBearerResponse{})
+	case UpdateBearerResponse:
+		return session.applyResponse(pdu, &TypedUpdateBearerResponse{})
+	case DeleteBearerResponse:
+		return session.applyResponse(pdu, &TypedDeleteBearerResponse{})
+	case DeleteSessionResponse:
+		return session.applyResponse(pdu, &TypedDeleteSessionResponse{})
+	}
+
+	return fmt.Errorf("message type (%s) does not change session state", NameOfMessageForType(pdu.Type))
+}
+
+// Abandon drops a request that was applied with Apply but will not be
+// answered, as when the peer does not respond.  The Session is rolled back as
+// if the request were rejected, so a PDN connection that a Create Session
+// Request added is removed, freeing its EBIs, and a PDN connection or bearer
+// that a Delete Session or Delete Bearer Request is deleting is active again.
+// Returns an error wrapping ErrIllegalTransition if the request is not
+// pending.
+func (session *Session) Abandon(request *PDU) error {
+	key := pendingSessionRequest{request.Type, request.SequenceNumber}
+
+	pendingRequest, isPending := session.pendingRequests[key]
+	if !isPending {
+		return fmt.Errorf("%w: (%s) with sequence number (%d) is not pending", ErrIllegalTransition, NameOfMessageForType(request.Type), request.SequenceNumber)
+	}
+
+	delete(session.pendingRequests, key)
+
+	// a response with no Cause is a rejection, and the other requests change
+	// nothing until their response is applied
+	switch pendingRequest := pendingRequest.(type) {
+	case *TypedCreateSessionRequest:
+		return session.applyCreateSessionResponse(pendingRequest, &TypedCreateSessionResponse{})
+	case *TypedDeleteBearerRequest:
+		return session.applyDeleteBearerResponse(pendingRequest, &TypedDeleteBearerResponse{})
+	case *TypedDeleteSessionRequest:
+		return session.applyDeleteSessionResponse(pendingRequest, &TypedDeleteSessionResponse{})
+	}
+
+	return nil
+}
+
+func (session *Session) applyRequest(pdu *PDU, request TypedMessage) error {
+	if err := request.Unmarshal(pdu); err != nil {
+		return err
+	}
+
+	key := pendingSessionRequest{pdu.Type, pdu.SequenceNumber}
+	if _, isPending := session.pendingRequests[key]; isPending {
+		return fmt.Errorf("%w: (%s) with sequence number (%d) is already pending", ErrIllegalTransition, NameOfMessageForType(pdu.Type), pdu.SequenceNumber)
+	}
+
+	var err error
+	switch request := request.(type) {
+	case *TypedCreateSessionRequest:
+		err = session.applyCreateSessionRequest(request)
+	case *TypedModifyBearerRequest:
+		err = session.applyModifyBearerRequest(request)
+	case *TypedCreateBearerRequest:
+		err = session.applyCreateBearerRequest(request)
+	case *TypedUpdateBearerRequest:
+		err = session.applyUpdateBearerRequest(request)
+	case *TypedDeleteBearerRequest:
+		err = session.applyDeleteBearerRequest(request)
+	case *TypedDeleteSessionRequest:
+		err = session.applyDeleteSessionRequest(request)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	session.pendingRequests[key] = request
+
+	return nil
+}
+
+func (session *Session) applyResponse(pdu *PDU, response TypedMessage) error {
+	if err := response.Unmarshal(pdu); err != nil {
+		return err
+	}
+
+	requestType, _ := RequestTypeFor(pdu.Type)
+	key := pendingSessionRequest{requestType, pdu.SequenceNumber}
+
+	request, isPending := session.pendingRequests[key]
+	if !isPending {
+		return fmt.Errorf("%w: (%s) with sequence number (%d) answers no pending request", ErrIllegalTransition, NameOfMessageForType(pdu.Type), pdu.SequenceNumber)
+	}
+
+	delete(session.pendingRequests, key)
+
+	switch response := response.(type) {
+	case *TypedCreateSessionResponse:
+		return session.applyCreateSessionResponse(request.(*TypedCreateSessionRequest), response)
+	case *TypedModifyBearerResponse:
+		return session.applyModifyBearerResponse(request.(*TypedModifyBearerRequest), response)
+	case *TypedCreateBearerResponse:
+		return session.applyCreateBearerResponse(request.(*TypedCreateBearerRequest), response)
+	case *TypedUpdateBearerResponse:
+		return session.applyUpdateBearerResponse(request.(*TypedUpdateBearerRequest), response)
+	case *TypedDeleteBearerResponse:
+		return session.applyDeleteBearerResponse(request.(*TypedDeleteBearerRequest), response)
+	case *TypedDeleteSessionResponse:
+		return session.applyDeleteSessionResponse(request.(*TypedDeleteSessionRequest), response)
+	}
+
+	return nil
+}
+
+func (session *Session) applyCreateSessionRequest(request *TypedCreateSessionRequest) error {
+	if len(request.BearerContextsToBeCreated) == 0 {
+		return fmt.Errorf("%w: Create Session Request has no Bearer Context to be created", ErrIllegalTransition)
+	}
+
+	for _, bearerContext := range request.BearerContextsToBeCreated {
+		if err := session.checkNewEBI(bearerContext.EBI); err != nil {
+			return err
+		}
+	}
+
+	pdnConnection := &SessionPDNConnection{
+		LinkedEBI: request.BearerContextsToBeCreated[0].EBI,
+		State:     SessionStateCreating,
+		Bearers:   make(map[uint8]*Bearer),
+	}
+
+	if request.APN != nil {
+		pdnConnection.APN = request.APN.AsString
+	}
+
+	for _, bearerContext := range request.BearerContextsToBeCreated {
+		bearer := &Bearer{EBI: bearerContext.EBI, State: SessionStateCreating, FTEIDs: make(map[FTEIDRole]*TypedFTEID)}
+		bearer.update(bearerContext)
+		pdnConnection.Bearers[bearer.EBI] = bearer
+	}
+
+	if request.IMSI != nil {
+		session.IMSI = request.IMSI.AsString
+	}
+
+	session.setRequesterFTEID(request.SenderFTEIDForControlPlane)
+	session.PDNConnections[pdnConnection.LinkedEBI] = pdnConnection
+
+	return nil
+}
+
+func (session *Session) applyCreateSessionResponse(request *TypedCreateSessionRequest, response *TypedCreateSessionResponse) error {
+	pdnConnection := session.PDNConnections[request.BearerContextsToBeCreated[0].EBI]
+	if pdnConnection == nil {
+		return nil
+	}
+
+	if !causeIsAccepted(response.Cause) {
+		delete(session.PDNConnections, pdnConnection.LinkedEBI)
+		return nil
+	}
+
+	pdnConnection.State = SessionStateActive
+	pdnConnection.PAA = response.PAA
+	session.setResponderFTEID(response.SenderFTEIDForControlPlane)
+
+	created := make(map[uint8]bool)
+	for _, bearerContext := range response.BearerContextsCreated {
+		bearer, isPresent := pdnConnection.Bearers[bearerContext.EBI]
+		if !isPresent || (bearerContext.Cause != nil && !bearerContext.Cause.IsAccepted()) {
+			continue
+		}
+
+		bearer.update(bearerContext)
+		bearer.State = SessionStateActive
+		created[bearer.EBI] = true
+	}
+
+	for ebi := range pdnConnection.Bearers {
+		if !created[ebi] {
+			delete(pdnConnection.Bearers, ebi)
+		}
+	}
+
+	if !created[pdnConnection.LinkedEBI] {
+		delete(session.PDNConnections, pdnConnection.LinkedEBI)
+	}
+
+	return nil
+}
+
+func (session *Session) applyModifyBearerRequest(request *TypedModifyBearerRequest) error {
+	for _, bearerContext := range request.BearerContextsToBeModified {
+		if _, err := session.activeBearer(bearerContext.EBI, "Modify Bearer Request"); err != nil {
+			return err
+		}
+	}
+
+	for _, bearerContext := range request.BearerContextsToBeRemoved {
+		if _, err := session.activeBearer(bearerContext.EBI, "Modify Bearer Request"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (session *Session) applyModifyBearerResponse(request *TypedModifyBearerRequest, response *TypedModifyBearerResponse) error {
+	if !causeIsAccepted(response.Cause) {
+		return nil
+	}
+
+	session.setRequesterFTEID(request.SenderFTEIDForControlPlane)
+
+	for _, bearerContext := range request.BearerContextsToBeModified {
+		if bearer, _ := session.Bearer(bearerContext.EBI); bearer != nil {
+			bearer.update(bearerContext)
+		}
+	}
+
+	for _, bearerContext := range response.BearerContextsModified {
+		if bearer, _ := session.Bearer(bearerContext.EBI); bearer != nil {
+			bearer.update(bearerContext)
+		}
+	}
+
+	for _, bearerContext := range request.BearerContextsToBeRemoved {
+		session.removeBearer(bearerContext.EBI)
+	}
+
+	return nil
+}
+
+func (session *Session) applyCreateBearerRequest(request *TypedCreateBearerRequest) error {
+	if request.LinkedEBI == nil {
+		return fmt.Errorf("%w: Create Bearer Request has no Linked EBI", ErrIllegalTransition)
+	}
+
+	pdnConnection, isPresent := session.PDNConnections[*request.LinkedEBI]
+	if !isPresent || pdnConnection.State != SessionStateActive {
+		return fmt.Errorf("%w: Create Bearer Request for Linked EBI (%d), which is not an active PDN connection", ErrIllegalTransition, *request.LinkedEBI)
+	}
+
+	if len(request.BearerContexts) == 0 {
+		return fmt.Errorf("%w: Create Bearer Request has no Bearer Context", ErrIllegalTransition)
+	}
+
+	return nil
+}
+
+// applyCreateBearerResponse adds each bearer that is accepted in the
+// response.  The EBI is assigned in the response, so the Bearer Contexts in
+// the request and response are matched by position.
+func (session *Session) applyCreateBearerResponse(request *TypedCreateBearerRequest, response *TypedCreateBearerResponse) error {
+	if !causeIsAccepted(response.Cause) {
+		return nil
+	}
+
+	pdnConnection, isPresent := session.PDNConnections[*request.LinkedEBI]
+	if !isPresent {
+		return fmt.Errorf("%w: PDN connection with Linked EBI (%d) was removed before Create Bearer Response", ErrIllegalTransition, *request.LinkedEBI)
+	}
+
+	// every accepted EBI is checked before any bearer is added, so that the
+	// Session is unchanged if the response is not applied
+	accepted := make(map[uint8]bool)
+	for _, bearerContext := range response.BearerContexts {
+		if bearerContext.Cause != nil && !bearerContext.Cause.IsAccepted() {
+			continue
+		}
+
+		if err := session.checkNewEBI(bearerContext.EBI); err != nil {
+			return err
+		}
+
+		if accepted[bearerContext.EBI] {
+			return fmt.Errorf("%w: EBI (%d) is accepted more than once in Create Bearer Response", ErrIllegalTransition, bearerContext.EBI)
+		}
+		accepted[bearerContext.EBI] = true
+	}
+
+	for i, bearerContext := range response.BearerContexts {
+		if bearerContext.Cause != nil && !bearerContext.Cause.IsAccepted() {
+			continue
+		}
+
+		bearer := &Bearer{EBI: bearerContext.EBI, State: SessionStateActive, FTEIDs: make(map[FTEIDRole]*TypedFTEID)}
+		if i < len(request.BearerContexts) {
+			bearer.update(request.BearerContexts[i])
+		}
+		bearer.update(bearerContext)
+
+		pdnConnection.Bearers[bearer.EBI] = bearer
+	}
+
+	return nil
+}
+
+func (session *Session) applyUpdateBearerRequest(request *TypedUpdateBearerRequest) error {
+	if len(request.BearerContexts) == 0 {
+		return fmt.Errorf("%w: Update Bearer Request has no Bearer Context", ErrIllegalTransition)
+	}
+
+	for _, bearerContext := range request.BearerContexts {
+		if _, err := session.activeBearer(bearerContext.EBI, "Update Bearer Request"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (session *Session) applyUpdateBearerResponse(request *TypedUpdateBearerRequest, response *TypedUpdateBearerResponse) error {
+	if !causeIsAccepted(response.Cause) {
+		return nil
+	}
+
+	rejected := make(map[uint8]bool)
+	for _, bearerContext := range response.BearerContexts {
+		if bearerContext.Cause != nil && !bearerContext.Cause.IsAccepted() {
+			rejected[bearerContext.EBI] = true
+		}
+	}
+
+	for _, bearerContext := range request.BearerContexts {
+		if bearer, _ := session.Bearer(bearerContext.EBI); bearer != nil && !rejected[bearerContext.EBI] {
+			bearer.update(bearerContext)
+		}
+	}
+
+	return nil
+}
+
+func (session *Session) applyDeleteBearerRequest(request *TypedDeleteBearerRequest) error {
+	if request.LinkedEBI != nil {
+		pdnConnection, isPresent := session.PDNConnections[*request.LinkedEBI]
+		if !isPresent || pdnConnection.State != SessionStateActive {
+			return fmt.Errorf("%w: Delete Bearer Request for Linked EBI (%d), which is not an active PDN connection", ErrIllegalTransition, *request.LinkedEBI)
+		}
+
+		pdnConnection.State = SessionStateDeleting
+
+		return nil
+	}
+
+	if len(request.EPSBearerIDs) == 0 {
+		return fmt.Errorf("%w: Delete Bearer Request has neither Linked EBI nor EPS Bearer IDs", ErrIllegalTransition)
+	}
+
+	for _, ebi := range request.EPSBearerIDs {
+		if _, err := session.activeBearer(ebi, "Delete Bearer Request"); err != nil {
+			return err
+		}
+	}
+
+	for _, ebi := range request.EPSBearerIDs {
+		bearer, _ := session.Bearer(ebi)
+		bearer.State = SessionStateDeleting
+	}
+
+	return nil
+}
+
+func (session *Session) applyDeleteBearerResponse(request *TypedDeleteBearerRequest, response *TypedDeleteBearerResponse) error {
+	isAccepted := causeIsAccepted(response.Cause)
+
+	if request.LinkedEBI != nil {
+		if pdnConnection, isPresent := session.PDNConnections[*request.LinkedEBI]; isPresent {
+			if isAccepted {
+				delete(session.PDNConnections, *request.LinkedEBI)
+			} else {
+				pdnConnection.State = SessionStateActive
+			}
+		}
+
+		return nil
+	}
+
+	rejected := make(map[uint8]bool)
+	for _, bearerContext := range response.BearerContexts {
+		if bearerContext.Cause != nil && !bearerContext.Cause.IsAccepted() {
+			rejected[bearerContext.EBI] = true
+		}
+	}
+
+	for _, ebi := range request.EPSBearerIDs {
+		if isAccepted && !rejected[ebi] {
+			session.removeBearer(ebi)
+		} else if bearer, _ := session.Bearer(ebi); bearer != nil {
+			bearer.State = SessionStateActive
+		}
+	}
+
+	return nil
+}
+
+func (session *Session) applyDeleteSessionRequest(request *TypedDeleteSessionRequest) error {
+	pdnConnection, err := session.pdnConnectionForDeleteSession(request)
+	if err != nil {
+		return err
+	}
+
+	if pdnConnection.State != SessionStateActive {
+		return fmt.Errorf("%w: Delete Session Request for PDN connection with Linked EBI (%d) in state (%s)", ErrIllegalTransition, pdnConnection.LinkedEBI, pdnConnection.State)
+	}
+
+	pdnConnection.State = SessionStateDeleting
+
+	return nil
+}
+
+func (session *Session) applyDeleteSessionResponse(request *TypedDeleteSessionRequest, response *TypedDeleteSessionResponse) error {
+	pdnConnection, err := session.pdnConnectionForDeleteSession(request)
+	if err != nil {
+		return nil
+	}
+
+	if causeIsAccepted(response.Cause) {
+		delete(session.PDNConnections, pdnConnection.LinkedEBI)
+	} else {
+		pdnConnection.State = SessionStateActive
+	}
+
+	return nil
+}
+
+// pdnConnectionForDeleteSession returns the PDN connection identified by the
+// Linked EBI in a Delete Session Request, or the only PDN connection if the
+// Linked EBI is absent
+func (session *Session) pdnConnectionForDeleteSession(request *TypedDeleteSessionRequest) (*SessionPDNConnection, error) {
+	if request.LinkedEBI != nil {
+		if pdnConnection, isPresent := session.PDNConnections[*request.LinkedEBI]; isPresent {
+			return pdnConnection, nil
+		}
+		return nil, fmt.Errorf("%w: Delete Session Request for unknown Linked EBI (%d)", ErrIllegalTransition, *request.LinkedEBI)
+	}
+
+	if len(session.PDNConnections) != 1 {
+		return nil, fmt.Errorf("%w: Delete Session Request has no Linked EBI, and session has (%d) PDN connections", ErrIllegalTransition, len(session.PDNConnections))
+	}
+
+	for _, pdnConnection := range session.PDNConnections {
+		return pdnConnection, nil
+	}
+
+	return nil, nil
+}
+
+// checkNewEBI returns an error if the EBI is not in the valid range, or is
+// already used in the Session
+func (session *Session) checkNewEBI(ebi uint8) error {
+	if ebi < MinimumEBI || ebi > MaximumEBI {
+		return fmt.Errorf("%w: EBI (%d) is outside of the range (%d) to (%d)", ErrIllegalTransition, ebi, MinimumEBI, MaximumEBI)
+	}
+
+	if bearer, _ := session.Bearer(ebi); bearer != nil {
+		return fmt.Errorf("%w: EBI (%d) is already in use", ErrIllegalTransition, ebi)
+	}
+
+	return nil
+}
+
+// activeBearer returns the bearer with the EBI, or an error naming
+// messageName if there is no such bearer, or it is not active
+func (session *Session) activeBearer(ebi uint8, messageName string) (*Bearer, error) {
+	bearer, _ := session.Bearer(ebi)
+	if bearer == nil {
+		return nil, fmt.Errorf("%w: %s for unknown EBI (%d)", ErrIllegalTransition, messageName, ebi)
+	}
+
+	if bearer.State != SessionStateActive {
+		return nil, fmt.Errorf("%w: %s for EBI (%d) in state (%s)", ErrIllegalTransition, messageName, ebi, bearer.State)
+	}
+
+	return bearer, nil
+}
+
+// removeBearer removes a bearer, and its PDN connection if it is the default
+// bearer
+func (session *Session) removeBearer(ebi uint8) {
+	if _, pdnConnection := session.Bearer(ebi); pdnConnection != nil {
+		if ebi == pdnConnection.LinkedEBI {
+			delete(session.PDNConnections, ebi)
+		} else {
+			delete(pdnConnection.Bearers, ebi)
+		}
+	}
+}
+
+func (session *Session) setRequesterFTEID(fteid *TypedFTEID) {
+	if fteid == nil {
+		return
+	}
+
+	if session.Side == SessionSideRequester {
+		session.LocalFTEID = fteid
+	} else {
+		session.RemoteFTEID = fteid
+	}
+}
+
+func (session *Session) setResponderFTEID(fteid *TypedFTEID) {
+	if fteid == nil {
+		return
+	}
+
+	if session.Side == SessionSideResponder {
+		session.LocalFTEID = fteid
+	} else {
+		session.RemoteFTEID = fteid
+	}
+}
+
+// update copies the F-TEIDs, QoS, TFT and Charging ID that are present in a
+// Bearer Context to the bearer
+func (bearer *Bearer) update(bearerContext *TypedBearerContext) {
+	roles := bearerContextFTEIDInstances[bearerContextPlacement{bearerContext.MessageType, bearerContext.Instance}]
+	for role := range roles {
+		if fteid := bearerContext.FTEID(role); fteid != nil {
+			bearer.FTEIDs[role] = fteid
+		}
+	}
+
+	if bearerContext.BearerQoS != nil {
+		bearer.QoS = bearerContext.BearerQoS
+	}
+
+	if bearerContext.TFT != nil {
+		bearer.TFT = bearerContext.TFT
+	}
+
+	if bearerContext.ChargingID != nil {
+		bearer.ChargingID = bearerContext.ChargingID
+	}
+}
+
+func causeIsAccepted(cause *TypedCause) bool {
+	return cause != nil && cause.IsAccepted()
+}
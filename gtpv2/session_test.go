@@ -0,0 +1,319 @@
+package gtpv2
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func marshalForSessionTest(t *testing.T, message TypedMessage) *PDU {
+	t.Helper()
+
+	pdu, err := message.Marshal()
+	if err != nil {
+		t.Fatalf("[%s] expected no error on Marshal of (%s), got (%s)", t.Name(), NameOfMessageForType(message.MessageType()), err)
+	}
+
+	return pdu
+}
+
+func TestSessionCallFlow(t *testing.T) {
+	session := NewSession(SessionSideRequester)
+	accepted := &TypedCause{Value: CauseRequestAccepted}
+
+	mmeFTEID := &TypedFTEID{InterfaceType: 10, Key: 0x00000101, IPv4Addr: net.IP{10, 0, 0, 1}}
+	sgwFTEID := &TypedFTEID{InterfaceType: 11, Key: 0x00000202, IPv4Addr: net.IP{10, 0, 0, 2}}
+
+	csRequest := &TypedCreateSessionRequest{
+		SequenceNumber:             1,
+		IMSI:                       &TypedIMSI{AsString: "001010123456789"},
+		SenderFTEIDForControlPlane: mmeFTEID,
+		APN:                        &TypedAPN{AsString: "internet"},
+	}
+	csRequest.NewBearerContextToBeCreated(5).BearerQoS = &TypedBearerQoS{PriorityLevel: 9, QCI: 9}
+
+	if err := session.Apply(marshalForSessionTest(t, csRequest)); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Create Session Request, got (%s)", err)
+	}
+
+	if session.PDNConnections[5] == nil || session.PDNConnections[5].State != SessionStateCreating {
+		t.Fatalf("[TestSessionCallFlow] expected PDN connection (5) in state Creating after Create Session Request")
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, csRequest)); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionCallFlow] expected ErrIllegalTransition on repeated Create Session Request, got (%v)", err)
+	}
+
+	csResponse := &TypedCreateSessionResponse{
+		SequenceNumber:             1,
+		Cause:                      accepted,
+		SenderFTEIDForControlPlane: sgwFTEID,
+		PAA:                        &TypedPAA{PDNType: PDNTypeIPv4, IPv4Address: net.IP{192, 168, 0, 10}},
+	}
+	created := csResponse.NewBearerContextCreated(5)
+	created.Cause = accepted
+	created.SetFTEID(FTEIDRoleS1USGW, &TypedFTEID{InterfaceType: 1, Key: 0x00001001, IPv4Addr: net.IP{10, 0, 1, 2}})
+
+	if err := session.Apply(marshalForSessionTest(t, csResponse)); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Create Session Response, got (%s)", err)
+	}
+
+	pdnConnection := session.PDNConnections[5]
+	if pdnConnection == nil || pdnConnection.State != SessionStateActive || pdnConnection.APN != "internet" || !pdnConnection.PAA.IPv4Address.Equal(net.IP{192, 168, 0, 10}) {
+		t.Fatalf("[TestSessionCallFlow] expected active PDN connection (5) with APN and PAA after Create Session Response")
+	}
+
+	if session.IMSI != "001010123456789" || session.LocalFTEID.Key != mmeFTEID.Key || session.RemoteFTEID.Key != sgwFTEID.Key {
+		t.Errorf("[TestSessionCallFlow] expected IMSI and control plane F-TEIDs after Create Session Response")
+	}
+
+	if bearer := pdnConnection.Bearers[5]; bearer == nil || bearer.State != SessionStateActive || bearer.FTEIDs[FTEIDRoleS1USGW] == nil || bearer.QoS.QCI != 9 {
+		t.Fatalf("[TestSessionCallFlow] expected active default bearer with S1-U SGW F-TEID and QoS")
+	}
+
+	mbRequest := &TypedModifyBearerRequest{SequenceNumber: 2}
+	mbRequest.NewBearerContextToBeModified(5).SetFTEID(FTEIDRoleS1UENodeB, &TypedFTEID{InterfaceType: 0, Key: 0x00002001, IPv4Addr: net.IP{10, 0, 2, 1}})
+
+	if err := session.Apply(marshalForSessionTest(t, mbRequest)); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Modify Bearer Request, got (%s)", err)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedModifyBearerResponse{SequenceNumber: 2, Cause: accepted})); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Modify Bearer Response, got (%s)", err)
+	}
+
+	if fteid := pdnConnection.Bearers[5].FTEIDs[FTEIDRoleS1UENodeB]; fteid == nil || fteid.Key != 0x00002001 {
+		t.Errorf("[TestSessionCallFlow] expected S1-U eNodeB F-TEID on default bearer after Modify Bearer Response")
+	}
+
+	cbRequest := &TypedCreateBearerRequest{SequenceNumber: 3, PTI: uint8Pointer(0), LinkedEBI: uint8Pointer(5)}
+	cbRequestContext := cbRequest.NewBearerContext(0)
+	cbRequestContext.BearerQoS = &TypedBearerQoS{PriorityLevel: 2, QCI: 1}
+	cbRequestContext.SetFTEID(FTEIDRoleS1USGW, &TypedFTEID{InterfaceType: 1, Key: 0x00001002, IPv4Addr: net.IP{10, 0, 1, 2}})
+
+	if err := session.Apply(marshalForSessionTest(t, cbRequest)); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Create Bearer Request, got (%s)", err)
+	}
+
+	cbResponse := &TypedCreateBearerResponse{SequenceNumber: 3, Cause: accepted}
+	cbResponse.NewBearerContext(6).Cause = accepted
+
+	if err := session.Apply(marshalForSessionTest(t, cbResponse)); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Create Bearer Response, got (%s)", err)
+	}
+
+	dedicated, dedicatedPDNConnection := session.Bearer(6)
+	if dedicated == nil || dedicatedPDNConnection != pdnConnection || dedicated.State != SessionStateActive || dedicated.QoS.QCI != 1 || dedicated.FTEIDs[FTEIDRoleS1USGW] == nil {
+		t.Fatalf("[TestSessionCallFlow] expected active dedicated bearer (6) with QoS and F-TEID from Create Bearer Request")
+	}
+
+	ubRequest := &TypedUpdateBearerRequest{SequenceNumber: 4}
+	ubRequest.NewBearerContext(6).BearerQoS = &TypedBearerQoS{PriorityLevel: 2, QCI: 2}
+
+	if err := session.Apply(marshalForSessionTest(t, ubRequest)); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Update Bearer Request, got (%s)", err)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedUpdateBearerResponse{SequenceNumber: 4, Cause: accepted})); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Update Bearer Response, got (%s)", err)
+	}
+
+	if dedicated.QoS.QCI != 2 {
+		t.Errorf("[TestSessionCallFlow] expected QCI (2) on dedicated bearer after Update Bearer Response, got (%d)", dedicated.QoS.QCI)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedDeleteBearerRequest{SequenceNumber: 5, EPSBearerIDs: []uint8{6}})); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Delete Bearer Request, got (%s)", err)
+	}
+
+	if dedicated.State != SessionStateDeleting {
+		t.Errorf("[TestSessionCallFlow] expected dedicated bearer in state Deleting, got (%s)", dedicated.State)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedDeleteBearerResponse{SequenceNumber: 5, Cause: accepted})); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Delete Bearer Response, got (%s)", err)
+	}
+
+	if bearer, _ := session.Bearer(6); bearer != nil {
+		t.Errorf("[TestSessionCallFlow] expected dedicated bearer to be removed after Delete Bearer Response")
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedDeleteSessionRequest{SequenceNumber: 6, LinkedEBI: uint8Pointer(5)})); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Delete Session Request, got (%s)", err)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedDeleteSessionResponse{SequenceNumber: 6, Cause: accepted})); err != nil {
+		t.Fatalf("[TestSessionCallFlow] expected no error on Delete Session Response, got (%s)", err)
+	}
+
+	if !session.IsEmpty() {
+		t.Errorf("[TestSessionCallFlow] expected no PDN connections after Delete Session Response")
+	}
+}
+
+func TestSessionIllegalTransitions(t *testing.T) {
+	session := NewSession(SessionSideResponder)
+
+	mbRequest := &TypedModifyBearerRequest{SequenceNumber: 1}
+	mbRequest.NewBearerContextToBeModified(5)
+
+	if err := session.Apply(marshalForSessionTest(t, mbRequest)); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionIllegalTransitions] expected ErrIllegalTransition on Modify Bearer Request for unknown EBI, got (%v)", err)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedDeleteSessionResponse{SequenceNumber: 2, Cause: &TypedCause{Value: CauseRequestAccepted}})); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionIllegalTransitions] expected ErrIllegalTransition on response with no pending request, got (%v)", err)
+	}
+
+	outOfRange := &TypedCreateSessionRequest{SequenceNumber: 3}
+	outOfRange.NewBearerContextToBeCreated(4)
+
+	if err := session.Apply(marshalForSessionTest(t, outOfRange)); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionIllegalTransitions] expected ErrIllegalTransition on Create Session Request with EBI (4), got (%v)", err)
+	}
+
+	csRequest := &TypedCreateSessionRequest{SequenceNumber: 4}
+	csRequest.NewBearerContextToBeCreated(5)
+
+	if err := session.Apply(marshalForSessionTest(t, csRequest)); err != nil {
+		t.Fatalf("[TestSessionIllegalTransitions] expected no error on Create Session Request, got (%s)", err)
+	}
+
+	duplicate := &TypedCreateSessionRequest{SequenceNumber: 5}
+	duplicate.NewBearerContextToBeCreated(5)
+
+	if err := session.Apply(marshalForSessionTest(t, duplicate)); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionIllegalTransitions] expected ErrIllegalTransition on Create Session Request with EBI in use, got (%v)", err)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedDeleteSessionRequest{SequenceNumber: 6, LinkedEBI: uint8Pointer(5)})); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionIllegalTransitions] expected ErrIllegalTransition on Delete Session Request for PDN connection being created, got (%v)", err)
+	}
+
+	rejection := &TypedCreateSessionResponse{SequenceNumber: 4, Cause: &TypedCause{Value: CauseNoResourcesAvailable}}
+	if err := session.Apply(marshalForSessionTest(t, rejection)); err != nil {
+		t.Fatalf("[TestSessionIllegalTransitions] expected no error on Create Session Response, got (%s)", err)
+	}
+
+	if !session.IsEmpty() {
+		t.Errorf("[TestSessionIllegalTransitions] expected no PDN connections after rejected Create Session Response")
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedReleaseAccessBearersRequest{SequenceNumber: 7})); err == nil || errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionIllegalTransitions] expected non-transition error on Release Access Bearers Request, got (%v)", err)
+	}
+}
+
+func TestSessionCreateBearerResponseIsAtomic(t *testing.T) {
+	session := NewSession(SessionSideResponder)
+	accepted := &TypedCause{Value: CauseRequestAccepted}
+
+	csRequest := &TypedCreateSessionRequest{SequenceNumber: 1}
+	csRequest.NewBearerContextToBeCreated(5)
+
+	csResponse := &TypedCreateSessionResponse{SequenceNumber: 1, Cause: accepted}
+	csResponse.NewBearerContextCreated(5).Cause = accepted
+
+	for _, message := range []TypedMessage{csRequest, csResponse} {
+		if err := session.Apply(marshalForSessionTest(t, message)); err != nil {
+			t.Fatalf("[TestSessionCreateBearerResponseIsAtomic] expected no error on (%s), got (%s)", NameOfMessageForType(message.MessageType()), err)
+		}
+	}
+
+	for testIndex, ebis := range [][]uint8{{6, 5}, {6, 6}} {
+		sequenceNumber := uint32(testIndex + 2)
+
+		cbRequest := &TypedCreateBearerRequest{SequenceNumber: sequenceNumber, LinkedEBI: uint8Pointer(5)}
+		cbResponse := &TypedCreateBearerResponse{SequenceNumber: sequenceNumber, Cause: accepted}
+		for _, ebi := range ebis {
+			cbRequest.NewBearerContext(0)
+			cbResponse.NewBearerContext(ebi).Cause = accepted
+		}
+
+		if err := session.Apply(marshalForSessionTest(t, cbRequest)); err != nil {
+			t.Fatalf("[TestSessionCreateBearerResponseIsAtomic] on test number [%d] expected no error on Create Bearer Request, got (%s)", testIndex+1, err)
+		}
+
+		if err := session.Apply(marshalForSessionTest(t, cbResponse)); !errors.Is(err, ErrIllegalTransition) {
+			t.Errorf("[TestSessionCreateBearerResponseIsAtomic] on test number [%d] expected ErrIllegalTransition on Create Bearer Response with EBIs %v, got (%v)", testIndex+1, ebis, err)
+		}
+
+		if bearer, _ := session.Bearer(6); bearer != nil {
+			t.Errorf("[TestSessionCreateBearerResponseIsAtomic] on test number [%d] expected no bearer (6) after Create Bearer Response that is not applied", testIndex+1)
+		}
+	}
+}
+
+func TestSessionAbandon(t *testing.T) {
+	session := NewSession(SessionSideRequester)
+	accepted := &TypedCause{Value: CauseRequestAccepted}
+
+	csRequest := &TypedCreateSessionRequest{SequenceNumber: 1}
+	csRequest.NewBearerContextToBeCreated(5)
+	csRequestPDU := marshalForSessionTest(t, csRequest)
+
+	if err := session.Apply(csRequestPDU); err != nil {
+		t.Fatalf("[TestSessionAbandon] expected no error on Create Session Request, got (%s)", err)
+	}
+
+	if err := session.Abandon(csRequestPDU); err != nil {
+		t.Fatalf("[TestSessionAbandon] expected no error on Abandon of Create Session Request, got (%s)", err)
+	}
+
+	if !session.IsEmpty() {
+		t.Errorf("[TestSessionAbandon] expected no PDN connections after Create Session Request is abandoned")
+	}
+
+	if err := session.Abandon(csRequestPDU); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionAbandon] expected ErrIllegalTransition on Abandon of request that is not pending, got (%v)", err)
+	}
+
+	if err := session.Apply(marshalForSessionTest(t, &TypedCreateSessionResponse{SequenceNumber: 1, Cause: accepted})); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("[TestSessionAbandon] expected ErrIllegalTransition on response to abandoned request, got (%v)", err)
+	}
+
+	// the EBIs of the abandoned request are free again
+	csRequest.SequenceNumber = 2
+	csRequest.NewBearerContextToBeCreated(6)
+
+	csResponse := &TypedCreateSessionResponse{SequenceNumber: 2, Cause: accepted}
+	csResponse.NewBearerContextCreated(5).Cause = accepted
+	csResponse.NewBearerContextCreated(6).Cause = accepted
+
+	for _, message := range []TypedMessage{csRequest, csResponse} {
+		if err := session.Apply(marshalForSessionTest(t, message)); err != nil {
+			t.Fatalf("[TestSessionAbandon] expected no error on (%s), got (%s)", NameOfMessageForType(message.MessageType()), err)
+		}
+	}
+
+	dbRequestPDU := marshalForSessionTest(t, &TypedDeleteBearerRequest{SequenceNumber: 3, EPSBearerIDs: []uint8{6}})
+	if err := session.Apply(dbRequestPDU); err != nil {
+		t.Fatalf("[TestSessionAbandon] expected no error on Delete Bearer Request, got (%s)", err)
+	}
+
+	if err := session.Abandon(dbRequestPDU); err != nil {
+		t.Fatalf("[TestSessionAbandon] expected no error on Abandon of Delete Bearer Request, got (%s)", err)
+	}
+
+	if bearer, _ := session.Bearer(6); bearer == nil || bearer.State != SessionStateActive {
+		t.Errorf("[TestSessionAbandon] expected bearer (6) in state Active after Delete Bearer Request is abandoned")
+	}
+
+	dsRequestPDU := marshalForSessionTest(t, &TypedDeleteSessionRequest{SequenceNumber: 4, LinkedEBI: uint8Pointer(5)})
+	if err := session.Apply(dsRequestPDU); err != nil {
+		t.Fatalf("[TestSessionAbandon] expected no error on Delete Session Request, got (%s)", err)
+	}
+
+	if err := session.Abandon(dsRequestPDU); err != nil {
+		t.Fatalf("[TestSessionAbandon] expected no error on Abandon of Delete Session Request, got (%s)", err)
+	}
+
+	if pdnConnection := session.PDNConnections[5]; pdnConnection == nil || pdnConnection.State != SessionStateActive {
+		t.Errorf("[TestSessionAbandon] expected PDN connection (5) in state Active after Delete Session Request is abandoned")
+	}
+
+	// the PDN connection can be deleted again
+	if err := session.Apply(marshalForSessionTest(t, &TypedDeleteSessionRequest{SequenceNumber: 5, LinkedEBI: uint8Pointer(5)})); err != nil {
+		t.Errorf("[TestSessionAbandon] expected no error on Delete Session Request after abandoned one, got (%s)", err)
+	}
+}
@@ -2,6 +2,12 @@ package gtpv2
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
 
 	"gopkg.in/yaml.v2"
 )
@@ -84,35 +90,112 @@ var mapOfYamlPduTypeToMessageType = map[string]MessageType{
 	"UpdatePDNConnectionSetResponse":             UpdatePDNConnectionSetResponse,
 }
 
+// mapOfYamlIETypeToBuilder provides, for each IE Type that may appear in a
+// template, the function that builds the IE from its Value
+var mapOfYamlIETypeToBuilder = map[string]func(value interface{}) (*IE, error){
+	"IMSI":           buildIMSIFromYaml,
+	"MSISDN":         buildMSISDNFromYaml,
+	"MEI":            buildMEIFromYaml,
+	"APN":            buildAPNFromYaml,
+	"ServingNetwork": buildServingNetworkFromYaml,
+	"F-TEID":         buildFTEIDFromYaml,
+	"PAA":            buildPAAFromYaml,
+	"PDNType":        buildPDNTypeFromYaml,
+	"BearerQoS":      buildBearerQoSFromYaml,
+	"AMBR":           buildAMBRFromYaml,
+	"Cause":          buildCauseFromYaml,
+	"EBI":            uint8IEBuilder(EBI),
+	"RATType":        uint8IEBuilder(RATType),
+	"SelectionMode":  uint8IEBuilder(SelectionMode),
+	"Recovery":       uint8IEBuilder(RecoveryRestartCounter),
+	"ChargingID":     uint32IEBuilder(ChargingID),
+	"BearerContext":  nil, // built from its IEs by generateIEsFromYaml
+}
+
+// IEYaml is an IE in a template.  Type is a key of the IE builders (e.g.,
+// "F-TEID"), and Value is the scalar, map or list that the builder for Type
+// accepts.  A BearerContext has IEs rather than a Value.
 type IEYaml struct {
-	Type  string      `yaml:"Type"`
-	Value interface{} `yaml:"Value"`
+	Type     string      `yaml:"Type"`
+	Instance uint8       `yaml:"Instance"`
+	Value    interface{} `yaml:"Value"`
+	IEs      []IEYaml    `yaml:"IEs"`
 }
 
+// Gtpv2PduYaml is a PDU in a template.  Name identifies the PDU in the
+// template, and Type is its message type (e.g., "CreateSessionRequest").  If
+// TEID is set, the PDU has a TEID field.
 type Gtpv2PduYaml struct {
 	Name string   `yaml:"Name"`
 	Type string   `yaml:"Type"`
+	TEID string   `yaml:"TEID"`
 	IEs  []IEYaml `yaml:"IEs"`
 }
 
 type GtpDefinitionRootYaml struct {
-	Gtpv2Pdus []Gtpv2PduYaml
+	Gtpv2Pdus []Gtpv2PduYaml `yaml:"Gtpv2Pdus"`
 }
 
 func validateGtpv2PduYaml(yaml Gtpv2PduYaml) error {
-	if _, providedPduTypeIsValid := mapOfYamlPduTypeToMessageType[yaml.Name]; !providedPduTypeIsValid {
-		return fmt.Errorf("provided PDU Type (%s) is not recognized", yaml.Name)
+	if yaml.Name == "" {
+		return fmt.Errorf("PDU of Type (%s) has no Name", yaml.Type)
+	}
+
+	if _, providedPduTypeIsValid := mapOfYamlPduTypeToMessageType[yaml.Type]; !providedPduTypeIsValid {
+		return fmt.Errorf("provided PDU Type (%s) is not recognized", yaml.Type)
+	}
+
+	return validateIEYamls(yaml.IEs)
+}
+
+func validateIEYamls(ieYamls []IEYaml) error {
+	for _, ieYaml := range ieYamls {
+		if _, providedIETypeIsValid := mapOfYamlIETypeToBuilder[ieYaml.Type]; !providedIETypeIsValid {
+			return fmt.Errorf("provided IE Type (%s) is not recognized", ieYaml.Type)
+		}
+
+		if ieYaml.Instance > 15 {
+			return fmt.Errorf("IE (%s) Instance (%d) is greater than 15", ieYaml.Type, ieYaml.Instance)
+		}
+
+		if err := validateIEYamls(ieYaml.IEs); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// t := gtpv2.ReadYamlTemplateFromFile( "/path/to/file.yaml" )
-// csr := t.GeneratePDUByName( "CSR01" )
+// Template is a set of named PDUs read from YAML, from which PDUs are
+// generated.  Any string in the YAML may be a text/template, which is executed
+// with the data passed to GeneratePDUByName.  For example:
+//
+//	Gtpv2Pdus:
+//	  - Name: attach
+//	    Type: CreateSessionRequest
+//	    TEID: "0"
+//	    IEs:
+//	      - Type: IMSI
+//	        Value: "{{ .IMSI }}"
+//	      - Type: ServingNetwork
+//	        Value: { MCC: "001", MNC: "01" }
+//	      - Type: F-TEID
+//	        Value: { InterfaceType: 10, Key: "{{ .TEID }}", IPv4: 10.0.0.1 }
+//	      - Type: BearerContext
+//	        IEs:
+//	          - Type: EBI
+//	            Value: 5
+//
+// then
+//
+//	t, err := gtpv2.ReadYamlTemplateFromFile("/path/to/file.yaml")
+//	csr, err := t.GeneratePDUByName("attach", map[string]string{"IMSI": "001010000000001", "TEID": "1"})
 type Template struct {
 	mapOfGtpv2PduYamlByName map[string]Gtpv2PduYaml
 }
 
+// ReadYamlTemplateFromString reads a Template from a YAML string.  Returns an
+// error if the YAML cannot be parsed, or a PDU or IE Type is not recognized.
 func ReadYamlTemplateFromString(yamlDefinition string) (*Template, error) {
 	unmarhalledYaml := &GtpDefinitionRootYaml{}
 	if err := yaml.Unmarshal([]byte(yamlDefinition), &unmarhalledYaml); err != nil {
@@ -130,6 +213,10 @@ func ReadYamlTemplateFromString(yamlDefinition string) (*Template, error) {
 			return nil, err
 		}
 
+		if _, nameIsDuplicate := mapOfGtpv2PduYamlByName[pduDefinitionYaml.Name]; nameIsDuplicate {
+			return nil, fmt.Errorf("PDU Name (%s) is used more than once", pduDefinitionYaml.Name)
+		}
+
 		mapOfGtpv2PduYamlByName[pduDefinitionYaml.Name] = pduDefinitionYaml
 	}
 
@@ -138,34 +225,436 @@ func ReadYamlTemplateFromString(yamlDefinition string) (*Template, error) {
 	}, nil
 }
 
+// ReadYamlTemplateFromFile reads a Template from a YAML file
 func ReadYamlTemplateFromFile(filePath string) (*Template, error) {
-	return nil, nil
-}
-
-// Gtpv2Pdus:
-//   - Name: CreateSessionRequest
-//     Type: CreateSessionRequest
-//     IEs:
-//       - Type: "IMSI"
-//         Value: "{{ Requestor.IMSI }}"
-//       - Type: "MEI"
-//         Value: "01-124500-012345-01"
-//       - Type: "ServingNetwork"
-//         Value: "001001"
-//       - Type: "F-TEID"
-//         Value: ...
-//       - Type: "F-TEID"
-//         Value: ...
-//       - Type: "APN"
-//         Value: "{{ Requestor.APN }}"
-//       - Type: "PDNType"
-//         Value: "{{ Requestor.PDNType }}"
-//       - Type: "BearerContext"
-//         Value:
-//           - Type: "EBI"
-//             Value: ...
-//           - Type: "F-TEID"
-//             Value: ...
-//           - Type: "F-TEID"
-//             Value: ...
-//           -
+	contents, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadYamlTemplateFromString(string(contents))
+}
+
+// HasPDU returns true if the Template has a PDU with the name
+func (t *Template) HasPDU(name string) bool {
+	_, isPresent := t.mapOfGtpv2PduYamlByName[name]
+	return isPresent
+}
+
+// GeneratePDUByName generates the PDU with the name.  Each string in the PDU
+// definition is executed as a text/template with data before it is used.  The
+// sequence number of the PDU is 0.  Returns an error if there is no PDU with
+// the name, a template cannot be executed, or a Value is not valid for its IE
+// Type.
+func (t *Template) GeneratePDUByName(name string, data interface{}) (*PDU, error) {
+	pduYaml, isPresent := t.mapOfGtpv2PduYamlByName[name]
+	if !isPresent {
+		return nil, fmt.Errorf("template has no PDU named (%s)", name)
+	}
+
+	ies, err := generateIEsFromYaml(pduYaml.IEs, data)
+	if err != nil {
+		return nil, fmt.Errorf("on PDU (%s): %s", name, err)
+	}
+
+	pdu := NewPDU(mapOfYamlPduTypeToMessageType[pduYaml.Type], 0, ies)
+
+	if pduYaml.TEID != "" {
+		teid, err := expandYamlValue(pduYaml.TEID, data)
+		if err != nil {
+			return nil, fmt.Errorf("on PDU (%s) TEID: %s", name, err)
+		}
+
+		value, err := uint64FromYaml(teid, 32)
+		if err != nil {
+			return nil, fmt.Errorf("on PDU (%s) TEID: %s", name, err)
+		}
+
+		pdu.AddTEID(uint32(value))
+	}
+
+	return pdu, nil
+}
+
+func generateIEsFromYaml(ieYamls []IEYaml, data interface{}) ([]*IE, error) {
+	ies := make([]*IE, 0, len(ieYamls))
+
+	for _, ieYaml := range ieYamls {
+		var ie *IE
+		var err error
+
+		if ieYaml.Type == "BearerContext" {
+			var groupedIEs []*IE
+			if groupedIEs, err = generateIEsFromYaml(ieYaml.IEs, data); err == nil {
+				ie, err = NewGroupedIEErrorable(BearerContext, groupedIEs)
+			}
+		} else {
+			var value interface{}
+			if value, err = expandYamlValue(ieYaml.Value, data); err == nil {
+				ie, err = mapOfYamlIETypeToBuilder[ieYaml.Type](value)
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("on IE (%s): %s", ieYaml.Type, err)
+		}
+
+		ie.InstanceNumber = ieYaml.Instance
+		ies = append(ies, ie)
+	}
+
+	return ies, nil
+}
+
+// expandYamlValue returns a copy of value in which each string is replaced by
+// the result of executing it as a text/template with data
+func expandYamlValue(value interface{}, data interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		if !strings.Contains(value, "{{") {
+			return value, nil
+		}
+
+		parsed, err := template.New("").Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		var expanded strings.Builder
+		if err := parsed.Execute(&expanded, data); err != nil {
+			return nil, err
+		}
+
+		return expanded.String(), nil
+
+	case map[interface{}]interface{}:
+		expandedMap := make(map[interface{}]interface{}, len(value))
+		for key, element := range value {
+			expandedElement, err := expandYamlValue(element, data)
+			if err != nil {
+				return nil, err
+			}
+			expandedMap[key] = expandedElement
+		}
+		return expandedMap, nil
+
+	case []interface{}:
+		expandedList := make([]interface{}, len(value))
+		for i, element := range value {
+			expandedElement, err := expandYamlValue(element, data)
+			if err != nil {
+				return nil, err
+			}
+			expandedList[i] = expandedElement
+		}
+		return expandedList, nil
+	}
+
+	return value, nil
+}
+
+func stringFromYaml(value interface{}) (string, error) {
+	if asString, isString := value.(string); isString {
+		return asString, nil
+	}
+
+	return "", fmt.Errorf("value (%v) must be a string", value)
+}
+
+// uint64FromYaml converts a YAML integer, or a string holding one, to a value
+// that fits in bitSize bits
+func uint64FromYaml(value interface{}, bitSize int) (uint64, error) {
+	var asUint64 uint64
+	var err error
+
+	switch value := value.(type) {
+	case int:
+		if value < 0 {
+			return 0, fmt.Errorf("value (%d) must not be negative", value)
+		}
+		asUint64 = uint64(value)
+	case uint64:
+		asUint64 = value
+	case string:
+		if asUint64, err = strconv.ParseUint(strings.TrimSpace(value), 0, 64); err != nil {
+			return 0, fmt.Errorf("value (%s) is not an unsigned integer", value)
+		}
+	default:
+		return 0, fmt.Errorf("value (%v) must be an unsigned integer", value)
+	}
+
+	if bitSize < 64 && asUint64 >= 1<<bitSize {
+		return 0, fmt.Errorf("value (%d) does not fit in (%d) bits", asUint64, bitSize)
+	}
+
+	return asUint64, nil
+}
+
+func ipFromYaml(value interface{}) (net.IP, error) {
+	asString, err := stringFromYaml(value)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(asString)
+	if ip == nil {
+		return nil, fmt.Errorf("value (%s) is not an IP address", asString)
+	}
+
+	return ip, nil
+}
+
+// yamlFields accesses the fields of a YAML map Value by name
+type yamlFields map[interface{}]interface{}
+
+func yamlFieldsFrom(value interface{}, knownFields ...string) (yamlFields, error) {
+	fields, isMap := value.(map[interface{}]interface{})
+	if !isMap {
+		return nil, fmt.Errorf("value must be a map with fields (%s)", strings.Join(knownFields, ", "))
+	}
+
+	for key := range fields {
+		if asString, isString := key.(string); !isString || !stringIsIn(asString, knownFields) {
+			return nil, fmt.Errorf("field (%v) is not one of (%s)", key, strings.Join(knownFields, ", "))
+		}
+	}
+
+	return fields, nil
+}
+
+func stringIsIn(value string, list []string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uint sets *into from the named field, if it is present, and returns the
+// first error from this or an earlier call
+func (fields yamlFields) uint(name string, bitSize int, into *uint64, earlierErr error) error {
+	if earlierErr != nil {
+		return earlierErr
+	}
+
+	if value, isPresent := fields[name]; isPresent {
+		asUint64, err := uint64FromYaml(value, bitSize)
+		if err != nil {
+			return fmt.Errorf("on %s: %s", name, err)
+		}
+		*into = asUint64
+	}
+
+	return nil
+}
+
+func (fields yamlFields) ip(name string, into *net.IP, earlierErr error) error {
+	if earlierErr != nil {
+		return earlierErr
+	}
+
+	if value, isPresent := fields[name]; isPresent {
+		ip, err := ipFromYaml(value)
+		if err != nil {
+			return fmt.Errorf("on %s: %s", name, err)
+		}
+		*into = ip
+	}
+
+	return nil
+}
+
+func buildIMSIFromYaml(value interface{}) (*IE, error) {
+	asString, err := stringFromYaml(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedIMSI{AsString: asString}).ToIEErrorable()
+}
+
+func buildMSISDNFromYaml(value interface{}) (*IE, error) {
+	asString, err := stringFromYaml(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedMSISDN{AsString: asString}).ToIEErrorable()
+}
+
+func buildMEIFromYaml(value interface{}) (*IE, error) {
+	asString, err := stringFromYaml(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedMEI{AsString: asString}).ToIEErrorable()
+}
+
+func buildAPNFromYaml(value interface{}) (*IE, error) {
+	asString, err := stringFromYaml(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedAPN{AsString: asString}).ToIEErrorable()
+}
+
+func buildServingNetworkFromYaml(value interface{}) (*IE, error) {
+	fields, err := yamlFieldsFrom(value, "MCC", "MNC")
+	if err != nil {
+		return nil, err
+	}
+
+	servingNetwork := &TypedServingNetwork{}
+	if servingNetwork.PLMN.MCC, err = stringFromYaml(fields["MCC"]); err != nil {
+		return nil, fmt.Errorf("on MCC: %s", err)
+	}
+
+	if servingNetwork.PLMN.MNC, err = stringFromYaml(fields["MNC"]); err != nil {
+		return nil, fmt.Errorf("on MNC: %s", err)
+	}
+
+	return servingNetwork.ToIEErrorable()
+}
+
+func buildFTEIDFromYaml(value interface{}) (*IE, error) {
+	fields, err := yamlFieldsFrom(value, "InterfaceType", "Key", "IPv4", "IPv6")
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaceType, key uint64
+	fteid := &TypedFTEID{}
+
+	err = fields.uint("InterfaceType", 6, &interfaceType, err)
+	err = fields.uint("Key", 32, &key, err)
+	err = fields.ip("IPv4", &fteid.IPv4Addr, err)
+	err = fields.ip("IPv6", &fteid.IPv6Addr, err)
+	if err != nil {
+		return nil, err
+	}
+
+	fteid.InterfaceType = uint8(interfaceType)
+	fteid.Key = uint32(key)
+
+	return fteid.ToIEErrorable()
+}
+
+func buildPAAFromYaml(value interface{}) (*IE, error) {
+	fields, err := yamlFieldsFrom(value, "PDNType", "IPv4", "IPv6Prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	var pdnType uint64
+	paa := &TypedPAA{}
+
+	err = fields.uint("PDNType", 3, &pdnType, err)
+	err = fields.ip("IPv4", &paa.IPv4Address, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix, isPresent := fields["IPv6Prefix"]; isPresent {
+		asString, err := stringFromYaml(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("on IPv6Prefix: %s", err)
+		}
+
+		if paa.IPv6Prefix, err = netip.ParsePrefix(asString); err != nil {
+			return nil, fmt.Errorf("on IPv6Prefix: %s", err)
+		}
+	}
+
+	paa.PDNType = PDNTypeValue(pdnType)
+
+	return paa.ToIEErrorable()
+}
+
+func buildPDNTypeFromYaml(value interface{}) (*IE, error) {
+	pdnType, err := uint64FromYaml(value, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedPDNType{Value: PDNTypeValue(pdnType)}).ToIEErrorable()
+}
+
+func buildBearerQoSFromYaml(value interface{}) (*IE, error) {
+	fields, err := yamlFieldsFrom(value, "PriorityLevel", "QCI", "MaximumBitRateUplink", "MaximumBitRateDownlink", "GuaranteedBitRateUplink", "GuaranteedBitRateDownlink")
+	if err != nil {
+		return nil, err
+	}
+
+	var priorityLevel, qci, mbrUplink, mbrDownlink, gbrUplink, gbrDownlink uint64
+
+	err = fields.uint("PriorityLevel", 4, &priorityLevel, err)
+	err = fields.uint("QCI", 8, &qci, err)
+	err = fields.uint("MaximumBitRateUplink", 64, &mbrUplink, err)
+	err = fields.uint("MaximumBitRateDownlink", 64, &mbrDownlink, err)
+	err = fields.uint("GuaranteedBitRateUplink", 64, &gbrUplink, err)
+	err = fields.uint("GuaranteedBitRateDownlink", 64, &gbrDownlink, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedBearerQoS{
+		PriorityLevel:             uint8(priorityLevel),
+		QCI:                       uint8(qci),
+		MaximumBitRateUplink:      BitRate(mbrUplink),
+		MaximumBitRateDownlink:    BitRate(mbrDownlink),
+		GuaranteedBitRateUplink:   BitRate(gbrUplink),
+		GuaranteedBitRateDownlink: BitRate(gbrDownlink),
+	}).ToIEErrorable()
+}
+
+func buildAMBRFromYaml(value interface{}) (*IE, error) {
+	fields, err := yamlFieldsFrom(value, "Uplink", "Downlink")
+	if err != nil {
+		return nil, err
+	}
+
+	var uplink, downlink uint64
+
+	err = fields.uint("Uplink", 64, &uplink, err)
+	err = fields.uint("Downlink", 64, &downlink, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedAMBR{Uplink: BitRate(uplink), Downlink: BitRate(downlink)}).ToIEErrorable()
+}
+
+func buildCauseFromYaml(value interface{}) (*IE, error) {
+	cause, err := uint64FromYaml(value, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&TypedCause{Value: CauseValue(cause)}).ToIEErrorable()
+}
+
+func uint8IEBuilder(ieType IEType) func(value interface{}) (*IE, error) {
+	return func(value interface{}) (*IE, error) {
+		asUint64, err := uint64FromYaml(value, 8)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewIEWithRawDataErrorable(ieType, []byte{uint8(asUint64)})
+	}
+}
+
+func uint32IEBuilder(ieType IEType) func(value interface{}) (*IE, error) {
+	return func(value interface{}) (*IE, error) {
+		asUint64, err := uint64FromYaml(value, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewIEWithRawDataErrorable(ieType, []byte{uint8(asUint64 >> 24), uint8(asUint64 >> 16), uint8(asUint64 >> 8), uint8(asUint64)})
+	}
+}
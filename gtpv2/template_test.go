@@ -2,9 +2,10 @@ package gtpv2_test
 
 import (
 	"fmt"
+	"net"
 	"testing"
 
-	"github.com/blorticus/gtpv2"
+	"github.com/blorticus-go/gtp/gtpv2"
 )
 
 var badYamlDefintions = []string{
@@ -58,3 +59,120 @@ func TestReadYamlTemplateFromString(t *testing.T) {
 	}
 
 }
+
+var templateWithAttach = `---
+Gtpv2Pdus:
+    - Name: attach
+      Type: CreateSessionRequest
+      TEID: "0"
+      IEs:
+        - Type: IMSI
+          Value: "{{ .IMSI }}"
+        - Type: ServingNetwork
+          Value: { MCC: "001", MNC: "01" }
+        - Type: RATType
+          Value: 6
+        - Type: F-TEID
+          Value: { InterfaceType: 10, Key: "{{ .TEID }}", IPv4: 10.0.0.1 }
+        - Type: APN
+          Value: internet
+        - Type: BearerContext
+          IEs:
+            - Type: EBI
+              Value: 5
+            - Type: BearerQoS
+              Value: { PriorityLevel: 9, QCI: 9 }
+`
+
+func TestGeneratePDUByName(t *testing.T) {
+	template, err := gtpv2.ReadYamlTemplateFromString(templateWithAttach)
+	if err != nil {
+		t.Fatalf("[TestGeneratePDUByName] expected no error on ReadYamlTemplateFromString, got (%s)", err)
+	}
+
+	if !template.HasPDU("attach") || template.HasPDU("detach") {
+		t.Errorf("[TestGeneratePDUByName] expected template to have PDU (attach) and not (detach)")
+	}
+
+	pdu, err := template.GeneratePDUByName("attach", map[string]string{"IMSI": "001010000000001", "TEID": "0x0a0b0c0d"})
+	if err != nil {
+		t.Fatalf("[TestGeneratePDUByName] expected no error on GeneratePDUByName, got (%s)", err)
+	}
+
+	if pdu.Type != gtpv2.CreateSessionRequest || !pdu.TEIDFieldIsPresent || pdu.TEID != 0 {
+		t.Errorf("[TestGeneratePDUByName] PDU header is not correct")
+	}
+
+	decodedPDU, _, err := gtpv2.DecodePDU(pdu.Encode())
+	if err != nil {
+		t.Fatalf("[TestGeneratePDUByName] expected no error on DecodePDU, got (%s)", err)
+	}
+
+	request := &gtpv2.TypedCreateSessionRequest{}
+	if err := request.Unmarshal(decodedPDU); err != nil {
+		t.Fatalf("[TestGeneratePDUByName] expected no error on Unmarshal, got (%s)", err)
+	}
+
+	if request.IMSI.AsString != "001010000000001" || request.APN.AsString != "internet" || *request.RATType != 6 {
+		t.Errorf("[TestGeneratePDUByName] expected IMSI, APN and RAT Type from template, got (%v, %v, %v)", request.IMSI, request.APN, request.RATType)
+	}
+
+	if request.ServingNetwork.PLMN.MCC != "001" || request.ServingNetwork.PLMN.MNC != "01" {
+		t.Errorf("[TestGeneratePDUByName] expected Serving Network (001-01), got (%s)", request.ServingNetwork.PLMN)
+	}
+
+	if fteid := request.SenderFTEIDForControlPlane; fteid == nil || fteid.Key != 0x0a0b0c0d || fteid.InterfaceType != 10 || !fteid.IPv4Addr.Equal(net.IP{10, 0, 0, 1}) {
+		t.Errorf("[TestGeneratePDUByName] expected Sender F-TEID from template, got (%v)", fteid)
+	}
+
+	if len(request.BearerContextsToBeCreated) != 1 || request.BearerContextsToBeCreated[0].EBI != 5 || request.BearerContextsToBeCreated[0].BearerQoS.QCI != 9 {
+		t.Errorf("[TestGeneratePDUByName] expected Bearer Context with EBI (5) and QCI (9)")
+	}
+
+	if _, err := template.GeneratePDUByName("attach", map[string]string{"IMSI": "001010000000001"}); err == nil {
+		t.Errorf("[TestGeneratePDUByName] expected error when template data is missing, got none")
+	}
+
+	if _, err := template.GeneratePDUByName("attach", map[string]string{"IMSI": "001010000000001", "TEID": "0x100000000"}); err == nil {
+		t.Errorf("[TestGeneratePDUByName] expected error when F-TEID Key does not fit in 32 bits, got none")
+	}
+
+	if _, err := template.GeneratePDUByName("detach", nil); err == nil {
+		t.Errorf("[TestGeneratePDUByName] expected error for unknown PDU name, got none")
+	}
+}
+
+var invalidTemplateDefinitions = []string{
+	`---
+Gtpv2Pdus:
+    - Name: csr
+      Type: NotAMessage
+`,
+	`---
+Gtpv2Pdus:
+    - Type: CreateSessionRequest
+`,
+	`---
+Gtpv2Pdus:
+    - Name: csr
+      Type: CreateSessionRequest
+    - Name: csr
+      Type: DeleteSessionRequest
+`,
+	`---
+Gtpv2Pdus:
+    - Name: csr
+      Type: CreateSessionRequest
+      IEs:
+        - Type: NotAnIE
+          Value: 1
+`,
+}
+
+func TestInvalidTemplateDefinitions(t *testing.T) {
+	for testNumber, definition := range invalidTemplateDefinitions {
+		if _, err := gtpv2.ReadYamlTemplateFromString(definition); err == nil {
+			t.Errorf("[TestInvalidTemplateDefinitions] on test number [%d] expected error, got none", testNumber)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package gtpv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Default values for the TransportConfig fields.  TS 29.274 section 7.6 leaves
+// T3-RESPONSE and N3-REQUESTS to the operator.
+const (
+	DefaultT3Response          = 3 * time.Second
+	DefaultN3Requests          = 3
+	DefaultIncomingQueueLength = 64
+)
+
+// ErrNoResponse is returned by Transport.SendRequest when no response is
+// received after the last retransmission of a request
+var ErrNoResponse = errors.New("no response received from peer")
+
+// TransportConfig provides the timers and limits for a Transport.  A zero
+// value for a field means that the default is used.
+type TransportConfig struct {
+	// T3Response is how long to wait for a response before retransmitting a
+	// request.  The default is DefaultT3Response.
+	T3Response time.Duration
+
+	// N3Requests is the maximum number of times that a request is
+	// retransmitted.  The default is DefaultN3Requests.  If it is less than
+	// zero, a request is never retransmitted.
+	N3Requests int
+
+	// ResponseCacheTime is how long a sent response is kept in order to
+	// answer a retransmission of the request.  The default is T3Response
+	// multiplied by one more than N3Requests.
+	ResponseCacheTime time.Duration
+
+	// IncomingQueueLength is the number of received requests that are held
+	// until they are retrieved by Transport.Receive.  A request that arrives
+	// when the queue is full is discarded, and will be accepted if the peer
+	// retransmits it.  The default is DefaultIncomingQueueLength.
+	IncomingQueueLength int
+}
+
+// IncomingMessage is a message received by a Transport.  If Err is not nil,
+// the message is a request that could not be decoded, PDU is only its header
+// (see DecodeRequest), and PDU and Err may be passed to NewErrorResponse.
+// PiggybackedPDU is the message piggybacked on PDU, if there is one.  The
+// piggybacked message is also received as its own IncomingMessage, so a
+// piggybacked request is returned by Transport.Receive.
+type IncomingMessage struct {
+	PDU            *PDU
+	PiggybackedPDU *PDU
+	Peer           net.Addr
+	Err            error
+}
+
+// Transport sends and receives GTPv2 messages over a net.PacketConn (usually
+// a UDP socket), providing the reliable delivery procedures of TS 29.274
+// section 7.6.  A request is retransmitted until a response is received, and a
+// response is matched to its request by the peer and sequence number (see
+// CorrelationKeyFor).  Each sent response is cached, so that a retransmitted
+// request is answered with the same response, and is not delivered again.  A
+// Transport does not allocate sequence numbers, so each request must have a
+// sequence number that is not in use for another request to the same peer.
+type Transport struct {
+	conn     net.PacketConn
+	config   TransportConfig
+	incoming chan *IncomingMessage
+
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	lock     sync.Mutex
+	pending  map[CorrelationKey]chan *IncomingMessage
+	received map[CorrelationKey]*receivedTransaction
+}
+
+// receivedTransaction tracks a request received from a peer.  response is nil
+// until the response is sent.
+type receivedTransaction struct {
+	response []byte
+	expiry   *time.Timer
+}
+
+// NewTransport creates a Transport that uses conn, and starts reading from it.
+// The Transport owns conn, which is closed by Transport.Close.
+func NewTransport(conn net.PacketConn, config TransportConfig) *Transport {
+	if config.T3Response <= 0 {
+		config.T3Response = DefaultT3Response
+	}
+
+	if config.N3Requests == 0 {
+		config.N3Requests = DefaultN3Requests
+	} else if config.N3Requests < 0 {
+		config.N3Requests = 0
+	}
+
+	if config.ResponseCacheTime <= 0 {
+		config.ResponseCacheTime = config.T3Response * time.Duration(config.N3Requests+1)
+	}
+
+	if config.IncomingQueueLength <= 0 {
+		config.IncomingQueueLength = DefaultIncomingQueueLength
+	}
+
+	transport := &Transport{
+		conn:     conn,
+		config:   config,
+		incoming: make(chan *IncomingMessage, config.IncomingQueueLength),
+		closed:   make(chan struct{}),
+		pending:  make(map[CorrelationKey]chan *IncomingMessage),
+		received: make(map[CorrelationKey]*receivedTransaction),
+	}
+
+	go transport.readLoop()
+
+	return transport
+}
+
+// LocalAddr returns the local address of the underlying connection
+func (transport *Transport) LocalAddr() net.Addr {
+	return transport.conn.LocalAddr()
+}
+
+// SendRequest sends a request (or command, or notification that is
+// acknowledged) to a peer, and waits for the message that answers it, which is
+// usually the response, but may be a request triggered by a command, or a
+// Version Not Supported Indication.  The request is retransmitted each time
+// T3Response passes without an answer, up to N3Requests times.  Returns
+// ErrNoResponse if there is still no answer after that, ctx.Err() if ctx is
+// done first, and net.ErrClosed if the Transport is closed.  Returns an error
+// if the message type is not answered, or if a request with the same sequence
+// number to the same peer is pending.  A response that is itself answered, like
+// a Context Response, is also cached as with SendResponse.
+func (transport *Transport) SendRequest(ctx context.Context, peer net.Addr, request *PDU) (*IncomingMessage, error) {
+	if !IsRequest(request.Type) {
+		return nil, fmt.Errorf("message type (%s) is not answered by a response", NameOfMessageForType(request.Type))
+	}
+
+	key := CorrelationKeyFor(peer, request, true)
+	answer := make(chan *IncomingMessage, 1)
+
+	transport.lock.Lock()
+	if _, keyIsInUse := transport.pending[key]; keyIsInUse {
+		transport.lock.Unlock()
+		return nil, fmt.Errorf("a request with sequence number (%d) to peer (%s) is already pending", request.SequenceNumber, key.Peer)
+	}
+	transport.pending[key] = answer
+
+	stream := request.Encode()
+	if IsResponse(request.Type) {
+		transport.cacheResponse(key, stream)
+	}
+	transport.lock.Unlock()
+
+	defer func() {
+		transport.lock.Lock()
+		delete(transport.pending, key)
+		transport.lock.Unlock()
+	}()
+
+	timer := time.NewTimer(transport.config.T3Response)
+	defer timer.Stop()
+
+	for retransmissions := 0; ; retransmissions++ {
+		if _, err := transport.conn.WriteTo(stream, peer); err != nil {
+			if transport.isClosed() {
+				return nil, net.ErrClosed
+			}
+			return nil, err
+		}
+
+		select {
+		case message := <-answer:
+			return message, nil
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-transport.closed:
+			return nil, net.ErrClosed
+
+		case <-timer.C:
+			if retransmissions >= transport.config.N3Requests {
+				return nil, ErrNoResponse
+			}
+			timer.Reset(transport.config.T3Response)
+		}
+	}
+}
+
+// SendResponse sends a response to a peer, and caches it for ResponseCacheTime
+// so that it is sent again if the peer retransmits the request.  Returns an
+// error if the message type is not a response, or if the write fails.
+func (transport *Transport) SendResponse(peer net.Addr, response *PDU) error {
+	if !IsResponse(response.Type) {
+		return fmt.Errorf("message type (%s) is not a response", NameOfMessageForType(response.Type))
+	}
+
+	if transport.isClosed() {
+		return net.ErrClosed
+	}
+
+	stream := response.Encode()
+	key := CorrelationKeyFor(peer, response, true)
+
+	transport.lock.Lock()
+	transport.cacheResponse(key, stream)
+	transport.lock.Unlock()
+
+	_, err := transport.conn.WriteTo(stream, peer)
+	return err
+}
+
+// Receive returns the next request received from a peer.  A retransmission of
+// a request that has already been received is not returned.  A response that
+// does not answer a pending request is discarded.  Returns ctx.Err() if ctx is
+// done first, and net.ErrClosed if the Transport is closed.
+func (transport *Transport) Receive(ctx context.Context) (*IncomingMessage, error) {
+	select {
+	case message := <-transport.incoming:
+		return message, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-transport.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close stops the Transport and closes the underlying connection.  Pending
+// calls to SendRequest and Receive return net.ErrClosed.
+func (transport *Transport) Close() error {
+	transport.shutdown()
+	return transport.conn.Close()
+}
+
+func (transport *Transport) shutdown() {
+	transport.closeOnce.Do(func() {
+		close(transport.closed)
+	})
+}
+
+func (transport *Transport) isClosed() bool {
+	select {
+	case <-transport.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (transport *Transport) readLoop() {
+	buffer := make([]byte, 65536)
+
+	for {
+		length, peer, err := transport.conn.ReadFrom(buffer)
+		if err != nil {
+			transport.shutdown()
+			return
+		}
+
+		stream := make([]byte, length)
+		copy(stream, buffer[:length])
+
+		transport.handleIncoming(stream, peer)
+	}
+}
+
+func (transport *Transport) handleIncoming(stream []byte, peer net.Addr) {
+	pdu, piggybackedPdu, err := DecodeRequest(stream)
+	if pdu == nil {
+		return
+	}
+
+	transport.handleMessage(&IncomingMessage{PDU: pdu, PiggybackedPDU: piggybackedPdu, Peer: peer, Err: err})
+
+	// a piggybacked message (TS 29.274 Annex F) starts its own transaction,
+	// so it is handled as if it had arrived on its own
+	if piggybackedPdu != nil {
+		transport.handleMessage(&IncomingMessage{PDU: piggybackedPdu, Peer: peer})
+	}
+}
+
+// handleMessage delivers a received message to the pending request that it
+// answers, answers it with a cached response if it is a retransmitted
+// request, or adds it to the incoming queue
+func (transport *Transport) handleMessage(message *IncomingMessage) {
+	pdu, peer, err := message.PDU, message.Peer, message.Err
+	key := CorrelationKeyFor(peer, pdu, false)
+
+	transport.lock.Lock()
+
+	// the request that started a transaction shares its key with a pending
+	// response that is itself answered, so only a message that answers
+	// another is delivered to the pending request
+	answer, isPending := transport.pending[key]
+	if isPending && err == nil && ClassOf(pdu.Type, pdu.SequenceNumber) != MessageClassInitial {
+		delete(transport.pending, key)
+		// a request triggered by a command is also a received request, so
+		// that a retransmission of it is not delivered again
+		if IsRequest(pdu.Type) {
+			transport.trackReceivedTransaction(key)
+		}
+		transport.lock.Unlock()
+		answer <- message
+		return
+	}
+
+	if transaction, isKnown := transport.received[key]; isKnown {
+		response := transaction.response
+		transport.lock.Unlock()
+		if response != nil {
+			transport.conn.WriteTo(response, peer)
+		}
+		return
+	}
+
+	if IsResponse(pdu.Type) || (!IsRequest(pdu.Type) && (pdu.Type == VersionNotSupportedIndication || err != nil)) {
+		transport.lock.Unlock()
+		return
+	}
+
+	var transaction *receivedTransaction
+	if IsRequest(pdu.Type) {
+		transaction = transport.trackReceivedTransaction(key)
+	}
+
+	transport.lock.Unlock()
+
+	select {
+	case transport.incoming <- message:
+	default:
+		if transaction != nil {
+			transport.lock.Lock()
+			transport.forgetReceivedTransaction(key, transaction)
+			transport.lock.Unlock()
+		}
+	}
+}
+
+// cacheResponse sets the response for the received transaction with key, and
+// restarts its expiry.  Must be called with the lock held.
+func (transport *Transport) cacheResponse(key CorrelationKey, stream []byte) {
+	transaction, isKnown := transport.received[key]
+	if !isKnown || !transaction.expiry.Stop() {
+		transaction = transport.trackReceivedTransaction(key)
+	} else {
+		transaction.expiry.Reset(transport.config.ResponseCacheTime)
+	}
+	transaction.response = stream
+}
+
+// trackReceivedTransaction adds a receivedTransaction for key, which is removed
+// after ResponseCacheTime.  Must be called with the lock held.
+func (transport *Transport) trackReceivedTransaction(key CorrelationKey) *receivedTransaction {
+	transaction := &receivedTransaction{}
+
+	transaction.expiry = time.AfterFunc(transport.config.ResponseCacheTime, func() {
+		transport.lock.Lock()
+		transport.forgetReceivedTransaction(key, transaction)
+		transport.lock.Unlock()
+	})
+
+	transport.received[key] = transaction
+
+	return transaction
+}
+
+// forgetReceivedTransaction removes the receivedTransaction for key, if it is
+// still transaction.  Must be called with the lock held.
+func (transport *Transport) forgetReceivedTransaction(key CorrelationKey, transaction *receivedTransaction) {
+	if transport.received[key] == transaction {
+		transaction.expiry.Stop()
+		delete(transport.received, key)
+	}
+}
@@ -0,0 +1,279 @@
+package gtpv2
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func newLoopbackTransportAndPeer(t *testing.T, config TransportConfig) (*Transport, net.PacketConn) {
+	transportConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open transport socket: %s", err)
+	}
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open peer socket: %s", err)
+	}
+
+	transport := NewTransport(transportConn, config)
+
+	t.Cleanup(func() {
+		transport.Close()
+		peerConn.Close()
+	})
+
+	return transport, peerConn
+}
+
+func readPDUFromConn(t *testing.T, conn net.PacketConn, timeout time.Duration) (*PDU, net.Addr) {
+	buffer := make([]byte, 65536)
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	length, from, err := conn.ReadFrom(buffer)
+	if err != nil {
+		return nil, nil
+	}
+
+	pdu, _, err := DecodePDU(buffer[:length])
+	if err != nil {
+		t.Errorf("failed to decode PDU from transport: %s", err)
+		return nil, nil
+	}
+
+	return pdu, from
+}
+
+func TestTransportSendRequest(t *testing.T) {
+	transport, peerConn := newLoopbackTransportAndPeer(t, TransportConfig{T3Response: 50 * time.Millisecond, N3Requests: 3})
+
+	go func() {
+		for copiesReceived := 1; ; copiesReceived++ {
+			request, from := readPDUFromConn(t, peerConn, time.Second)
+			if request == nil {
+				return
+			}
+
+			// ignore the original and first retransmission
+			if copiesReceived == 3 {
+				response := NewPDU(ModifyBearerResponse, request.SequenceNumber, []*IE{NewIEWithRawData(Cause, []byte{16, 0})}).AddTEID(0x10)
+				peerConn.WriteTo(response.Encode(), from)
+				return
+			}
+		}
+	}()
+
+	request := NewPDU(ModifyBearerRequest, 0x000102, []*IE{}).AddTEID(0x20)
+
+	answer, err := transport.SendRequest(context.Background(), peerConn.LocalAddr(), request)
+	if err != nil {
+		t.Fatalf("[TestTransportSendRequest] expected no error, got (%s)", err)
+	}
+
+	if answer.PDU.Type != ModifyBearerResponse || answer.PDU.SequenceNumber != 0x000102 || answer.PDU.TEID != 0x10 {
+		t.Errorf("[TestTransportSendRequest] received answer is not correct")
+	}
+
+	if answer.Peer.String() != peerConn.LocalAddr().String() {
+		t.Errorf("[TestTransportSendRequest] expected answer peer (%s), got (%s)", peerConn.LocalAddr(), answer.Peer)
+	}
+}
+
+func TestTransportSendRequestWithoutResponse(t *testing.T) {
+	transport, peerConn := newLoopbackTransportAndPeer(t, TransportConfig{T3Response: 20 * time.Millisecond, N3Requests: 2})
+
+	copiesReceived := make(chan int)
+	go func() {
+		count := 0
+		for {
+			if request, _ := readPDUFromConn(t, peerConn, 200*time.Millisecond); request == nil {
+				copiesReceived <- count
+				return
+			}
+			count++
+		}
+	}()
+
+	request := NewPDU(DeleteSessionRequest, 0x000103, []*IE{}).AddTEID(0x20)
+
+	_, err := transport.SendRequest(context.Background(), peerConn.LocalAddr(), request)
+	if !errors.Is(err, ErrNoResponse) {
+		t.Errorf("[TestTransportSendRequestWithoutResponse] expected ErrNoResponse, got (%v)", err)
+	}
+
+	if count := <-copiesReceived; count != 3 {
+		t.Errorf("[TestTransportSendRequestWithoutResponse] expected peer to receive (3) copies of request, got (%d)", count)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	transport, peerConn = newLoopbackTransportAndPeer(t, TransportConfig{T3Response: time.Second})
+
+	_, err = transport.SendRequest(ctx, peerConn.LocalAddr(), request)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("[TestTransportSendRequestWithoutResponse] expected context.DeadlineExceeded, got (%v)", err)
+	}
+
+	if _, err := transport.SendRequest(context.Background(), peerConn.LocalAddr(), NewPDU(CreateSessionResponse, 1, []*IE{})); err == nil {
+		t.Errorf("[TestTransportSendRequestWithoutResponse] expected error sending response with SendRequest, got none")
+	}
+}
+
+func TestTransportReceiveAndDuplicateRequest(t *testing.T) {
+	transport, peerConn := newLoopbackTransportAndPeer(t, TransportConfig{T3Response: 50 * time.Millisecond, N3Requests: 3})
+
+	request := NewPDU(CreateSessionRequest, 0x000201, []*IE{}).AddTEID(0)
+	peerConn.WriteTo(request.Encode(), transport.LocalAddr())
+
+	incoming, err := transport.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("[TestTransportReceiveAndDuplicateRequest] expected no error on Receive, got (%s)", err)
+	}
+
+	if incoming.PDU.Type != CreateSessionRequest || incoming.PDU.SequenceNumber != 0x000201 || incoming.Err != nil {
+		t.Fatalf("[TestTransportReceiveAndDuplicateRequest] received request is not correct")
+	}
+
+	// a retransmission before the response is sent is discarded
+	peerConn.WriteTo(request.Encode(), transport.LocalAddr())
+
+	response := NewPDU(CreateSessionResponse, incoming.PDU.SequenceNumber, []*IE{NewIEWithRawData(Cause, []byte{16, 0})}).AddTEID(0x30)
+	if err := transport.SendResponse(incoming.Peer, response); err != nil {
+		t.Fatalf("[TestTransportReceiveAndDuplicateRequest] expected no error on SendResponse, got (%s)", err)
+	}
+
+	if pdu, _ := readPDUFromConn(t, peerConn, time.Second); pdu == nil || pdu.Type != CreateSessionResponse {
+		t.Fatalf("[TestTransportReceiveAndDuplicateRequest] expected peer to receive response")
+	}
+
+	// a retransmission after the response is sent is answered with the cached response
+	peerConn.WriteTo(request.Encode(), transport.LocalAddr())
+
+	if pdu, _ := readPDUFromConn(t, peerConn, time.Second); pdu == nil || pdu.Type != CreateSessionResponse || pdu.TEID != 0x30 {
+		t.Errorf("[TestTransportReceiveAndDuplicateRequest] expected peer to receive cached response")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := transport.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("[TestTransportReceiveAndDuplicateRequest] expected retransmitted request not to be delivered, got (%v)", err)
+	}
+
+	// a response that answers nothing is discarded
+	peerConn.WriteTo(NewPDU(DeleteSessionResponse, 0x000301, []*IE{}).AddTEID(1).Encode(), transport.LocalAddr())
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := transport.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("[TestTransportReceiveAndDuplicateRequest] expected unsolicited response not to be delivered, got (%v)", err)
+	}
+}
+
+func TestTransportTriggeredRequest(t *testing.T) {
+	transport, peerConn := newLoopbackTransportAndPeer(t, TransportConfig{T3Response: time.Second})
+
+	command := NewPDU(BearerResourceCommand, CommandSequenceNumberFlag|0x000010, []*IE{}).AddTEID(0x40)
+	triggeredRequest := NewPDU(CreateBearerRequest, command.SequenceNumber, []*IE{}).AddTEID(0x50)
+
+	go func() {
+		if received, from := readPDUFromConn(t, peerConn, time.Second); received != nil {
+			peerConn.WriteTo(triggeredRequest.Encode(), from)
+		}
+	}()
+
+	answer, err := transport.SendRequest(context.Background(), peerConn.LocalAddr(), command)
+	if err != nil {
+		t.Fatalf("[TestTransportTriggeredRequest] expected no error, got (%s)", err)
+	}
+
+	if answer.PDU.Type != CreateBearerRequest || answer.PDU.SequenceNumber != command.SequenceNumber {
+		t.Fatalf("[TestTransportTriggeredRequest] expected triggered Create Bearer Request as answer")
+	}
+
+	// a retransmission of the triggered request before the response is sent
+	// is discarded
+	peerConn.WriteTo(triggeredRequest.Encode(), transport.LocalAddr())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := transport.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("[TestTransportTriggeredRequest] expected retransmitted triggered request not to be delivered, got (%v)", err)
+	}
+
+	response := NewPDU(CreateBearerResponse, answer.PDU.SequenceNumber, []*IE{NewIEWithRawData(Cause, []byte{16, 0})}).AddTEID(0x60)
+	if err := transport.SendResponse(answer.Peer, response); err != nil {
+		t.Fatalf("[TestTransportTriggeredRequest] expected no error on SendResponse, got (%s)", err)
+	}
+
+	if pdu, _ := readPDUFromConn(t, peerConn, time.Second); pdu == nil || pdu.Type != CreateBearerResponse {
+		t.Fatalf("[TestTransportTriggeredRequest] expected peer to receive Create Bearer Response")
+	}
+
+	// a retransmission after the response is sent is answered with the cached
+	// response
+	peerConn.WriteTo(triggeredRequest.Encode(), transport.LocalAddr())
+
+	if pdu, _ := readPDUFromConn(t, peerConn, time.Second); pdu == nil || pdu.Type != CreateBearerResponse || pdu.TEID != 0x60 {
+		t.Errorf("[TestTransportTriggeredRequest] expected peer to receive cached Create Bearer Response")
+	}
+}
+
+func TestTransportContextResponse(t *testing.T) {
+	transport, peerConn := newLoopbackTransportAndPeer(t, TransportConfig{T3Response: time.Second})
+
+	request := NewPDU(ContextRequest, 0x000401, []*IE{}).AddTEID(0)
+	peerConn.WriteTo(request.Encode(), transport.LocalAddr())
+
+	incoming, err := transport.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("[TestTransportContextResponse] expected no error on Receive, got (%s)", err)
+	}
+
+	acknowledge := NewPDU(ContextAcknowledge, request.SequenceNumber, []*IE{NewIEWithRawData(Cause, []byte{16, 0})}).AddTEID(0x70)
+
+	go func() {
+		if pdu, from := readPDUFromConn(t, peerConn, time.Second); pdu != nil && pdu.Type == ContextResponse {
+			// a retransmission of the Context Request is answered with the
+			// Context Response rather than taken as the answer to it
+			peerConn.WriteTo(request.Encode(), from)
+			if pdu, _ := readPDUFromConn(t, peerConn, time.Second); pdu != nil && pdu.Type == ContextResponse {
+				peerConn.WriteTo(acknowledge.Encode(), from)
+			}
+		}
+	}()
+
+	response := NewPDU(ContextResponse, incoming.PDU.SequenceNumber, []*IE{NewIEWithRawData(Cause, []byte{16, 0})}).AddTEID(0x80)
+
+	answer, err := transport.SendRequest(context.Background(), incoming.Peer, response)
+	if err != nil {
+		t.Fatalf("[TestTransportContextResponse] expected no error on SendRequest, got (%s)", err)
+	}
+
+	if answer.PDU.Type != ContextAcknowledge || answer.PDU.SequenceNumber != request.SequenceNumber {
+		t.Errorf("[TestTransportContextResponse] expected Context Acknowledge as answer, got (%s)", NameOfMessageForType(answer.PDU.Type))
+	}
+}
+
+func TestTransportClose(t *testing.T) {
+	transport, _ := newLoopbackTransportAndPeer(t, TransportConfig{})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		transport.Close()
+	}()
+
+	if _, err := transport.Receive(context.Background()); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("[TestTransportClose] expected net.ErrClosed, got (%v)", err)
+	}
+
+	if err := transport.SendResponse(transport.LocalAddr(), NewPDU(EchoResponse, 1, []*IE{})); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("[TestTransportClose] expected net.ErrClosed, got (%v)", err)
+	}
+}
@@ -0,0 +1,588 @@
+package gtpv2
+
+import (
+	"fmt"
+)
+
+// IEPresence is the presence requirement of an IE in a message, from the
+// message tables in TS 29.274 chapter 7
+type IEPresence uint8
+
+// IE presence requirements
+const (
+	IEPresenceMandatory IEPresence = iota + 1
+	IEPresenceConditional
+	IEPresenceConditionalOptional
+	IEPresenceOptional
+)
+
+var iePresenceNames = []string{"", "M", "C", "CO", "O"}
+
+// String returns the abbreviation of the presence requirement used in
+// TS 29.274 (e.g., "CO")
+func (presence IEPresence) String() string {
+	if presence > 0 && int(presence) < len(iePresenceNames) {
+		return iePresenceNames[presence]
+	}
+
+	return fmt.Sprintf("IEPresence(%d)", uint8(presence))
+}
+
+// ValidationMode controls which violations PDU.Validate reports
+type ValidationMode uint8
+
+// Validation modes.  ValidationLenient reports only the problems that a
+// receiver must reject a message for (TS 29.274 section 7.7): missing or
+// incorrect mandatory IEs.  ValidationStrict also reports incorrect
+// non-mandatory IEs, IEs that the message may not carry, and repeated IEs
+// that may appear only once.
+const (
+	ValidationLenient ValidationMode = iota
+	ValidationStrict
+)
+
+// ViolationType is the type of a Violation
+type ViolationType uint8
+
+// Violation types
+const (
+	ViolationMissingMandatoryIE ViolationType = iota + 1
+	ViolationIncorrectIE
+	ViolationUnexpectedIE
+	ViolationDuplicateIE
+)
+
+var violationTypeNames = []string{"", "missing mandatory IE", "incorrect IE", "unexpected IE", "duplicate IE"}
+
+// String returns a description of the violation type (e.g., "unexpected IE")
+func (violationType ViolationType) String() string {
+	if violationType > 0 && int(violationType) < len(violationTypeNames) {
+		return violationTypeNames[violationType]
+	}
+
+	return fmt.Sprintf("ViolationType(%d)", uint8(violationType))
+}
+
+// Violation is a problem found by PDU.Validate.  IE is the offending IE, and
+// is nil for ViolationMissingMandatoryIE.  Presence is the presence
+// requirement of the IE in the message, and is 0 for ViolationUnexpectedIE.
+// Err is the reason that the IE is incorrect, and is set only for
+// ViolationIncorrectIE.
+type Violation struct {
+	Type     ViolationType
+	IEType   IEType
+	Instance uint8
+	Presence IEPresence
+	IE       *IE
+	Err      error
+}
+
+// String returns a description of the violation
+func (violation *Violation) String() string {
+	if violation.Err != nil {
+		return fmt.Sprintf("%s (%s) with instance (%d): %s", violation.Type, NameOfIEForType(violation.IEType), violation.Instance, violation.Err)
+	}
+
+	return fmt.Sprintf("%s (%s) with instance (%d)", violation.Type, NameOfIEForType(violation.IEType), violation.Instance)
+}
+
+type messageIERule struct {
+	ieType     IEType
+	instance   uint8
+	presence   IEPresence
+	repeatable bool
+}
+
+type ieTypeAndInstance struct {
+	ieType   IEType
+	instance uint8
+}
+
+// messageIERules provides, for each message type that can be validated, the
+// IEs that the message may carry (TS 29.274 Tables 7.1.1-1, 7.1.2-1, 7.2.1-1,
+// 7.2.2-1, 7.2.3-1, 7.2.4-1, 7.2.7-1, 7.2.8-1, 7.2.9.1-1, 7.2.9.2-1,
+// 7.2.10.1-1, 7.2.10.2-1, 7.2.15-1, 7.2.16-1, 7.2.21-1 and 7.2.22-1, as of
+// Release 16).  IEs added in later releases are reported as unexpected by
+// ValidationStrict.  The Private Extension IE may appear in any message, so
+// it is not listed.
+var messageIERules = map[MessageType][]messageIERule{
+	EchoRequest: {
+		{RecoveryRestartCounter, 0, IEPresenceMandatory, false},
+		{NodeFeatures, 0, IEPresenceConditionalOptional, false},
+	},
+	EchoResponse: {
+		{RecoveryRestartCounter, 0, IEPresenceMandatory, false},
+		{NodeFeatures, 0, IEPresenceConditionalOptional, false},
+	},
+	CreateSessionRequest: {
+		{IMSI, 0, IEPresenceConditional, false},
+		{MSISDN, 0, IEPresenceConditional, false},
+		{MEI, 0, IEPresenceConditional, false},
+		{ULI, 0, IEPresenceConditional, false},
+		{ServingNetwork, 0, IEPresenceConditional, false},
+		{RATType, 0, IEPresenceMandatory, false},
+		{Indication, 0, IEPresenceConditional, false},
+		{FTEID, 0, IEPresenceMandatory, false},
+		{FTEID, 1, IEPresenceConditional, false},
+		{APN, 0, IEPresenceMandatory, false},
+		{SelectionMode, 0, IEPresenceConditional, false},
+		{PDNType, 0, IEPresenceConditional, false},
+		{PAA, 0, IEPresenceConditional, false},
+		{APNRestriction, 0, IEPresenceConditional, false},
+		{AMBR, 0, IEPresenceConditional, false},
+		{EBI, 0, IEPresenceConditional, false},
+		{TrustedWLANModeIndication, 0, IEPresenceConditionalOptional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditional, false},
+		{BearerContext, 0, IEPresenceMandatory, true},
+		{BearerContext, 1, IEPresenceConditional, true},
+		{TraceInformation, 0, IEPresenceConditional, false},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{FQCSID, 2, IEPresenceConditionalOptional, false},
+		{FQCSID, 3, IEPresenceConditionalOptional, false},
+		{UETimeZone, 0, IEPresenceConditionalOptional, false},
+		{UserCSGInformation, 0, IEPresenceConditionalOptional, false},
+		{ChargingCharacteristics, 0, IEPresenceConditional, false},
+		{LDN, 0, IEPresenceOptional, false},
+		{LDN, 1, IEPresenceOptional, false},
+		{LDN, 2, IEPresenceOptional, false},
+		{LDN, 3, IEPresenceOptional, false},
+		{SignallingPriorityIndication, 0, IEPresenceConditionalOptional, false},
+		{IPAddress, 0, IEPresenceConditionalOptional, false},
+		{IPAddress, 1, IEPresenceConditionalOptional, false},
+		{PortNumber, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 1, IEPresenceConditionalOptional, false},
+		{APCO, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifier, 0, IEPresenceConditionalOptional, false},
+		{CNOperatorSelectionEntity, 0, IEPresenceConditionalOptional, false},
+		{PresenceReportingAreaInformation, 0, IEPresenceConditionalOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{OverloadControlInformation, 2, IEPresenceOptional, false},
+		{MillisecondTimeStamp, 0, IEPresenceConditionalOptional, false},
+		{IntegerNumber, 0, IEPresenceConditionalOptional, false},
+		{RemoteUEContext, 0, IEPresenceConditionalOptional, true},
+		{ePCO, 0, IEPresenceConditionalOptional, false},
+		{ServingPLMNRateControl, 0, IEPresenceConditionalOptional, false},
+		{Counter, 0, IEPresenceConditionalOptional, false},
+		{MappedUEUsageType, 0, IEPresenceConditionalOptional, false},
+		{FQDN, 0, IEPresenceConditionalOptional, false},
+		{SecondaryRATUsageDataReport, 0, IEPresenceConditionalOptional, true},
+		{UPFunctionSelectionIndicationFlags, 0, IEPresenceConditionalOptional, false},
+		{IPAddress, 2, IEPresenceConditionalOptional, false},
+		{IPAddress, 3, IEPresenceConditionalOptional, false},
+		{TWANIdentifier, 1, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 0, IEPresenceConditionalOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{NodeIdentifier, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 2, IEPresenceConditionalOptional, false},
+		{ULI, 1, IEPresenceConditionalOptional, false},
+		{APNRateControlStatus, 0, IEPresenceConditionalOptional, false},
+	},
+	CreateSessionResponse: {
+		{Cause, 0, IEPresenceMandatory, false},
+		{ChangeReportingAction, 0, IEPresenceConditional, false},
+		{CSGInformationReportingAction, 0, IEPresenceConditionalOptional, false},
+		{HeNBInformationReporting, 0, IEPresenceConditionalOptional, false},
+		{FTEID, 0, IEPresenceConditional, false},
+		{FTEID, 1, IEPresenceConditional, false},
+		{PAA, 0, IEPresenceConditional, false},
+		{APNRestriction, 0, IEPresenceConditional, false},
+		{AMBR, 0, IEPresenceConditional, false},
+		{EBI, 0, IEPresenceConditional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditional, false},
+		{BearerContext, 0, IEPresenceMandatory, true},
+		{BearerContext, 1, IEPresenceConditional, true},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{FQDN, 0, IEPresenceConditional, false},
+		{IPAddress, 0, IEPresenceConditional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{LDN, 0, IEPresenceOptional, false},
+		{LDN, 1, IEPresenceOptional, false},
+		{EPCTimer, 0, IEPresenceConditionalOptional, false},
+		{APCO, 0, IEPresenceConditionalOptional, false},
+		{IPv4ConfigurationParameters, 0, IEPresenceConditionalOptional, false},
+		{Indication, 0, IEPresenceConditionalOptional, false},
+		{PresenceReportingAreaAction, 0, IEPresenceConditionalOptional, true},
+		{LoadControlInformation, 0, IEPresenceOptional, false},
+		{LoadControlInformation, 1, IEPresenceOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{ChargingID, 0, IEPresenceConditionalOptional, false},
+		{ePCO, 0, IEPresenceConditionalOptional, false},
+		{FQDN, 1, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 2, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+	},
+	CreateBearerRequest: {
+		{ProcedureTransactionID, 0, IEPresenceConditional, false},
+		{EBI, 0, IEPresenceMandatory, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceOptional, false},
+		{BearerContext, 0, IEPresenceMandatory, true},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{ChangeReportingAction, 0, IEPresenceConditional, false},
+		{CSGInformationReportingAction, 0, IEPresenceConditionalOptional, false},
+		{HeNBInformationReporting, 0, IEPresenceConditionalOptional, false},
+		{PresenceReportingAreaAction, 0, IEPresenceConditionalOptional, true},
+		{Indication, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 0, IEPresenceOptional, false},
+		{LoadControlInformation, 1, IEPresenceOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 2, IEPresenceOptional, false},
+	},
+	CreateBearerResponse: {
+		{Cause, 0, IEPresenceMandatory, false},
+		{BearerContext, 0, IEPresenceMandatory, true},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{FQCSID, 2, IEPresenceConditionalOptional, false},
+		{FQCSID, 3, IEPresenceConditionalOptional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditionalOptional, false},
+		{UETimeZone, 0, IEPresenceConditionalOptional, false},
+		{ULI, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifier, 0, IEPresenceConditionalOptional, false},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{PresenceReportingAreaInformation, 0, IEPresenceConditionalOptional, true},
+		{IPAddress, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 1, IEPresenceConditionalOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{OverloadControlInformation, 2, IEPresenceOptional, false},
+		{TWANIdentifier, 1, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 1, IEPresenceConditionalOptional, false},
+		{IPAddress, 1, IEPresenceConditionalOptional, false},
+	},
+	ModifyBearerRequest: {
+		{MEI, 0, IEPresenceConditional, false},
+		{ULI, 0, IEPresenceConditional, false},
+		{ServingNetwork, 0, IEPresenceConditionalOptional, false},
+		{RATType, 0, IEPresenceConditional, false},
+		{Indication, 0, IEPresenceConditional, false},
+		{FTEID, 0, IEPresenceConditional, false},
+		{AMBR, 0, IEPresenceConditional, false},
+		{DelayValue, 0, IEPresenceConditional, false},
+		{BearerContext, 0, IEPresenceConditional, true},
+		{BearerContext, 1, IEPresenceConditional, true},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{UETimeZone, 0, IEPresenceConditionalOptional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{UserCSGInformation, 0, IEPresenceConditionalOptional, false},
+		{IPAddress, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 0, IEPresenceConditionalOptional, false},
+		{LDN, 0, IEPresenceOptional, false},
+		{LDN, 1, IEPresenceOptional, false},
+		{IPAddress, 1, IEPresenceConditionalOptional, false},
+		{PortNumber, 1, IEPresenceConditionalOptional, false},
+		{CNOperatorSelectionEntity, 0, IEPresenceConditionalOptional, false},
+		{PresenceReportingAreaInformation, 0, IEPresenceConditionalOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{OverloadControlInformation, 2, IEPresenceOptional, false},
+		{ServingPLMNRateControl, 0, IEPresenceConditionalOptional, false},
+		{Counter, 0, IEPresenceConditionalOptional, false},
+		{SecondaryRATUsageDataReport, 0, IEPresenceConditionalOptional, true},
+		{IPAddress, 2, IEPresenceConditionalOptional, false},
+		{IMSI, 0, IEPresenceConditionalOptional, false},
+		{ULI, 1, IEPresenceConditionalOptional, false},
+		{TWANIdentifier, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 0, IEPresenceConditionalOptional, false},
+	},
+	ModifyBearerResponse: {
+		{Cause, 0, IEPresenceMandatory, false},
+		{MSISDN, 0, IEPresenceConditional, false},
+		{EBI, 0, IEPresenceConditional, false},
+		{APNRestriction, 0, IEPresenceConditional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditional, false},
+		{BearerContext, 0, IEPresenceConditional, true},
+		{BearerContext, 1, IEPresenceConditional, true},
+		{ChangeReportingAction, 0, IEPresenceConditional, false},
+		{CSGInformationReportingAction, 0, IEPresenceConditionalOptional, false},
+		{HeNBInformationReporting, 0, IEPresenceConditionalOptional, false},
+		{FQDN, 0, IEPresenceConditional, false},
+		{IPAddress, 0, IEPresenceConditional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{LDN, 0, IEPresenceOptional, false},
+		{LDN, 1, IEPresenceOptional, false},
+		{Indication, 0, IEPresenceConditionalOptional, false},
+		{PresenceReportingAreaAction, 0, IEPresenceConditionalOptional, true},
+		{LoadControlInformation, 0, IEPresenceOptional, false},
+		{LoadControlInformation, 1, IEPresenceOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{ChargingID, 0, IEPresenceConditionalOptional, false},
+		{APCO, 0, IEPresenceConditionalOptional, false},
+		{ePCO, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 2, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+	},
+	DeleteSessionRequest: {
+		{Cause, 0, IEPresenceConditional, false},
+		{EBI, 0, IEPresenceConditional, false},
+		{ULI, 0, IEPresenceConditional, false},
+		{Indication, 0, IEPresenceConditional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditional, false},
+		{NodeType, 0, IEPresenceConditional, false},
+		{FTEID, 0, IEPresenceOptional, false},
+		{UETimeZone, 0, IEPresenceConditionalOptional, false},
+		{ULITimestamp, 0, IEPresenceConditionalOptional, false},
+		{RANNASCause, 0, IEPresenceConditionalOptional, true},
+		{TWANIdentifier, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 0, IEPresenceConditionalOptional, false},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{TWANIdentifier, 1, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 1, IEPresenceConditionalOptional, false},
+		{IPAddress, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 1, IEPresenceConditionalOptional, false},
+		{ePCO, 0, IEPresenceConditionalOptional, false},
+		{SecondaryRATUsageDataReport, 0, IEPresenceConditionalOptional, true},
+		{OverloadControlInformation, 2, IEPresenceOptional, false},
+	},
+	DeleteSessionResponse: {
+		{Cause, 0, IEPresenceMandatory, false},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditional, false},
+		{Indication, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 0, IEPresenceOptional, false},
+		{LoadControlInformation, 1, IEPresenceOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{APCO, 0, IEPresenceConditionalOptional, false},
+		{ePCO, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 2, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+	},
+	DeleteBearerRequest: {
+		{EBI, 0, IEPresenceConditional, false},
+		{EBI, 1, IEPresenceConditional, true},
+		{BearerContext, 0, IEPresenceOptional, true},
+		{ProcedureTransactionID, 0, IEPresenceConditional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{Cause, 0, IEPresenceConditional, false},
+		{Indication, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 0, IEPresenceOptional, false},
+		{LoadControlInformation, 1, IEPresenceOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{ePCO, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 2, IEPresenceOptional, false},
+		{APNRateControlStatus, 0, IEPresenceConditionalOptional, false},
+	},
+	DeleteBearerResponse: {
+		{Cause, 0, IEPresenceMandatory, false},
+		{EBI, 0, IEPresenceConditional, false},
+		{BearerContext, 0, IEPresenceConditional, true},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{FQCSID, 2, IEPresenceConditionalOptional, false},
+		{FQCSID, 3, IEPresenceConditionalOptional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditional, false},
+		{UETimeZone, 0, IEPresenceConditionalOptional, false},
+		{ULI, 0, IEPresenceConditionalOptional, false},
+		{ULITimestamp, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifier, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 0, IEPresenceConditionalOptional, false},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{IPAddress, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 1, IEPresenceConditionalOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{SecondaryRATUsageDataReport, 0, IEPresenceConditionalOptional, true},
+		{ePCO, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifier, 1, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 1, IEPresenceConditionalOptional, false},
+		{IPAddress, 1, IEPresenceConditionalOptional, false},
+	},
+	UpdateBearerRequest: {
+		{BearerContext, 0, IEPresenceMandatory, true},
+		{ProcedureTransactionID, 0, IEPresenceConditional, false},
+		{ProtocolConfigurationOptions, 0, IEPresenceOptional, false},
+		{AMBR, 0, IEPresenceMandatory, false},
+		{ChangeReportingAction, 0, IEPresenceConditional, false},
+		{CSGInformationReportingAction, 0, IEPresenceConditionalOptional, false},
+		{Indication, 0, IEPresenceConditional, false},
+		{HeNBInformationReporting, 0, IEPresenceConditionalOptional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{PresenceReportingAreaAction, 0, IEPresenceConditionalOptional, true},
+		{LoadControlInformation, 0, IEPresenceOptional, false},
+		{LoadControlInformation, 1, IEPresenceOptional, true},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 2, IEPresenceOptional, false},
+	},
+	UpdateBearerResponse: {
+		{Cause, 0, IEPresenceMandatory, false},
+		{BearerContext, 0, IEPresenceMandatory, true},
+		{ProtocolConfigurationOptions, 0, IEPresenceConditionalOptional, false},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{FQCSID, 0, IEPresenceConditional, false},
+		{FQCSID, 1, IEPresenceConditional, false},
+		{FQCSID, 2, IEPresenceConditionalOptional, false},
+		{FQCSID, 3, IEPresenceConditionalOptional, false},
+		{Indication, 0, IEPresenceConditional, false},
+		{UETimeZone, 0, IEPresenceConditionalOptional, false},
+		{ULI, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifier, 0, IEPresenceConditionalOptional, false},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 1, IEPresenceOptional, false},
+		{PresenceReportingAreaInformation, 0, IEPresenceConditionalOptional, true},
+		{IPAddress, 0, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 0, IEPresenceConditionalOptional, false},
+		{PortNumber, 1, IEPresenceConditionalOptional, false},
+		{FContainer, 0, IEPresenceConditionalOptional, false},
+		{OverloadControlInformation, 2, IEPresenceOptional, false},
+		{TWANIdentifier, 1, IEPresenceConditionalOptional, false},
+		{TWANIdentifierTimestamp, 1, IEPresenceConditionalOptional, false},
+		{IPAddress, 1, IEPresenceConditionalOptional, false},
+	},
+	ReleaseAccessBearersRequest: {
+		{EBI, 0, IEPresenceConditional, true},
+		{NodeType, 0, IEPresenceConditionalOptional, false},
+		{Indication, 0, IEPresenceConditionalOptional, false},
+		{SecondaryRATUsageDataReport, 0, IEPresenceConditionalOptional, true},
+	},
+	ReleaseAccessBearersResponse: {
+		{Cause, 0, IEPresenceMandatory, false},
+		{RecoveryRestartCounter, 0, IEPresenceConditional, false},
+		{Indication, 0, IEPresenceConditionalOptional, false},
+		{LoadControlInformation, 0, IEPresenceOptional, false},
+		{OverloadControlInformation, 0, IEPresenceOptional, false},
+	},
+}
+
+// minimumIEDataLengths provides the smallest valid data length for IE types
+// that have no typed representation but carry a fixed-size value
+var minimumIEDataLengths = map[IEType]int{
+	RecoveryRestartCounter:  1,
+	EBI:                     1,
+	RATType:                 1,
+	DelayValue:              1,
+	ChargingID:              4,
+	ChargingCharacteristics: 2,
+	BearerFlags:             1,
+	ProcedureTransactionID:  1,
+	UETimeZone:              2,
+	APNRestriction:          1,
+	SelectionMode:           1,
+	NodeType:                1,
+}
+
+// CanValidate returns true if PDU.Validate has an IE table for the message type
+func CanValidate(messageType MessageType) bool {
+	_, tableExists := messageIERules[messageType]
+	return tableExists
+}
+
+// IEPresenceIn returns the presence requirement of an IE type and instance in
+// a message type.  Returns false if the message type has no IE table (see
+// CanValidate) or if the message may not carry the IE.
+func IEPresenceIn(messageType MessageType, ieType IEType, instance uint8) (IEPresence, bool) {
+	for _, rule := range messageIERules[messageType] {
+		if rule.ieType == ieType && rule.instance == instance {
+			return rule.presence, true
+		}
+	}
+
+	return 0, false
+}
+
+// Validate checks the IEs in the PDU against the IE table for the PDU message
+// type, and returns the violations that are found (see ValidationMode for the
+// violations reported in each mode).  An IE is incorrect if it is too short
+// for its type or, for IE types that have a typed representation, if it
+// cannot be converted to that representation.  Conditional IEs are not
+// required, because their conditions depend on state outside of the message.
+// Returns nil if there are no violations, or if the message type has no IE
+// table (see CanValidate).
+func (pdu *PDU) Validate(mode ValidationMode) []*Violation {
+	rules, tableExists := messageIERules[pdu.Type]
+	if !tableExists {
+		return nil
+	}
+
+	rulesByIE := make(map[ieTypeAndInstance]messageIERule)
+	for _, rule := range rules {
+		rulesByIE[ieTypeAndInstance{rule.ieType, rule.instance}] = rule
+	}
+
+	var violations []*Violation
+	occurrences := make(map[ieTypeAndInstance]int)
+
+	for _, ie := range pdu.InformationElements {
+		if ie.Type == PrivateExtension {
+			continue
+		}
+
+		key := ieTypeAndInstance{ie.Type, ie.InstanceNumber}
+		occurrences[key]++
+
+		rule, ruleExists := rulesByIE[key]
+		if !ruleExists {
+			if mode == ValidationStrict {
+				violations = append(violations, &Violation{Type: ViolationUnexpectedIE, IEType: ie.Type, Instance: ie.InstanceNumber, IE: ie})
+			}
+			continue
+		}
+
+		if occurrences[key] > 1 && !rule.repeatable {
+			if mode == ValidationStrict {
+				violations = append(violations, &Violation{Type: ViolationDuplicateIE, IEType: ie.Type, Instance: ie.InstanceNumber, Presence: rule.presence, IE: ie})
+			}
+			continue
+		}
+
+		if rule.presence != IEPresenceMandatory && mode != ValidationStrict {
+			continue
+		}
+
+		if err := checkIEData(ie, pdu.Type); err != nil {
+			violations = append(violations, &Violation{Type: ViolationIncorrectIE, IEType: ie.Type, Instance: ie.InstanceNumber, Presence: rule.presence, IE: ie, Err: err})
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.presence == IEPresenceMandatory && occurrences[ieTypeAndInstance{rule.ieType, rule.instance}] == 0 {
+			violations = append(violations, &Violation{Type: ViolationMissingMandatoryIE, IEType: rule.ieType, Instance: rule.instance, Presence: rule.presence})
+		}
+	}
+
+	return violations
+}
+
+func checkIEData(ie *IE, inMessageType MessageType) error {
+	if minimumLength, lengthIsFixed := minimumIEDataLengths[ie.Type]; lengthIsFixed {
+		if len(ie.Data) < minimumLength {
+			return fmt.Errorf("IE data length (%d) is less than minimum (%d)", len(ie.Data), minimumLength)
+		}
+		return nil
+	}
+
+	if ie.Type == BearerContext {
+		_, err := DecodeBearerContext(ie, inMessageType)
+		return err
+	}
+
+	if _, err := ie.TypedDataErrorable(); err != nil && err != errNoTypeConversion {
+		return err
+	}
+
+	return nil
+}
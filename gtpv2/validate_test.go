@@ -0,0 +1,144 @@
+package gtpv2
+
+import (
+	"net"
+	"testing"
+)
+
+type expectedViolation struct {
+	violationType ViolationType
+	ieType        IEType
+	instance      uint8
+}
+
+func TestPDUValidate(t *testing.T) {
+	request := &TypedCreateSessionRequest{
+		IMSI:                       &TypedIMSI{AsString: "001010123456789"},
+		RATType:                    uint8Pointer(6),
+		SenderFTEIDForControlPlane: &TypedFTEID{InterfaceType: 10, Key: 0x01010101, IPv4Addr: net.IP{10, 0, 0, 1}},
+		APN:                        &TypedAPN{AsString: "internet"},
+	}
+	request.NewBearerContextToBeCreated(5)
+
+	validPDU, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestPDUValidate] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	withIEs := func(messageType MessageType, ies ...*IE) *PDU {
+		return NewPDU(messageType, 1, ies)
+	}
+
+	validIEs := validPDU.InformationElements
+	fteidWithInstance := func(instance uint8) *IE {
+		ie := NewIEWithRawData(FTEID, validIEs[2].Data)
+		ie.InstanceNumber = instance
+		return ie
+	}
+
+	for testIndex, testCase := range []struct {
+		pdu      *PDU
+		mode     ValidationMode
+		expected []expectedViolation
+	}{
+		{validPDU, ValidationLenient, nil},
+		{validPDU, ValidationStrict, nil},
+		{withIEs(CreateSessionRequest, validIEs[0], validIEs[2], validIEs[3], validIEs[4]), ValidationLenient, []expectedViolation{
+			{ViolationMissingMandatoryIE, RATType, 0},
+		}},
+		{withIEs(CreateSessionRequest, validIEs[1], validIEs[2], validIEs[4]), ValidationLenient, []expectedViolation{
+			{ViolationMissingMandatoryIE, APN, 0},
+		}},
+		{withIEs(CreateSessionRequest, validIEs[1], fteidWithInstance(1), validIEs[3], validIEs[4]), ValidationLenient, []expectedViolation{
+			{ViolationMissingMandatoryIE, FTEID, 0},
+		}},
+		{withIEs(CreateSessionRequest, NewIEWithRawData(RATType, []byte{}), validIEs[2], validIEs[3], validIEs[4]), ValidationLenient, []expectedViolation{
+			{ViolationIncorrectIE, RATType, 0},
+		}},
+		{withIEs(CreateSessionRequest, NewIEWithRawData(IMSI, []byte{0x00}), validIEs[1], validIEs[2], validIEs[3], validIEs[4]), ValidationLenient, nil},
+		{withIEs(CreateSessionRequest, NewIEWithRawData(ServingNetwork, []byte{0x00}), validIEs[1], validIEs[2], validIEs[3], validIEs[4]), ValidationStrict, []expectedViolation{
+			{ViolationIncorrectIE, ServingNetwork, 0},
+		}},
+		{withIEs(CreateSessionRequest, validIEs[1], validIEs[1], validIEs[2], validIEs[3], validIEs[4], validIEs[4]), ValidationLenient, nil},
+		{withIEs(CreateSessionRequest, validIEs[1], validIEs[1], validIEs[2], validIEs[3], validIEs[4], validIEs[4]), ValidationStrict, []expectedViolation{
+			{ViolationDuplicateIE, RATType, 0},
+		}},
+		{withIEs(CreateSessionRequest, validIEs[1], validIEs[2], validIEs[3], validIEs[4], NewIEWithRawData(DetachType, []byte{0x01}), NewIEWithRawData(PrivateExtension, []byte{0x00, 0x01})), ValidationLenient, nil},
+		{withIEs(CreateSessionRequest, validIEs[1], validIEs[2], validIEs[3], validIEs[4], NewIEWithRawData(DetachType, []byte{0x01}), NewIEWithRawData(PrivateExtension, []byte{0x00, 0x01})), ValidationStrict, []expectedViolation{
+			{ViolationUnexpectedIE, DetachType, 0},
+		}},
+		{withIEs(EchoRequest), ValidationLenient, []expectedViolation{
+			{ViolationMissingMandatoryIE, RecoveryRestartCounter, 0},
+		}},
+		{withIEs(ForwardRelocationRequest), ValidationStrict, nil},
+	} {
+		violations := testCase.pdu.Validate(testCase.mode)
+
+		if len(violations) != len(testCase.expected) {
+			t.Errorf("[TestPDUValidate] on test number [%d] expected (%d) violations, got (%d): %v", testIndex+1, len(testCase.expected), len(violations), violations)
+			continue
+		}
+
+		for i, violation := range violations {
+			expected := testCase.expected[i]
+			if violation.Type != expected.violationType || violation.IEType != expected.ieType || violation.Instance != expected.instance {
+				t.Errorf("[TestPDUValidate] on test number [%d] expected violation (%s) for IE (%s) with instance (%d), got (%s)", testIndex+1, expected.violationType, NameOfIEForType(expected.ieType), expected.instance, violation)
+			}
+		}
+	}
+
+	if presence, isAllowed := IEPresenceIn(CreateSessionRequest, FTEID, 1); !isAllowed || presence != IEPresenceConditional {
+		t.Errorf("[TestPDUValidate] expected F-TEID with instance (1) in Create Session Request to be conditional")
+	}
+
+	if _, isAllowed := IEPresenceIn(CreateSessionRequest, DetachType, 0); isAllowed {
+		t.Errorf("[TestPDUValidate] expected Detach Type to not be allowed in Create Session Request")
+	}
+}
+
+func TestPDUValidateStrictCreateSessionRequest(t *testing.T) {
+	request := &TypedCreateSessionRequest{
+		IMSI:                       &TypedIMSI{AsString: "001010123456789"},
+		RATType:                    uint8Pointer(6),
+		SenderFTEIDForControlPlane: &TypedFTEID{InterfaceType: 10, Key: 0x01010101, IPv4Addr: net.IP{10, 0, 0, 1}},
+		APN:                        &TypedAPN{AsString: "internet"},
+	}
+	request.NewBearerContextToBeCreated(5)
+
+	pdu, err := request.Marshal()
+	if err != nil {
+		t.Fatalf("[TestPDUValidateStrictCreateSessionRequest] expected no error on Marshal, got error = (%s)", err.Error())
+	}
+
+	withInstance := func(ie *IE, instance uint8) *IE {
+		ie.InstanceNumber = instance
+		return ie
+	}
+
+	// MME/S4-SGSN Identifier, ePDG IP Address, UE TCP Port and a second PRA
+	// Information are carried by a CSR from an MME or S4-SGSN
+	pdu.InformationElements = append(pdu.InformationElements,
+		withInstance(NewIEWithRawData(IPAddress, []byte{10, 0, 0, 2}), 2),
+		withInstance(NewIEWithRawData(IPAddress, []byte{10, 0, 0, 3}), 3),
+		withInstance(NewIEWithRawData(PortNumber, []byte{0x08, 0x68}), 2),
+		NewIEWithRawData(APNRateControlStatus, []byte{0x00, 0x00, 0x00, 0x01}),
+		NewIEWithRawData(PresenceReportingAreaInformation, []byte{0x00, 0x00, 0x00, 0x01}),
+		NewIEWithRawData(PresenceReportingAreaInformation, []byte{0x00, 0x00, 0x00, 0x02}),
+	)
+
+	if violations := pdu.Validate(ValidationStrict); len(violations) != 0 {
+		t.Errorf("[TestPDUValidateStrictCreateSessionRequest] expected no violations, got (%d): %v", len(violations), violations)
+	}
+
+	for _, ieTypeAndInstance := range []struct {
+		ieType   IEType
+		instance uint8
+	}{
+		{IPAddress, 2}, {IPAddress, 3}, {ULI, 1}, {TWANIdentifier, 1}, {TWANIdentifierTimestamp, 0},
+		{FContainer, 0}, {NodeIdentifier, 0}, {PortNumber, 2}, {APNRateControlStatus, 0},
+	} {
+		if _, isAllowed := IEPresenceIn(CreateSessionRequest, ieTypeAndInstance.ieType, ieTypeAndInstance.instance); !isAllowed {
+			t.Errorf("[TestPDUValidateStrictCreateSessionRequest] expected IE (%s) with instance (%d) to be allowed in Create Session Request", NameOfIEForType(ieTypeAndInstance.ieType), ieTypeAndInstance.instance)
+		}
+	}
+}
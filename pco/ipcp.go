@@ -0,0 +1,158 @@
+package pco
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// IPCP packet codes (RFC 1332)
+const (
+	IPCPConfigureRequest = 1
+	IPCPConfigureAck     = 2
+	IPCPConfigureNak     = 3
+	IPCPConfigureReject  = 4
+)
+
+// IPCP option types (RFC 1332 and RFC 1877)
+const (
+	IPCPOptionIPAddress           = 3
+	IPCPOptionPrimaryDNSServer    = 0x81
+	IPCPOptionPrimaryNBNSServer   = 0x82
+	IPCPOptionSecondaryDNSServer  = 0x83
+	IPCPOptionSecondaryNBNSServer = 0x84
+)
+
+// IPCPOption is a single option in an IPCPPacket.  Data excludes the
+// option type and length octets.
+type IPCPOption struct {
+	Type uint8
+	Data []byte
+}
+
+// IPCPPacket is an IPCP packet, as carried in the contents of an IDIPCP
+// Entry.  UEs commonly request DNS server addresses by sending a
+// Configure-Request with IPCPOptionPrimaryDNSServer and
+// IPCPOptionSecondaryDNSServer options set to 0.0.0.0, and the network
+// supplies the addresses in a Configure-Nak with the same Identifier.
+type IPCPPacket struct {
+	Code       uint8
+	Identifier uint8
+	Options    []*IPCPOption
+}
+
+// NewIPCPDNSServerNak returns an IPCP Configure-Nak supplying the primary
+// and (if it is not nil) secondary DNS server addresses.  identifier should
+// match the Identifier of the Configure-Request being answered.  Returns an
+// error if either address is not IPv4.
+func NewIPCPDNSServerNak(identifier uint8, primary net.IP, secondary net.IP) (*IPCPPacket, error) {
+	packet := &IPCPPacket{Code: IPCPConfigureNak, Identifier: identifier, Options: make([]*IPCPOption, 0, 2)}
+
+	for _, server := range []struct {
+		optionType uint8
+		address    net.IP
+	}{
+		{IPCPOptionPrimaryDNSServer, primary},
+		{IPCPOptionSecondaryDNSServer, secondary},
+	} {
+		if server.address == nil {
+			continue
+		}
+
+		asIPv4 := server.address.To4()
+		if asIPv4 == nil {
+			return nil, fmt.Errorf("IPCP DNS server address (%s) is not IPv4", server.address)
+		}
+
+		packet.Options = append(packet.Options, &IPCPOption{Type: server.optionType, Data: []byte(asIPv4)})
+	}
+
+	return packet, nil
+}
+
+// Option returns the first option of the provided type, or nil if there is
+// none
+func (packet *IPCPPacket) Option(optionType uint8) *IPCPOption {
+	for _, option := range packet.Options {
+		if option.Type == optionType {
+			return option
+		}
+	}
+
+	return nil
+}
+
+// RequestsDNSServers returns true if the packet is a Configure-Request that
+// includes either the primary or secondary DNS server option
+func (packet *IPCPPacket) RequestsDNSServers() bool {
+	return packet.Code == IPCPConfigureRequest &&
+		(packet.Option(IPCPOptionPrimaryDNSServer) != nil || packet.Option(IPCPOptionSecondaryDNSServer) != nil)
+}
+
+// Encode converts the packet to its wire encoding.  Returns an error if the
+// data for an option or the packet as a whole is too long.
+func (packet *IPCPPacket) Encode() ([]byte, error) {
+	encoded := []byte{packet.Code, packet.Identifier, 0, 0}
+
+	for _, option := range packet.Options {
+		if len(option.Data) > 0xff-2 {
+			return nil, fmt.Errorf("IPCP option (%d) data length (%d) exceeds maximum (253)", option.Type, len(option.Data))
+		}
+
+		encoded = append(encoded, option.Type, uint8(len(option.Data)+2))
+		encoded = append(encoded, option.Data...)
+	}
+
+	if len(encoded) > 0xffff {
+		return nil, fmt.Errorf("IPCP packet length (%d) exceeds maximum (65535)", len(encoded))
+	}
+
+	binary.BigEndian.PutUint16(encoded[2:4], uint16(len(encoded)))
+
+	return encoded, nil
+}
+
+// ToEntry encodes the packet and returns it as an IDIPCP Entry
+func (packet *IPCPPacket) ToEntry() (*Entry, error) {
+	encoded, err := packet.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{ID: IDIPCP, Contents: encoded}, nil
+}
+
+// DecodeIPCP converts the wire encoding of an IPCP packet to an IPCPPacket.
+// Octets beyond the length in the packet header are ignored.
+func DecodeIPCP(encoded []byte) (*IPCPPacket, error) {
+	if len(encoded) < 4 {
+		return nil, fmt.Errorf("insufficient octets for IPCP header")
+	}
+
+	packetLength := int(binary.BigEndian.Uint16(encoded[2:4]))
+	if packetLength < 4 || packetLength > len(encoded) {
+		return nil, fmt.Errorf("IPCP packet length (%d) is invalid for (%d) available octets", packetLength, len(encoded))
+	}
+
+	packet := &IPCPPacket{Code: encoded[0], Identifier: encoded[1], Options: make([]*IPCPOption, 0)}
+
+	remaining := encoded[4:packetLength]
+	for len(remaining) > 0 {
+		if len(remaining) < 2 {
+			return nil, fmt.Errorf("insufficient octets for IPCP option header")
+		}
+
+		optionLength := int(remaining[1])
+		if optionLength < 2 || optionLength > len(remaining) {
+			return nil, fmt.Errorf("IPCP option (%d) length (%d) is invalid for (%d) remaining octets", remaining[0], optionLength, len(remaining))
+		}
+
+		data := make([]byte, optionLength-2)
+		copy(data, remaining[2:optionLength])
+
+		packet.Options = append(packet.Options, &IPCPOption{Type: remaining[0], Data: data})
+		remaining = remaining[optionLength:]
+	}
+
+	return packet, nil
+}
@@ -0,0 +1,67 @@
+package pco_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blorticus-go/gtp/pco"
+	"github.com/go-test/deep"
+)
+
+func TestIPCP(t *testing.T) {
+	request, err := (&pco.Entry{ID: pco.IDIPCP, Contents: ipcpDNSRequestContents}).IPCP()
+	if err != nil {
+		t.Fatalf("[IPCP] expected no error, got = (%s)", err.Error())
+	}
+
+	expectedRequest := &pco.IPCPPacket{
+		Code:       pco.IPCPConfigureRequest,
+		Identifier: 0,
+		Options: []*pco.IPCPOption{
+			{Type: pco.IPCPOptionPrimaryDNSServer, Data: []byte{0, 0, 0, 0}},
+			{Type: pco.IPCPOptionSecondaryDNSServer, Data: []byte{0, 0, 0, 0}},
+		},
+	}
+
+	if diff := deep.Equal(expectedRequest, request); diff != nil {
+		t.Errorf("[IPCP]: %s", diff)
+	}
+
+	if !request.RequestsDNSServers() {
+		t.Errorf("[RequestsDNSServers] expected true, got false")
+	}
+
+	nak, err := pco.NewIPCPDNSServerNak(request.Identifier, net.ParseIP("10.0.0.53"), net.ParseIP("10.0.1.53"))
+	if err != nil {
+		t.Fatalf("[NewIPCPDNSServerNak] expected no error, got = (%s)", err.Error())
+	}
+
+	entry, err := nak.ToEntry()
+	if err != nil {
+		t.Fatalf("[ToEntry] expected no error, got = (%s)", err.Error())
+	}
+
+	expectedContents := []byte{0x03, 0x00, 0x00, 0x10, 0x81, 0x06, 10, 0, 0, 53, 0x83, 0x06, 10, 0, 1, 53}
+	if diff := deep.Equal(expectedContents, entry.Contents); diff != nil {
+		t.Errorf("[ToEntry]: %s", diff)
+	}
+
+	if nak.RequestsDNSServers() {
+		t.Errorf("[RequestsDNSServers] expected false for Configure-Nak, got true")
+	}
+
+	if _, err := pco.NewIPCPDNSServerNak(0, net.ParseIP("2001:db8::53"), nil); err == nil {
+		t.Errorf("[NewIPCPDNSServerNak] expected error for IPv6 address, got none")
+	}
+
+	for _, invalid := range [][]byte{
+		{0x01, 0x00, 0x00},
+		{0x01, 0x00, 0x00, 0x20, 0x81, 0x06, 0x00, 0x00, 0x00, 0x00},
+		{0x01, 0x00, 0x00, 0x06, 0x81, 0x06},
+		{0x01, 0x00, 0x00, 0x05, 0x81},
+	} {
+		if _, err := pco.DecodeIPCP(invalid); err == nil {
+			t.Errorf("[DecodeIPCP] (% x) expected error, got none", invalid)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+// Package pco encodes and decodes the Protocol Configuration Options container
+// (TS 24.008 section 10.5.6.3) and the Extended Protocol Configuration Options
+// container (TS 24.301 section 9.9.4.26).  These are carried in the GTPv2 PCO,
+// APCO and ePCO IEs, and in the GTPv1 Protocol Configuration Options IE.
+//
+// A Container is a configuration protocol identifier followed by a list of
+// Entries.  Each Entry is either a protocol (e.g., IPCP) carrying a packet for
+// that protocol, or a container identified by a container ID (e.g., a DNS
+// server IPv4 address request).  Entries are kept in order and their contents
+// are kept raw, so entries with IDs that this package does not know about are
+// preserved.  Helper methods provide typed access to the contents of
+// well-known entries.
+package pco
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// ConfigurationProtocolPPP is the only defined configuration protocol.  It is
+// used for both PCO and ePCO.
+const ConfigurationProtocolPPP = 0
+
+// ID is a protocol ID or container ID.  The two share a single two-octet
+// space.
+type ID uint16
+
+// Protocol IDs (TS 24.008 Table 10.5.154)
+const (
+	IDLCP  ID = 0xc021
+	IDPAP  ID = 0xc023
+	IDCHAP ID = 0xc223
+	IDIPCP ID = 0x8021
+)
+
+// Container IDs (TS 24.008 Table 10.5.154).  Most container IDs have a
+// different meaning depending on the direction.  In a message from the UE,
+// the entry usually has no contents and is a request for the thing the
+// network sends back using the same ID (e.g., IDDNSServerIPv4Address is a
+// "DNS Server IPv4 Address Request" from the UE, and a "DNS Server IPv4
+// Address" from the network).
+const (
+	IDPCSCFIPv6Address                     ID = 0x0001
+	IDIMCNSubsystemSignalingFlag           ID = 0x0002
+	IDDNSServerIPv6Address                 ID = 0x0003
+	IDPolicyControlRejectionCode           ID = 0x0004
+	IDSelectedBearerControlMode            ID = 0x0005
+	IDDSMIPv6HomeAgentAddress              ID = 0x0007
+	IDDSMIPv6HomeNetworkPrefix             ID = 0x0008
+	IDDSMIPv6IPv4HomeAgentAddress          ID = 0x0009
+	IDIPAddressAllocationViaNASSignalling  ID = 0x000a
+	IDIPv4AddressAllocationViaDHCPv4       ID = 0x000b
+	IDPCSCFIPv4Address                     ID = 0x000c
+	IDDNSServerIPv4Address                 ID = 0x000d
+	IDMSISDN                               ID = 0x000e
+	IDIFOMSupport                          ID = 0x000f
+	IDIPv4LinkMTU                          ID = 0x0010
+	IDLocalAddressInTFTSupport             ID = 0x0011
+	IDPCSCFReselectionSupport              ID = 0x0012
+	IDNBIFOMRequestIndicator               ID = 0x0013
+	IDNBIFOMMode                           ID = 0x0014
+	IDNonIPLinkMTU                         ID = 0x0015
+	IDAPNRateControl                       ID = 0x0016
+	IDThreeGPPPSDataOffUEStatus            ID = 0x0017
+	IDReliableDataServiceRequestIndicator  ID = 0x0018
+	IDAdditionalAPNRateControlForException ID = 0x0019
+	IDPDUSessionID                         ID = 0x001a
+	IDEthernetFrameMaximumPayloadSize      ID = 0x0020
+)
+
+var idNames = map[ID]string{
+	IDLCP:                                  "LCP",
+	IDPAP:                                  "PAP",
+	IDCHAP:                                 "CHAP",
+	IDIPCP:                                 "IPCP",
+	IDPCSCFIPv6Address:                     "P-CSCF IPv6 Address",
+	IDIMCNSubsystemSignalingFlag:           "IM CN Subsystem Signaling Flag",
+	IDDNSServerIPv6Address:                 "DNS Server IPv6 Address",
+	IDPolicyControlRejectionCode:           "Policy Control Rejection Code",
+	IDSelectedBearerControlMode:            "Selected Bearer Control Mode",
+	IDDSMIPv6HomeAgentAddress:              "DSMIPv6 Home Agent Address",
+	IDDSMIPv6HomeNetworkPrefix:             "DSMIPv6 Home Network Prefix",
+	IDDSMIPv6IPv4HomeAgentAddress:          "DSMIPv6 IPv4 Home Agent Address",
+	IDIPAddressAllocationViaNASSignalling:  "IP Address Allocation Via NAS Signalling",
+	IDIPv4AddressAllocationViaDHCPv4:       "IPv4 Address Allocation Via DHCPv4",
+	IDPCSCFIPv4Address:                     "P-CSCF IPv4 Address",
+	IDDNSServerIPv4Address:                 "DNS Server IPv4 Address",
+	IDMSISDN:                               "MSISDN",
+	IDIFOMSupport:                          "IFOM Support",
+	IDIPv4LinkMTU:                          "IPv4 Link MTU",
+	IDLocalAddressInTFTSupport:             "Local Address In TFT Support",
+	IDPCSCFReselectionSupport:              "P-CSCF Re-selection Support",
+	IDNBIFOMRequestIndicator:               "NBIFOM Request Indicator",
+	IDNBIFOMMode:                           "NBIFOM Mode",
+	IDNonIPLinkMTU:                         "Non-IP Link MTU",
+	IDAPNRateControl:                       "APN Rate Control",
+	IDThreeGPPPSDataOffUEStatus:            "3GPP PS Data Off UE Status",
+	IDReliableDataServiceRequestIndicator:  "Reliable Data Service Request Indicator",
+	IDAdditionalAPNRateControlForException: "Additional APN Rate Control For Exception Data",
+	IDPDUSessionID:                         "PDU Session ID",
+	IDEthernetFrameMaximumPayloadSize:      "Ethernet Frame Maximum Payload Size",
+}
+
+// String returns the name of the protocol or container, or its value in
+// hex if the ID is not known
+func (id ID) String() string {
+	if name, isKnown := idNames[id]; isKnown {
+		return name
+	}
+
+	return fmt.Sprintf("0x%04x", uint16(id))
+}
+
+// Entry is a single protocol or container in a Container
+type Entry struct {
+	ID       ID
+	Contents []byte
+}
+
+// NewRequestEntry returns an Entry with no contents.  This is the usual form
+// of a request from the UE (e.g., a DNS Server IPv4 Address Request).
+func NewRequestEntry(id ID) *Entry {
+	return &Entry{ID: id, Contents: []byte{}}
+}
+
+// NewIPAddressEntry returns an Entry for one of the address containers
+// (IDDNSServerIPv4Address, IDDNSServerIPv6Address, IDPCSCFIPv4Address or
+// IDPCSCFIPv6Address).  Returns an error if id is not one of these, or if the
+// address family of ip does not match the container.
+func NewIPAddressEntry(id ID, ip net.IP) (*Entry, error) {
+	addressLength, isAddressContainer := addressLengthForID(id)
+	if !isAddressContainer {
+		return nil, fmt.Errorf("container ID (%s) does not carry an IP address", id)
+	}
+
+	var contents net.IP
+	if addressLength == net.IPv4len {
+		contents = ip.To4()
+	} else if ip.To4() == nil {
+		contents = ip.To16()
+	}
+
+	if contents == nil {
+		return nil, fmt.Errorf("address (%s) is not the correct family for container (%s)", ip, id)
+	}
+
+	return &Entry{ID: id, Contents: []byte(contents)}, nil
+}
+
+// NewIPv4LinkMTUEntry returns an IDIPv4LinkMTU Entry with the provided MTU
+func NewIPv4LinkMTUEntry(mtu uint16) *Entry {
+	return &Entry{ID: IDIPv4LinkMTU, Contents: binary.BigEndian.AppendUint16(nil, mtu)}
+}
+
+func addressLengthForID(id ID) (length int, isAddressContainer bool) {
+	switch id {
+	case IDDNSServerIPv4Address, IDPCSCFIPv4Address:
+		return net.IPv4len, true
+	case IDDNSServerIPv6Address, IDPCSCFIPv6Address:
+		return net.IPv6len, true
+	}
+
+	return 0, false
+}
+
+// IsRequest returns true if the Entry has no contents
+func (entry *Entry) IsRequest() bool {
+	return len(entry.Contents) == 0
+}
+
+// IP returns the address carried by one of the address containers (see
+// NewIPAddressEntry).  Returns an error if the Entry is not an address
+// container, or if the contents are not the correct length for the address.
+func (entry *Entry) IP() (net.IP, error) {
+	addressLength, isAddressContainer := addressLengthForID(entry.ID)
+	if !isAddressContainer {
+		return nil, fmt.Errorf("container ID (%s) does not carry an IP address", entry.ID)
+	}
+
+	if len(entry.Contents) != addressLength {
+		return nil, fmt.Errorf("container (%s) contents length (%d) is not (%d)", entry.ID, len(entry.Contents), addressLength)
+	}
+
+	ip := make(net.IP, addressLength)
+	copy(ip, entry.Contents)
+
+	return ip, nil
+}
+
+// MTU returns the MTU carried by an IDIPv4LinkMTU or IDNonIPLinkMTU Entry.
+// Returns an error if the Entry is not one of these, or if the contents
+// are not two octets long.
+func (entry *Entry) MTU() (uint16, error) {
+	if entry.ID != IDIPv4LinkMTU && entry.ID != IDNonIPLinkMTU {
+		return 0, fmt.Errorf("container ID (%s) does not carry an MTU", entry.ID)
+	}
+
+	if len(entry.Contents) != 2 {
+		return 0, fmt.Errorf("container (%s) contents length (%d) is not (2)", entry.ID, len(entry.Contents))
+	}
+
+	return binary.BigEndian.Uint16(entry.Contents), nil
+}
+
+// IPCP decodes the contents of an IDIPCP Entry.  Returns an error if the
+// Entry is not IPCP, or if the contents cannot be decoded.
+func (entry *Entry) IPCP() (*IPCPPacket, error) {
+	if entry.ID != IDIPCP {
+		return nil, fmt.Errorf("protocol ID (%s) is not IPCP", entry.ID)
+	}
+
+	return DecodeIPCP(entry.Contents)
+}
+
+// Container is a PCO or ePCO container
+type Container struct {
+	ConfigurationProtocol uint8
+	Entries               []*Entry
+}
+
+// NewContainer returns a Container using ConfigurationProtocolPPP, with the
+// provided entries
+func NewContainer(entries ...*Entry) *Container {
+	return &Container{ConfigurationProtocol: ConfigurationProtocolPPP, Entries: entries}
+}
+
+// Add appends entries to the Container
+func (container *Container) Add(entries ...*Entry) {
+	container.Entries = append(container.Entries, entries...)
+}
+
+// EntriesWithID returns each Entry with the provided ID, in order.  The
+// returned slice is empty if there are none.
+func (container *Container) EntriesWithID(id ID) []*Entry {
+	matching := make([]*Entry, 0)
+
+	for _, entry := range container.Entries {
+		if entry.ID == id {
+			matching = append(matching, entry)
+		}
+	}
+
+	return matching
+}
+
+// Contains returns true if the Container has at least one Entry with the
+// provided ID
+func (container *Container) Contains(id ID) bool {
+	return len(container.EntriesWithID(id)) > 0
+}
+
+// Encode converts the Container to its PCO encoding, in which each Entry
+// has a one octet length.  Returns an error if the configuration protocol
+// is more than three bits long, or the contents of an Entry are more than
+// 255 octets long.
+func (container *Container) Encode() ([]byte, error) {
+	return container.encode(1)
+}
+
+// EncodeExtended converts the Container to its ePCO encoding, in which each
+// Entry has a two octet length.
+func (container *Container) EncodeExtended() ([]byte, error) {
+	return container.encode(2)
+}
+
+func (container *Container) encode(lengthFieldSize int) ([]byte, error) {
+	if container.ConfigurationProtocol > 0x07 {
+		return nil, fmt.Errorf("configuration protocol (%d) exceeds maximum 3-bit value", container.ConfigurationProtocol)
+	}
+
+	maximumContentsLength := 0xff
+	if lengthFieldSize == 2 {
+		maximumContentsLength = 0xffff
+	}
+
+	// the extension bit is always set
+	encoded := []byte{0x80 | container.ConfigurationProtocol}
+
+	for _, entry := range container.Entries {
+		if len(entry.Contents) > maximumContentsLength {
+			return nil, fmt.Errorf("contents of (%s) has length (%d), which exceeds maximum (%d)", entry.ID, len(entry.Contents), maximumContentsLength)
+		}
+
+		encoded = binary.BigEndian.AppendUint16(encoded, uint16(entry.ID))
+
+		if lengthFieldSize == 2 {
+			encoded = binary.BigEndian.AppendUint16(encoded, uint16(len(entry.Contents)))
+		} else {
+			encoded = append(encoded, uint8(len(entry.Contents)))
+		}
+
+		encoded = append(encoded, entry.Contents...)
+	}
+
+	return encoded, nil
+}
+
+// Decode converts a PCO encoding to a Container
+func Decode(encoded []byte) (*Container, error) {
+	return decode(encoded, 1)
+}
+
+// DecodeExtended converts an ePCO encoding to a Container
+func DecodeExtended(encoded []byte) (*Container, error) {
+	return decode(encoded, 2)
+}
+
+func decode(encoded []byte, lengthFieldSize int) (*Container, error) {
+	if len(encoded) < 1 {
+		return nil, fmt.Errorf("insufficient octets for configuration protocol")
+	}
+
+	container := &Container{
+		ConfigurationProtocol: encoded[0] & 0x07,
+		Entries:               make([]*Entry, 0),
+	}
+
+	remaining := encoded[1:]
+	for len(remaining) > 0 {
+		if len(remaining) < 2+lengthFieldSize {
+			return nil, fmt.Errorf("insufficient octets for entry header after (%d) entries", len(container.Entries))
+		}
+
+		id := ID(binary.BigEndian.Uint16(remaining[0:2]))
+
+		var contentsLength int
+		if lengthFieldSize == 2 {
+			contentsLength = int(binary.BigEndian.Uint16(remaining[2:4]))
+		} else {
+			contentsLength = int(remaining[2])
+		}
+
+		remaining = remaining[2+lengthFieldSize:]
+
+		if len(remaining) < contentsLength {
+			return nil, fmt.Errorf("entry (%s) has length (%d), but only (%d) octets remain", id, contentsLength, len(remaining))
+		}
+
+		contents := make([]byte, contentsLength)
+		copy(contents, remaining[:contentsLength])
+		remaining = remaining[contentsLength:]
+
+		container.Entries = append(container.Entries, &Entry{ID: id, Contents: contents})
+	}
+
+	return container, nil
+}
@@ -0,0 +1,149 @@
+package pco_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blorticus-go/gtp/pco"
+	"github.com/go-test/deep"
+)
+
+var ipcpDNSRequestContents = []byte{0x01, 0x00, 0x00, 0x10, 0x81, 0x06, 0x00, 0x00, 0x00, 0x00, 0x83, 0x06, 0x00, 0x00, 0x00, 0x00}
+
+func TestEncodeAndDecode(t *testing.T) {
+	container := pco.NewContainer(
+		&pco.Entry{ID: pco.IDIPCP, Contents: ipcpDNSRequestContents},
+		pco.NewRequestEntry(pco.IDDNSServerIPv4Address),
+		pco.NewRequestEntry(pco.IDIPAddressAllocationViaNASSignalling),
+		&pco.Entry{ID: 0xff00, Contents: []byte{0x01, 0x02}},
+	)
+
+	expected := []byte{
+		0x80,
+		0x80, 0x21, 0x10, 0x01, 0x00, 0x00, 0x10, 0x81, 0x06, 0x00, 0x00, 0x00, 0x00, 0x83, 0x06, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x0d, 0x00,
+		0x00, 0x0a, 0x00,
+		0xff, 0x00, 0x02, 0x01, 0x02,
+	}
+
+	encoded, err := container.Encode()
+	if err != nil {
+		t.Fatalf("[Encode] expected no error, got = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(expected, encoded); diff != nil {
+		t.Errorf("[Encode]: %s", diff)
+	}
+
+	decoded, err := pco.Decode(expected)
+	if err != nil {
+		t.Fatalf("[Decode] expected no error, got = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(container, decoded); diff != nil {
+		t.Errorf("[Decode]: %s", diff)
+	}
+
+	if !decoded.Contains(pco.IDDNSServerIPv4Address) || decoded.Contains(pco.IDDNSServerIPv6Address) {
+		t.Errorf("[Decode] Contains() does not match the decoded entries")
+	}
+
+	expectedExtended := []byte{
+		0x80,
+		0x80, 0x21, 0x00, 0x10, 0x01, 0x00, 0x00, 0x10, 0x81, 0x06, 0x00, 0x00, 0x00, 0x00, 0x83, 0x06, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x0d, 0x00, 0x00,
+		0x00, 0x0a, 0x00, 0x00,
+		0xff, 0x00, 0x00, 0x02, 0x01, 0x02,
+	}
+
+	encoded, err = container.EncodeExtended()
+	if err != nil {
+		t.Fatalf("[EncodeExtended] expected no error, got = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(expectedExtended, encoded); diff != nil {
+		t.Errorf("[EncodeExtended]: %s", diff)
+	}
+
+	decoded, err = pco.DecodeExtended(expectedExtended)
+	if err != nil {
+		t.Fatalf("[DecodeExtended] expected no error, got = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal(container, decoded); diff != nil {
+		t.Errorf("[DecodeExtended]: %s", diff)
+	}
+}
+
+func TestDecodeInvalidCases(t *testing.T) {
+	for _, invalid := range [][]byte{
+		{},
+		{0x80, 0x00},
+		{0x80, 0x00, 0x0d},
+		{0x80, 0x00, 0x0d, 0x04, 0x08, 0x08, 0x08},
+	} {
+		if _, err := pco.Decode(invalid); err == nil {
+			t.Errorf("[% x] expected error on Decode, got none", invalid)
+		}
+	}
+
+	if _, err := pco.DecodeExtended([]byte{0x80, 0x00, 0x0d, 0x00}); err == nil {
+		t.Errorf("expected error on DecodeExtended for truncated length, got none")
+	}
+
+	if _, err := (&pco.Container{ConfigurationProtocol: 8}).Encode(); err == nil {
+		t.Errorf("expected error on Encode for configuration protocol 8, got none")
+	}
+
+	if _, err := pco.NewContainer(&pco.Entry{ID: pco.IDMSISDN, Contents: make([]byte, 256)}).Encode(); err == nil {
+		t.Errorf("expected error on Encode for contents longer than 255 octets, got none")
+	}
+}
+
+func TestTypedEntries(t *testing.T) {
+	dnsV4, err := pco.NewIPAddressEntry(pco.IDDNSServerIPv4Address, net.ParseIP("8.8.8.8"))
+	if err != nil {
+		t.Fatalf("[NewIPAddressEntry] expected no error for IPv4 DNS, got = (%s)", err.Error())
+	}
+
+	if diff := deep.Equal([]byte{8, 8, 8, 8}, dnsV4.Contents); diff != nil {
+		t.Errorf("[NewIPAddressEntry] IPv4 DNS contents: %s", diff)
+	}
+
+	if ip, err := dnsV4.IP(); err != nil || !ip.Equal(net.ParseIP("8.8.8.8")) {
+		t.Errorf("[IP] expected (8.8.8.8), got (%s) with error (%v)", ip, err)
+	}
+
+	pcscfV6, err := pco.NewIPAddressEntry(pco.IDPCSCFIPv6Address, net.ParseIP("2001:db8::53"))
+	if err != nil {
+		t.Fatalf("[NewIPAddressEntry] expected no error for IPv6 P-CSCF, got = (%s)", err.Error())
+	}
+
+	if ip, err := pcscfV6.IP(); err != nil || !ip.Equal(net.ParseIP("2001:db8::53")) {
+		t.Errorf("[IP] expected (2001:db8::53), got (%s) with error (%v)", ip, err)
+	}
+
+	if _, err := pco.NewIPAddressEntry(pco.IDDNSServerIPv6Address, net.ParseIP("8.8.8.8")); err == nil {
+		t.Errorf("[NewIPAddressEntry] expected error for IPv4 address in IPv6 container, got none")
+	}
+
+	if _, err := pco.NewIPAddressEntry(pco.IDIPv4LinkMTU, net.ParseIP("8.8.8.8")); err == nil {
+		t.Errorf("[NewIPAddressEntry] expected error for non-address container, got none")
+	}
+
+	if _, err := pco.NewRequestEntry(pco.IDDNSServerIPv4Address).IP(); err == nil {
+		t.Errorf("[IP] expected error for request entry, got none")
+	}
+
+	if mtu, err := pco.NewIPv4LinkMTUEntry(1400).MTU(); err != nil || mtu != 1400 {
+		t.Errorf("[MTU] expected (1400), got (%d) with error (%v)", mtu, err)
+	}
+
+	if _, err := dnsV4.MTU(); err == nil {
+		t.Errorf("[MTU] expected error for DNS entry, got none")
+	}
+
+	if pco.IDIPCP.String() != "IPCP" || pco.ID(0xff00).String() != "0xff00" {
+		t.Errorf("[String] unexpected ID names (%s) and (%s)", pco.IDIPCP, pco.ID(0xff00))
+	}
+}
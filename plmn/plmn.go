@@ -0,0 +1,99 @@
+// Package plmn models a Public Land Mobile Network identity (a Mobile Country
+// Code and Mobile Network Code pair), and provides the three octet encoding of
+// that pair used by the Serving Network, PLMN ID, ULI and similar GTP IEs
+// (TS 24.008 Figure 10.5.13 and TS 29.274 Figure 8.18-1).
+package plmn
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// EncodedLength is the length, in octets, of an encoded PLMN
+const EncodedLength = 3
+
+var matcherForProperMCC = regexp.MustCompile(`^\d{3}$`)
+var matcherForProperMNC = regexp.MustCompile(`^\d{2,3}$`)
+
+// PLMN is a PLMN identity.  MCC is always three decimal digits.  MNC is either
+// two or three decimal digits.  Leading zeroes are significant for the MNC,
+// so "01" and "001" are different MNCs.
+type PLMN struct {
+	MCC string
+	MNC string
+}
+
+// Validate returns an error if the MCC or MNC are not the correct number of
+// decimal digits
+func (p PLMN) Validate() error {
+	if !matcherForProperMCC.MatchString(p.MCC) {
+		return fmt.Errorf("MCC (%s) must be exactly three decimal digits", p.MCC)
+	}
+
+	if !matcherForProperMNC.MatchString(p.MNC) {
+		return fmt.Errorf("MNC (%s) must be two or three decimal digits", p.MNC)
+	}
+
+	return nil
+}
+
+// HasThreeDigitMNC returns true if the MNC is three digits long
+func (p PLMN) HasThreeDigitMNC() bool {
+	return len(p.MNC) == 3
+}
+
+// String returns the PLMN as MCC-MNC (e.g., "310-260")
+func (p PLMN) String() string {
+	return p.MCC + "-" + p.MNC
+}
+
+// Encode converts the PLMN to its three octet encoding.  A two-digit MNC has
+// the MNC digit 3 nybble set to 1111b.  Returns an error if the PLMN is not
+// valid.
+func (p PLMN) Encode() ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	mncDigit3 := byte(0x0f)
+	if p.HasThreeDigitMNC() {
+		mncDigit3 = p.MNC[2] - '0'
+	}
+
+	return []byte{
+		(p.MCC[1]-'0')<<4 | (p.MCC[0] - '0'),
+		mncDigit3<<4 | (p.MCC[2] - '0'),
+		(p.MNC[1]-'0')<<4 | (p.MNC[0] - '0'),
+	}, nil
+}
+
+// Decode is the inverse of Encode.  encoded must be exactly EncodedLength
+// octets long.
+func Decode(encoded []byte) (PLMN, error) {
+	if len(encoded) != EncodedLength {
+		return PLMN{}, fmt.Errorf("encoded PLMN must be (%d) octets, got (%d)", EncodedLength, len(encoded))
+	}
+
+	digits := []byte{
+		encoded[0] & 0x0f, encoded[0] >> 4, encoded[1] & 0x0f,
+		encoded[2] & 0x0f, encoded[2] >> 4, encoded[1] >> 4,
+	}
+
+	for i, digit := range digits[:5] {
+		if digit > 9 {
+			return PLMN{}, fmt.Errorf("invalid MCC/MNC digit value (0x%x) at digit position (%d)", digit, i+1)
+		}
+	}
+
+	if digits[5] == 0x0f {
+		digits = digits[:5]
+	} else if digits[5] > 9 {
+		return PLMN{}, fmt.Errorf("invalid MNC digit 3 value (0x%x)", digits[5])
+	}
+
+	for i := range digits {
+		digits[i] += '0'
+	}
+
+	return PLMN{MCC: string(digits[:3]), MNC: string(digits[3:])}, nil
+}
@@ -0,0 +1,74 @@
+package plmn_test
+
+import (
+	"testing"
+
+	"github.com/blorticus-go/gtp/plmn"
+	"github.com/go-test/deep"
+)
+
+func TestEncodeAndDecode(t *testing.T) {
+	testCases := []struct {
+		plmn    plmn.PLMN
+		encoded []byte
+	}{
+		{plmn.PLMN{MCC: "001", MNC: "01"}, []byte{0x00, 0xf1, 0x10}},
+		{plmn.PLMN{MCC: "001", MNC: "001"}, []byte{0x00, 0x11, 0x00}},
+		{plmn.PLMN{MCC: "310", MNC: "260"}, []byte{0x13, 0x00, 0x62}},
+		{plmn.PLMN{MCC: "234", MNC: "15"}, []byte{0x32, 0xf4, 0x51}},
+	}
+
+	for _, testCase := range testCases {
+		encoded, err := testCase.plmn.Encode()
+		if err != nil {
+			t.Errorf("[%s] on Encode expected no error, got = (%s)", testCase.plmn, err.Error())
+		} else if diff := deep.Equal(testCase.encoded, encoded); diff != nil {
+			t.Errorf("[%s] on Encode: %s", testCase.plmn, diff)
+		}
+
+		decoded, err := plmn.Decode(testCase.encoded)
+		if err != nil {
+			t.Errorf("[%s] on Decode expected no error, got = (%s)", testCase.plmn, err.Error())
+		} else if diff := deep.Equal(testCase.plmn, decoded); diff != nil {
+			t.Errorf("[%s] on Decode: %s", testCase.plmn, diff)
+		}
+	}
+}
+
+func TestInvalidCases(t *testing.T) {
+	for _, invalid := range []plmn.PLMN{
+		{MCC: "01", MNC: "01"},
+		{MCC: "0011", MNC: "01"},
+		{MCC: "001", MNC: "1"},
+		{MCC: "001", MNC: "0001"},
+		{MCC: "00a", MNC: "01"},
+	} {
+		if _, err := invalid.Encode(); err == nil {
+			t.Errorf("[%s] expected error on Encode, got none", invalid)
+		}
+	}
+
+	for _, invalid := range [][]byte{
+		{0x00, 0xf1},
+		{0x00, 0xf1, 0x10, 0x00},
+		{0x0a, 0xf1, 0x10},
+		{0x00, 0xe1, 0x10},
+		{0x00, 0xf1, 0xf0},
+	} {
+		if _, err := plmn.Decode(invalid); err == nil {
+			t.Errorf("[% x] expected error on Decode, got none", invalid)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	p := plmn.PLMN{MCC: "310", MNC: "26"}
+
+	if p.String() != "310-26" {
+		t.Errorf("expected String() = (310-26), got (%s)", p.String())
+	}
+
+	if p.HasThreeDigitMNC() {
+		t.Errorf("expected HasThreeDigitMNC() = false for (%s)", p)
+	}
+}
@@ -0,0 +1,83 @@
+// Package tbcd encodes and decodes strings of decimal digits using Telephony
+// Binary Coded Decimal (TBCD) (TS 29.002 section 17.7.8), as used by the IMSI,
+// MSISDN and IMEI identities in GTP IEs.  Each octet carries two digits.  The
+// first digit is in the low-order nybble and the second is in the high-order
+// nybble.  When there is an odd number of digits, or when the encoding is
+// padded to a fixed length, unused nybbles are set to the filler value 1111b.
+package tbcd
+
+import (
+	"fmt"
+)
+
+const filler = 0x0f
+
+// Encode converts a string of decimal digits to TBCD.  If the number of digits
+// is odd, the high-order nybble of the last octet is set to filler.  Returns an
+// error if digits is empty or contains anything other than decimal digits.
+func Encode(digits string) ([]byte, error) {
+	return EncodeToLength(digits, (len(digits)+1)/2)
+}
+
+// EncodeToLength is the same as Encode, but the result is exactly octetCount
+// octets long, with all nybbles after the last digit set to filler.  Returns
+// an error if the digits do not fit in octetCount octets.
+func EncodeToLength(digits string, octetCount int) ([]byte, error) {
+	if len(digits) == 0 {
+		return nil, fmt.Errorf("no digits to encode")
+	}
+
+	if len(digits) > octetCount*2 {
+		return nil, fmt.Errorf("(%d) digits do not fit in (%d) octets", len(digits), octetCount)
+	}
+
+	encoded := make([]byte, octetCount)
+	for i := range encoded {
+		encoded[i] = filler<<4 | filler
+	}
+
+	for position := 0; position < len(digits); position++ {
+		digit := digits[position]
+
+		if digit < '0' || digit > '9' {
+			return nil, fmt.Errorf("invalid digit (%q) at position (%d)", digit, position+1)
+		}
+
+		if position%2 == 0 {
+			encoded[position/2] = encoded[position/2]&0xf0 | (digit - '0')
+		} else {
+			encoded[position/2] = encoded[position/2]&0x0f | (digit-'0')<<4
+		}
+	}
+
+	return encoded, nil
+}
+
+// Decode converts TBCD octets to a string of decimal digits.  Decoding stops at
+// the first filler nybble, and every nybble after that must also be filler.
+// Returns an error identifying the digit position of any nybble that is not a
+// decimal digit or filler.
+func Decode(encoded []byte) (string, error) {
+	digits := make([]byte, 0, len(encoded)*2)
+	fillerFound := false
+
+	for position := 0; position < len(encoded)*2; position++ {
+		nybble := encoded[position/2] & 0x0f
+		if position%2 == 1 {
+			nybble = encoded[position/2] >> 4
+		}
+
+		switch {
+		case nybble == filler:
+			fillerFound = true
+		case fillerFound:
+			return "", fmt.Errorf("digit at position (%d) follows a filler value", position+1)
+		case nybble > 9:
+			return "", fmt.Errorf("invalid digit value (0x%x) at position (%d)", nybble, position+1)
+		default:
+			digits = append(digits, '0'+nybble)
+		}
+	}
+
+	return string(digits), nil
+}
@@ -0,0 +1,87 @@
+package tbcd_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/gtp/tbcd"
+	"github.com/go-test/deep"
+)
+
+func TestEncodeAndDecode(t *testing.T) {
+	testCases := []struct {
+		digits     string
+		octetCount int
+		encoded    []byte
+	}{
+		{"001002789012345", 8, []byte{0x00, 0x01, 0x20, 0x87, 0x09, 0x21, 0x43, 0xf5}},
+		{"0010027890123456", 8, []byte{0x00, 0x01, 0x20, 0x87, 0x09, 0x21, 0x43, 0x65}},
+		{"1", 1, []byte{0xf1}},
+		{"15551234567", 6, []byte{0x51, 0x55, 0x21, 0x43, 0x65, 0xf7}},
+		{"31026", 8, []byte{0x13, 0x20, 0xf6, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+
+	for _, testCase := range testCases {
+		encoded, err := tbcd.EncodeToLength(testCase.digits, testCase.octetCount)
+		if err != nil {
+			t.Errorf("[%s] on EncodeToLength expected no error, got = (%s)", testCase.digits, err.Error())
+		} else if diff := deep.Equal(testCase.encoded, encoded); diff != nil {
+			t.Errorf("[%s] on EncodeToLength: %s", testCase.digits, diff)
+		}
+
+		decoded, err := tbcd.Decode(testCase.encoded)
+		if err != nil {
+			t.Errorf("[%s] on Decode expected no error, got = (%s)", testCase.digits, err.Error())
+		} else if decoded != testCase.digits {
+			t.Errorf("[%s] on Decode got (%s)", testCase.digits, decoded)
+		}
+	}
+
+	encoded, err := tbcd.Encode("12345")
+	if err != nil {
+		t.Errorf("[Encode] expected no error, got = (%s)", err.Error())
+	} else if diff := deep.Equal([]byte{0x21, 0x43, 0xf5}, encoded); diff != nil {
+		t.Errorf("[Encode]: %s", diff)
+	}
+}
+
+func TestEncodeInvalidCases(t *testing.T) {
+	testCases := []struct {
+		digits                string
+		octetCount            int
+		expectedErrorFragment string
+	}{
+		{"", 8, "no digits"},
+		{"12345678901234567", 8, "do not fit"},
+		{"0010a2", 8, "position (5)"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := tbcd.EncodeToLength(testCase.digits, testCase.octetCount)
+		if err == nil {
+			t.Errorf("[%s] on EncodeToLength expected error, but got none", testCase.digits)
+		} else if !strings.Contains(err.Error(), testCase.expectedErrorFragment) {
+			t.Errorf("[%s] on EncodeToLength expected error containing (%s), got (%s)", testCase.digits, testCase.expectedErrorFragment, err.Error())
+		}
+	}
+}
+
+func TestDecodeInvalidCases(t *testing.T) {
+	testCases := []struct {
+		encoded               []byte
+		expectedErrorFragment string
+	}{
+		{[]byte{0x21, 0xa3}, "position (4)"},
+		{[]byte{0x21, 0x3f, 0x45}, "position (4)"},
+		{[]byte{0x1f}, "position (2)"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := tbcd.Decode(testCase.encoded)
+		if err == nil {
+			t.Errorf("[%02x] on Decode expected error, but got none", testCase.encoded)
+		} else if !strings.Contains(err.Error(), testCase.expectedErrorFragment) {
+			t.Errorf("[%02x] on Decode expected error containing (%s), got (%s)", testCase.encoded, testCase.expectedErrorFragment, err.Error())
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package teid allocates Tunnel Endpoint Identifiers (TEIDs), and maps the
+// TEIDs allocated by a node to the sessions that own them, so that a received
+// GTPv1 or GTPv2 PDU can be routed by the TEID in its header.  TEID 0 has a
+// special meaning in both versions (TS 29.274 section 5.5.2 and TS 29.060
+// section 6), so it is never allocated.
+package teid
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Strategy is the order in which an Allocator chooses TEIDs
+type Strategy uint8
+
+// Allocation strategies
+const (
+	// Sequential allocates TEIDs in increasing order, wrapping from the
+	// maximum back to the minimum
+	Sequential Strategy = iota
+	// Random allocates TEIDs in random order, which makes them harder for an
+	// attacker to guess
+	Random
+)
+
+// ErrExhausted is returned by Allocator.Allocate when every TEID in the range
+// is in use or waiting for reuse
+var ErrExhausted = errors.New("no TEID is available")
+
+// ErrInUse is returned (wrapped) when a TEID cannot be used because it is
+// already allocated, or is waiting for reuse
+var ErrInUse = errors.New("TEID is in use")
+
+// ErrNotAllocated is returned (wrapped) when a TEID that is not allocated is
+// released
+var ErrNotAllocated = errors.New("TEID is not allocated")
+
+// randomAttemptsBeforeScan is the number of random TEIDs that the Random
+// strategy tries before it scans for an available one
+const randomAttemptsBeforeScan = 64
+
+// AllocatorConfig provides the settings for an Allocator
+type AllocatorConfig struct {
+	Strategy Strategy
+
+	// Minimum and Maximum bound the TEIDs that are allocated.  A Minimum of 0
+	// is treated as 1, and a Maximum of 0 is treated as 0xffffffff.
+	Minimum uint32
+	Maximum uint32
+
+	// ReuseDelay is how long a released TEID is held before it can be
+	// allocated again, so that late messages for the old tunnel are not
+	// routed to a new one
+	ReuseDelay time.Duration
+}
+
+type releasedTEID struct {
+	teid       uint32
+	reusableAt time.Time
+}
+
+// Allocator allocates TEIDs from a range.  An Allocator is safe for
+// concurrent use.
+type Allocator struct {
+	config AllocatorConfig
+	size   uint64
+
+	lock          sync.Mutex
+	allocated     map[uint32]bool
+	awaitingReuse map[uint32]bool
+	releaseQueue  []releasedTEID
+	next          uint32
+	random        *rand.Rand
+}
+
+// NewAllocator creates an Allocator with no TEIDs allocated.  Returns an error
+// if Minimum is greater than Maximum.
+func NewAllocator(config AllocatorConfig) (*Allocator, error) {
+	if config.Minimum == 0 {
+		config.Minimum = 1
+	}
+
+	if config.Maximum == 0 {
+		config.Maximum = 0xffffffff
+	}
+
+	if config.Minimum > config.Maximum {
+		return nil, fmt.Errorf("minimum TEID (%d) is greater than maximum TEID (%d)", config.Minimum, config.Maximum)
+	}
+
+	return &Allocator{
+		config:        config,
+		size:          uint64(config.Maximum-config.Minimum) + 1,
+		allocated:     make(map[uint32]bool),
+		awaitingReuse: make(map[uint32]bool),
+		next:          config.Minimum,
+		random:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// Allocate returns a TEID that is not in use, and marks it as in use.  Returns
+// ErrExhausted if there is none.
+func (allocator *Allocator) Allocate() (uint32, error) {
+	allocator.lock.Lock()
+	defer allocator.lock.Unlock()
+
+	allocator.expireReleased(time.Now())
+
+	if uint64(len(allocator.allocated)+len(allocator.awaitingReuse)) >= allocator.size {
+		return 0, ErrExhausted
+	}
+
+	if allocator.config.Strategy == Random {
+		for attempt := 0; attempt < randomAttemptsBeforeScan; attempt++ {
+			teid := allocator.config.Minimum + uint32(allocator.random.Int63n(int64(allocator.size)))
+			if allocator.isAvailable(teid) {
+				allocator.allocated[teid] = true
+				return teid, nil
+			}
+		}
+
+		allocator.next = allocator.config.Minimum + uint32(allocator.random.Int63n(int64(allocator.size)))
+	}
+
+	for {
+		teid := allocator.next
+		if allocator.next == allocator.config.Maximum {
+			allocator.next = allocator.config.Minimum
+		} else {
+			allocator.next++
+		}
+
+		if allocator.isAvailable(teid) {
+			allocator.allocated[teid] = true
+			return teid, nil
+		}
+	}
+}
+
+// Reserve marks a specific TEID as in use, as when sessions are restored
+// after a restart.  Returns an error wrapping ErrInUse if the TEID is in use
+// or waiting for reuse, and an error if it is outside of the range.
+func (allocator *Allocator) Reserve(teid uint32) error {
+	if teid < allocator.config.Minimum || teid > allocator.config.Maximum {
+		return fmt.Errorf("TEID (0x%08x) is outside of the range (0x%08x) to (0x%08x)", teid, allocator.config.Minimum, allocator.config.Maximum)
+	}
+
+	allocator.lock.Lock()
+	defer allocator.lock.Unlock()
+
+	allocator.expireReleased(time.Now())
+
+	if !allocator.isAvailable(teid) {
+		return fmt.Errorf("TEID (0x%08x): %w", teid, ErrInUse)
+	}
+
+	allocator.allocated[teid] = true
+
+	return nil
+}
+
+// Release returns a TEID to the Allocator.  It is not allocated again until
+// ReuseDelay has passed.  Returns an error wrapping ErrNotAllocated if it is
+// not in use.
+func (allocator *Allocator) Release(teid uint32) error {
+	allocator.lock.Lock()
+	defer allocator.lock.Unlock()
+
+	if !allocator.allocated[teid] {
+		return fmt.Errorf("TEID (0x%08x): %w", teid, ErrNotAllocated)
+	}
+
+	delete(allocator.allocated, teid)
+
+	if allocator.config.ReuseDelay > 0 {
+		allocator.awaitingReuse[teid] = true
+		allocator.releaseQueue = append(allocator.releaseQueue, releasedTEID{teid: teid, reusableAt: time.Now().Add(allocator.config.ReuseDelay)})
+	}
+
+	return nil
+}
+
+// IsAllocated returns true if the TEID is in use
+func (allocator *Allocator) IsAllocated(teid uint32) bool {
+	allocator.lock.Lock()
+	defer allocator.lock.Unlock()
+
+	return allocator.allocated[teid]
+}
+
+// Count returns the number of TEIDs in use
+func (allocator *Allocator) Count() int {
+	allocator.lock.Lock()
+	defer allocator.lock.Unlock()
+
+	return len(allocator.allocated)
+}
+
+// isAvailable must be called with the lock held
+func (allocator *Allocator) isAvailable(teid uint32) bool {
+	return !allocator.allocated[teid] && !allocator.awaitingReuse[teid]
+}
+
+// expireReleased makes released TEIDs whose ReuseDelay has passed available.
+// Because ReuseDelay is the same for every TEID, the queue is in order of
+// reusableAt.  Must be called with the lock held.
+func (allocator *Allocator) expireReleased(now time.Time) {
+	expired := 0
+	for ; expired < len(allocator.releaseQueue) && !now.Before(allocator.releaseQueue[expired].reusableAt); expired++ {
+		delete(allocator.awaitingReuse, allocator.releaseQueue[expired].teid)
+	}
+
+	allocator.releaseQueue = allocator.releaseQueue[expired:]
+}
@@ -0,0 +1,117 @@
+package teid_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blorticus-go/gtp/teid"
+)
+
+func TestSequentialAllocator(t *testing.T) {
+	allocator, err := teid.NewAllocator(teid.AllocatorConfig{Strategy: teid.Sequential, Maximum: 4})
+	if err != nil {
+		t.Fatalf("[TestSequentialAllocator] expected no error on NewAllocator, got (%s)", err)
+	}
+
+	for expected := uint32(1); expected <= 4; expected++ {
+		if allocated, err := allocator.Allocate(); err != nil || allocated != expected {
+			t.Errorf("[TestSequentialAllocator] expected TEID (%d), got (%d, %v)", expected, allocated, err)
+		}
+	}
+
+	if _, err := allocator.Allocate(); !errors.Is(err, teid.ErrExhausted) {
+		t.Errorf("[TestSequentialAllocator] expected ErrExhausted, got (%v)", err)
+	}
+
+	if err := allocator.Release(2); err != nil {
+		t.Errorf("[TestSequentialAllocator] expected no error on Release, got (%s)", err)
+	}
+
+	if err := allocator.Release(2); !errors.Is(err, teid.ErrNotAllocated) {
+		t.Errorf("[TestSequentialAllocator] expected ErrNotAllocated on second Release, got (%v)", err)
+	}
+
+	if allocated, err := allocator.Allocate(); err != nil || allocated != 2 {
+		t.Errorf("[TestSequentialAllocator] expected released TEID (2) after wrap, got (%d, %v)", allocated, err)
+	}
+
+	if allocator.Count() != 4 || !allocator.IsAllocated(3) {
+		t.Errorf("[TestSequentialAllocator] expected (4) TEIDs in use, including (3)")
+	}
+
+	if err := allocator.Reserve(3); !errors.Is(err, teid.ErrInUse) {
+		t.Errorf("[TestSequentialAllocator] expected ErrInUse on Reserve, got (%v)", err)
+	}
+
+	if err := allocator.Reserve(0); err == nil {
+		t.Errorf("[TestSequentialAllocator] expected error on Reserve of TEID 0, got none")
+	}
+
+	if _, err := teid.NewAllocator(teid.AllocatorConfig{Minimum: 10, Maximum: 5}); err == nil {
+		t.Errorf("[TestSequentialAllocator] expected error on NewAllocator with Minimum greater than Maximum, got none")
+	}
+}
+
+func TestAllocatorReuseDelay(t *testing.T) {
+	allocator, _ := teid.NewAllocator(teid.AllocatorConfig{Strategy: teid.Random, Minimum: 100, Maximum: 101, ReuseDelay: 50 * time.Millisecond})
+
+	first, _ := allocator.Allocate()
+	second, _ := allocator.Allocate()
+
+	if first == second || first < 100 || first > 101 || second < 100 || second > 101 {
+		t.Fatalf("[TestAllocatorReuseDelay] expected TEIDs (100) and (101), got (%d) and (%d)", first, second)
+	}
+
+	allocator.Release(first)
+
+	if _, err := allocator.Allocate(); !errors.Is(err, teid.ErrExhausted) {
+		t.Errorf("[TestAllocatorReuseDelay] expected ErrExhausted during reuse delay, got (%v)", err)
+	}
+
+	if err := allocator.Reserve(first); !errors.Is(err, teid.ErrInUse) {
+		t.Errorf("[TestAllocatorReuseDelay] expected ErrInUse on Reserve during reuse delay, got (%v)", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if allocated, err := allocator.Allocate(); err != nil || allocated != first {
+		t.Errorf("[TestAllocatorReuseDelay] expected TEID (%d) after reuse delay, got (%d, %v)", first, allocated, err)
+	}
+}
+
+func TestRandomAllocatorConcurrency(t *testing.T) {
+	allocator, _ := teid.NewAllocator(teid.AllocatorConfig{Strategy: teid.Random, Maximum: 2000})
+
+	allocated := make(chan uint32, 2000)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for j := 0; j < 100; j++ {
+				value, err := allocator.Allocate()
+				if err != nil {
+					t.Errorf("[TestRandomAllocatorConcurrency] expected no error on Allocate, got (%s)", err)
+					return
+				}
+				allocated <- value
+			}
+		}()
+	}
+	waitGroup.Wait()
+	close(allocated)
+
+	seen := make(map[uint32]bool)
+	for value := range allocated {
+		if value == 0 || value > 2000 || seen[value] {
+			t.Fatalf("[TestRandomAllocatorConcurrency] TEID (%d) is out of range or allocated more than once", value)
+		}
+		seen[value] = true
+	}
+
+	if len(seen) != 2000 {
+		t.Errorf("[TestRandomAllocatorConcurrency] expected (2000) TEIDs, got (%d)", len(seen))
+	}
+}
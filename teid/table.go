@@ -0,0 +1,118 @@
+package teid
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Table maps the TEIDs allocated by a node to sessions of type S.  A node
+// usually has one Table for the control plane and one for the user plane.
+// TEIDs are allocated from the Allocator given to NewTable, so every TEID in
+// the Table is allocated, and every TEID removed from the Table is released.
+// A Table is safe for concurrent use.
+type Table[S any] struct {
+	allocator *Allocator
+
+	lock     sync.RWMutex
+	sessions map[uint32]S
+}
+
+// NewTable creates an empty Table that allocates TEIDs from allocator.  The
+// allocator should not be shared with another Table.
+func NewTable[S any](allocator *Allocator) *Table[S] {
+	return &Table[S]{
+		allocator: allocator,
+		sessions:  make(map[uint32]S),
+	}
+}
+
+// Add allocates a TEID for session, and returns it.  Returns ErrExhausted if
+// no TEID is available.
+func (table *Table[S]) Add(session S) (uint32, error) {
+	teid, err := table.allocator.Allocate()
+	if err != nil {
+		return 0, err
+	}
+
+	table.lock.Lock()
+	table.sessions[teid] = session
+	table.lock.Unlock()
+
+	return teid, nil
+}
+
+// Insert adds session with a specific TEID, as when sessions are restored
+// after a restart.  Returns an error wrapping ErrInUse if the TEID is already
+// in use.
+func (table *Table[S]) Insert(teid uint32, session S) error {
+	if err := table.allocator.Reserve(teid); err != nil {
+		return err
+	}
+
+	table.lock.Lock()
+	table.sessions[teid] = session
+	table.lock.Unlock()
+
+	return nil
+}
+
+// Lookup returns the session for a TEID, or false if the TEID is not in the
+// Table.  It is used to route a received PDU by the TEID in its header.
+func (table *Table[S]) Lookup(teid uint32) (S, bool) {
+	table.lock.RLock()
+	defer table.lock.RUnlock()
+
+	session, isPresent := table.sessions[teid]
+	return session, isPresent
+}
+
+// Replace changes the session for a TEID that is in the Table.  Returns an
+// error wrapping ErrNotAllocated if the TEID is not in the Table.
+func (table *Table[S]) Replace(teid uint32, session S) error {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+
+	if _, isPresent := table.sessions[teid]; !isPresent {
+		return fmt.Errorf("TEID (0x%08x): %w", teid, ErrNotAllocated)
+	}
+
+	table.sessions[teid] = session
+
+	return nil
+}
+
+// Remove removes a TEID from the Table, releases it, and returns its session.
+// Returns false if the TEID is not in the Table.
+func (table *Table[S]) Remove(teid uint32) (S, bool) {
+	table.lock.Lock()
+	session, isPresent := table.sessions[teid]
+	delete(table.sessions, teid)
+	table.lock.Unlock()
+
+	if isPresent {
+		table.allocator.Release(teid)
+	}
+
+	return session, isPresent
+}
+
+// Len returns the number of TEIDs in the Table
+func (table *Table[S]) Len() int {
+	table.lock.RLock()
+	defer table.lock.RUnlock()
+
+	return len(table.sessions)
+}
+
+// Range calls f for each TEID and session in the Table, in no particular
+// order, until f returns false.  The Table must not be changed by f.
+func (table *Table[S]) Range(f func(teid uint32, session S) bool) {
+	table.lock.RLock()
+	defer table.lock.RUnlock()
+
+	for teid, session := range table.sessions {
+		if !f(teid, session) {
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package teid_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blorticus-go/gtp/teid"
+)
+
+type testSession struct {
+	imsi string
+}
+
+func TestTable(t *testing.T) {
+	allocator, _ := teid.NewAllocator(teid.AllocatorConfig{Maximum: 10})
+	table := teid.NewTable[*testSession](allocator)
+
+	first := &testSession{imsi: "001010000000001"}
+	second := &testSession{imsi: "001010000000002"}
+
+	firstTEID, err := table.Add(first)
+	if err != nil {
+		t.Fatalf("[TestTable] expected no error on Add, got (%s)", err)
+	}
+
+	if err := table.Insert(5, second); err != nil {
+		t.Fatalf("[TestTable] expected no error on Insert, got (%s)", err)
+	}
+
+	if err := table.Insert(firstTEID, second); !errors.Is(err, teid.ErrInUse) {
+		t.Errorf("[TestTable] expected ErrInUse on Insert of TEID in use, got (%v)", err)
+	}
+
+	if session, isPresent := table.Lookup(firstTEID); !isPresent || session != first {
+		t.Errorf("[TestTable] expected Lookup of (%d) to return first session", firstTEID)
+	}
+
+	if session, isPresent := table.Lookup(5); !isPresent || session != second {
+		t.Errorf("[TestTable] expected Lookup of (5) to return second session")
+	}
+
+	if _, isPresent := table.Lookup(6); isPresent {
+		t.Errorf("[TestTable] expected Lookup of (6) to find nothing")
+	}
+
+	if err := table.Replace(6, first); !errors.Is(err, teid.ErrNotAllocated) {
+		t.Errorf("[TestTable] expected ErrNotAllocated on Replace of unknown TEID, got (%v)", err)
+	}
+
+	count := 0
+	table.Range(func(teid uint32, session *testSession) bool {
+		count++
+		return true
+	})
+
+	if count != 2 || table.Len() != 2 {
+		t.Errorf("[TestTable] expected (2) sessions, got (%d) from Range and (%d) from Len", count, table.Len())
+	}
+
+	if session, isPresent := table.Remove(5); !isPresent || session != second {
+		t.Errorf("[TestTable] expected Remove of (5) to return second session")
+	}
+
+	if allocator.IsAllocated(5) {
+		t.Errorf("[TestTable] expected removed TEID to be released")
+	}
+
+	if _, isPresent := table.Remove(5); isPresent {
+		t.Errorf("[TestTable] expected second Remove of (5) to find nothing")
+	}
+}
@@ -0,0 +1,403 @@
+package tft
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// ComponentType is a packet filter component type identifier
+type ComponentType uint8
+
+// Packet filter component types (TS 24.008 Table 10.5.162)
+const (
+	ComponentTypeIPv4RemoteAddress             ComponentType = 0x10
+	ComponentTypeIPv4LocalAddress              ComponentType = 0x11
+	ComponentTypeIPv6RemoteAddress             ComponentType = 0x20
+	ComponentTypeIPv6RemoteAddressPrefixLength ComponentType = 0x21
+	ComponentTypeIPv6LocalAddressPrefixLength  ComponentType = 0x23
+	ComponentTypeProtocolIdentifier            ComponentType = 0x30
+	ComponentTypeSingleLocalPort               ComponentType = 0x40
+	ComponentTypeLocalPortRange                ComponentType = 0x41
+	ComponentTypeSingleRemotePort              ComponentType = 0x50
+	ComponentTypeRemotePortRange               ComponentType = 0x51
+	ComponentTypeSecurityParameterIndex        ComponentType = 0x60
+	ComponentTypeTypeOfService                 ComponentType = 0x70
+	ComponentTypeFlowLabel                     ComponentType = 0x80
+)
+
+var componentTypeNames = map[ComponentType]string{
+	ComponentTypeIPv4RemoteAddress:             "IPv4 remote address",
+	ComponentTypeIPv4LocalAddress:              "IPv4 local address",
+	ComponentTypeIPv6RemoteAddress:             "IPv6 remote address",
+	ComponentTypeIPv6RemoteAddressPrefixLength: "IPv6 remote address/prefix length",
+	ComponentTypeIPv6LocalAddressPrefixLength:  "IPv6 local address/prefix length",
+	ComponentTypeProtocolIdentifier:            "Protocol identifier/Next header",
+	ComponentTypeSingleLocalPort:               "Single local port",
+	ComponentTypeLocalPortRange:                "Local port range",
+	ComponentTypeSingleRemotePort:              "Single remote port",
+	ComponentTypeRemotePortRange:               "Remote port range",
+	ComponentTypeSecurityParameterIndex:        "Security parameter index",
+	ComponentTypeTypeOfService:                 "Type of service/Traffic class",
+	ComponentTypeFlowLabel:                     "Flow label",
+}
+
+// String returns the name of the component type from TS 24.008, or its
+// value in hex if the type is not known
+func (componentType ComponentType) String() string {
+	if name, isKnown := componentTypeNames[componentType]; isKnown {
+		return name
+	}
+
+	return fmt.Sprintf("0x%02x", uint8(componentType))
+}
+
+// Component is a single packet filter component.  Each component type
+// has its own struct (e.g., IPv4RemoteAddress, SingleRemotePort).
+type Component interface {
+	Type() ComponentType
+
+	// encodeValue returns the component value, which excludes the
+	// component type identifier
+	encodeValue() ([]byte, error)
+}
+
+// IPv4RemoteAddress matches the remote IPv4 address against Address,
+// after applying Mask.  If Mask is nil, it is treated as a host mask.
+type IPv4RemoteAddress struct {
+	Address net.IP
+	Mask    net.IPMask
+}
+
+// IPv4LocalAddress matches the local (UE) IPv4 address against Address,
+// after applying Mask.  If Mask is nil, it is treated as a host mask.
+type IPv4LocalAddress struct {
+	Address net.IP
+	Mask    net.IPMask
+}
+
+// IPv6RemoteAddress matches the remote IPv6 address against Address,
+// after applying Mask.  If Mask is nil, it is treated as a host mask.
+type IPv6RemoteAddress struct {
+	Address net.IP
+	Mask    net.IPMask
+}
+
+// IPv6RemoteAddressPrefixLength matches the remote IPv6 address against
+// the first PrefixLength bits of Address
+type IPv6RemoteAddressPrefixLength struct {
+	Address      net.IP
+	PrefixLength uint8
+}
+
+// IPv6LocalAddressPrefixLength matches the local (UE) IPv6 address against
+// the first PrefixLength bits of Address
+type IPv6LocalAddressPrefixLength struct {
+	Address      net.IP
+	PrefixLength uint8
+}
+
+// ProtocolIdentifier matches the IPv4 protocol or IPv6 next header value
+type ProtocolIdentifier struct {
+	Protocol uint8
+}
+
+// SingleLocalPort matches a single local (UE) port
+type SingleLocalPort struct {
+	Port uint16
+}
+
+// LocalPortRange matches an inclusive range of local (UE) ports
+type LocalPortRange struct {
+	Low  uint16
+	High uint16
+}
+
+// SingleRemotePort matches a single remote port
+type SingleRemotePort struct {
+	Port uint16
+}
+
+// RemotePortRange matches an inclusive range of remote ports
+type RemotePortRange struct {
+	Low  uint16
+	High uint16
+}
+
+// SecurityParameterIndex matches an IPsec SPI
+type SecurityParameterIndex struct {
+	SPI uint32
+}
+
+// TypeOfService matches the IPv4 type of service or IPv6 traffic class,
+// after applying Mask
+type TypeOfService struct {
+	Value uint8
+	Mask  uint8
+}
+
+// FlowLabel matches the IPv6 flow label.  Label is actually a uint20 value.
+type FlowLabel struct {
+	Label uint32
+}
+
+// Type returns ComponentTypeIPv4RemoteAddress
+func (c *IPv4RemoteAddress) Type() ComponentType { return ComponentTypeIPv4RemoteAddress }
+
+// Type returns ComponentTypeIPv4LocalAddress
+func (c *IPv4LocalAddress) Type() ComponentType { return ComponentTypeIPv4LocalAddress }
+
+// Type returns ComponentTypeIPv6RemoteAddress
+func (c *IPv6RemoteAddress) Type() ComponentType { return ComponentTypeIPv6RemoteAddress }
+
+// Type returns ComponentTypeIPv6RemoteAddressPrefixLength
+func (c *IPv6RemoteAddressPrefixLength) Type() ComponentType {
+	return ComponentTypeIPv6RemoteAddressPrefixLength
+}
+
+// Type returns ComponentTypeIPv6LocalAddressPrefixLength
+func (c *IPv6LocalAddressPrefixLength) Type() ComponentType {
+	return ComponentTypeIPv6LocalAddressPrefixLength
+}
+
+// Type returns ComponentTypeProtocolIdentifier
+func (c *ProtocolIdentifier) Type() ComponentType { return ComponentTypeProtocolIdentifier }
+
+// Type returns ComponentTypeSingleLocalPort
+func (c *SingleLocalPort) Type() ComponentType { return ComponentTypeSingleLocalPort }
+
+// Type returns ComponentTypeLocalPortRange
+func (c *LocalPortRange) Type() ComponentType { return ComponentTypeLocalPortRange }
+
+// Type returns ComponentTypeSingleRemotePort
+func (c *SingleRemotePort) Type() ComponentType { return ComponentTypeSingleRemotePort }
+
+// Type returns ComponentTypeRemotePortRange
+func (c *RemotePortRange) Type() ComponentType { return ComponentTypeRemotePortRange }
+
+// Type returns ComponentTypeSecurityParameterIndex
+func (c *SecurityParameterIndex) Type() ComponentType { return ComponentTypeSecurityParameterIndex }
+
+// Type returns ComponentTypeTypeOfService
+func (c *TypeOfService) Type() ComponentType { return ComponentTypeTypeOfService }
+
+// Type returns ComponentTypeFlowLabel
+func (c *FlowLabel) Type() ComponentType { return ComponentTypeFlowLabel }
+
+func encodeAddressAndMask(address net.IP, mask net.IPMask, addressLength int) ([]byte, error) {
+	var asBytes net.IP
+	if addressLength == net.IPv4len {
+		asBytes = address.To4()
+	} else if address.To4() == nil {
+		asBytes = address.To16()
+	}
+
+	if asBytes == nil {
+		return nil, fmt.Errorf("address (%s) is not a %d octet address", address, addressLength)
+	}
+
+	if mask == nil {
+		mask = net.CIDRMask(addressLength*8, addressLength*8)
+	}
+
+	if len(mask) != addressLength {
+		return nil, fmt.Errorf("mask length (%d) does not match address length (%d)", len(mask), addressLength)
+	}
+
+	return append(append(make([]byte, 0, addressLength*2), asBytes...), mask...), nil
+}
+
+func encodeIPv6AddressAndPrefixLength(address net.IP, prefixLength uint8) ([]byte, error) {
+	if address.To4() != nil || address.To16() == nil {
+		return nil, fmt.Errorf("address (%s) is not an IPv6 address", address)
+	}
+
+	if prefixLength > 128 {
+		return nil, fmt.Errorf("prefix length (%d) exceeds maximum (128)", prefixLength)
+	}
+
+	return append(append(make([]byte, 0, 17), address.To16()...), prefixLength), nil
+}
+
+func encodePortRange(low uint16, high uint16) ([]byte, error) {
+	if low > high {
+		return nil, fmt.Errorf("port range low value (%d) is greater than high value (%d)", low, high)
+	}
+
+	return binary.BigEndian.AppendUint16(binary.BigEndian.AppendUint16(nil, low), high), nil
+}
+
+func (c *IPv4RemoteAddress) encodeValue() ([]byte, error) {
+	return encodeAddressAndMask(c.Address, c.Mask, net.IPv4len)
+}
+
+func (c *IPv4LocalAddress) encodeValue() ([]byte, error) {
+	return encodeAddressAndMask(c.Address, c.Mask, net.IPv4len)
+}
+
+func (c *IPv6RemoteAddress) encodeValue() ([]byte, error) {
+	return encodeAddressAndMask(c.Address, c.Mask, net.IPv6len)
+}
+
+func (c *IPv6RemoteAddressPrefixLength) encodeValue() ([]byte, error) {
+	return encodeIPv6AddressAndPrefixLength(c.Address, c.PrefixLength)
+}
+
+func (c *IPv6LocalAddressPrefixLength) encodeValue() ([]byte, error) {
+	return encodeIPv6AddressAndPrefixLength(c.Address, c.PrefixLength)
+}
+
+func (c *ProtocolIdentifier) encodeValue() ([]byte, error) {
+	return []byte{c.Protocol}, nil
+}
+
+func (c *SingleLocalPort) encodeValue() ([]byte, error) {
+	return binary.BigEndian.AppendUint16(nil, c.Port), nil
+}
+
+func (c *LocalPortRange) encodeValue() ([]byte, error) {
+	return encodePortRange(c.Low, c.High)
+}
+
+func (c *SingleRemotePort) encodeValue() ([]byte, error) {
+	return binary.BigEndian.AppendUint16(nil, c.Port), nil
+}
+
+func (c *RemotePortRange) encodeValue() ([]byte, error) {
+	return encodePortRange(c.Low, c.High)
+}
+
+func (c *SecurityParameterIndex) encodeValue() ([]byte, error) {
+	return binary.BigEndian.AppendUint32(nil, c.SPI), nil
+}
+
+func (c *TypeOfService) encodeValue() ([]byte, error) {
+	return []byte{c.Value, c.Mask}, nil
+}
+
+func (c *FlowLabel) encodeValue() ([]byte, error) {
+	if c.Label > 0x000fffff {
+		return nil, fmt.Errorf("flow label (0x%x) exceeds maximum 20-bit value", c.Label)
+	}
+
+	return []byte{byte(c.Label >> 16), byte(c.Label >> 8), byte(c.Label)}, nil
+}
+
+// componentDecoders provides, for each known component type, the length of
+// the component value and a function that decodes it.  The component value
+// length is implied by the type, so a component of an unknown type cannot be
+// skipped.
+var componentDecoders = map[ComponentType]struct {
+	valueLength int
+	decode      func(value []byte) Component
+}{
+	ComponentTypeIPv4RemoteAddress: {8, func(v []byte) Component {
+		return &IPv4RemoteAddress{Address: copyIP(v[0:4]), Mask: net.IPMask(copyIP(v[4:8]))}
+	}},
+	ComponentTypeIPv4LocalAddress: {8, func(v []byte) Component {
+		return &IPv4LocalAddress{Address: copyIP(v[0:4]), Mask: net.IPMask(copyIP(v[4:8]))}
+	}},
+	ComponentTypeIPv6RemoteAddress: {32, func(v []byte) Component {
+		return &IPv6RemoteAddress{Address: copyIP(v[0:16]), Mask: net.IPMask(copyIP(v[16:32]))}
+	}},
+	ComponentTypeIPv6RemoteAddressPrefixLength: {17, func(v []byte) Component {
+		return &IPv6RemoteAddressPrefixLength{Address: copyIP(v[0:16]), PrefixLength: v[16]}
+	}},
+	ComponentTypeIPv6LocalAddressPrefixLength: {17, func(v []byte) Component {
+		return &IPv6LocalAddressPrefixLength{Address: copyIP(v[0:16]), PrefixLength: v[16]}
+	}},
+	ComponentTypeProtocolIdentifier: {1, func(v []byte) Component {
+		return &ProtocolIdentifier{Protocol: v[0]}
+	}},
+	ComponentTypeSingleLocalPort: {2, func(v []byte) Component {
+		return &SingleLocalPort{Port: binary.BigEndian.Uint16(v)}
+	}},
+	ComponentTypeLocalPortRange: {4, func(v []byte) Component {
+		return &LocalPortRange{Low: binary.BigEndian.Uint16(v[0:2]), High: binary.BigEndian.Uint16(v[2:4])}
+	}},
+	ComponentTypeSingleRemotePort: {2, func(v []byte) Component {
+		return &SingleRemotePort{Port: binary.BigEndian.Uint16(v)}
+	}},
+	ComponentTypeRemotePortRange: {4, func(v []byte) Component {
+		return &RemotePortRange{Low: binary.BigEndian.Uint16(v[0:2]), High: binary.BigEndian.Uint16(v[2:4])}
+	}},
+	ComponentTypeSecurityParameterIndex: {4, func(v []byte) Component {
+		return &SecurityParameterIndex{SPI: binary.BigEndian.Uint32(v)}
+	}},
+	ComponentTypeTypeOfService: {2, func(v []byte) Component {
+		return &TypeOfService{Value: v[0], Mask: v[1]}
+	}},
+	ComponentTypeFlowLabel: {3, func(v []byte) Component {
+		return &FlowLabel{Label: uint32(v[0]&0x0f)<<16 | uint32(v[1])<<8 | uint32(v[2])}
+	}},
+}
+
+func copyIP(octets []byte) net.IP {
+	ip := make(net.IP, len(octets))
+	copy(ip, octets)
+	return ip
+}
+
+func decodeComponents(contents []byte) ([]Component, error) {
+	components := make([]Component, 0)
+
+	for remaining := contents; len(remaining) > 0; {
+		componentType := ComponentType(remaining[0])
+
+		decoder, isKnown := componentDecoders[componentType]
+		if !isKnown {
+			return nil, fmt.Errorf("unknown packet filter component type (%s)", componentType)
+		}
+
+		if len(remaining)-1 < decoder.valueLength {
+			return nil, fmt.Errorf("insufficient octets for packet filter component (%s)", componentType)
+		}
+
+		components = append(components, decoder.decode(remaining[1:1+decoder.valueLength]))
+		remaining = remaining[1+decoder.valueLength:]
+	}
+
+	return components, nil
+}
+
+// componentConflicts lists sets of component types of which at most one may
+// be present in a single packet filter (TS 24.008 section 10.5.6.12)
+var componentConflicts = [][]ComponentType{
+	{ComponentTypeIPv4RemoteAddress, ComponentTypeIPv6RemoteAddress, ComponentTypeIPv6RemoteAddressPrefixLength},
+	{ComponentTypeIPv4LocalAddress, ComponentTypeIPv6LocalAddressPrefixLength},
+	{ComponentTypeSingleLocalPort, ComponentTypeLocalPortRange},
+	{ComponentTypeSingleRemotePort, ComponentTypeRemotePortRange},
+	{ComponentTypeIPv4RemoteAddress, ComponentTypeFlowLabel},
+	{ComponentTypeIPv4LocalAddress, ComponentTypeFlowLabel},
+}
+
+func validateComponentCombination(components []Component) error {
+	present := make(map[ComponentType]bool)
+
+	for _, component := range components {
+		if component == nil {
+			return fmt.Errorf("packet filter component is nil")
+		}
+
+		if present[component.Type()] {
+			return fmt.Errorf("packet filter component (%s) appears more than once", component.Type())
+		}
+
+		present[component.Type()] = true
+	}
+
+	for _, conflictSet := range componentConflicts {
+		var found []ComponentType
+		for _, componentType := range conflictSet {
+			if present[componentType] {
+				found = append(found, componentType)
+			}
+		}
+
+		if len(found) > 1 {
+			return fmt.Errorf("packet filter components (%s) and (%s) may not be used together", found[0], found[1])
+		}
+	}
+
+	return nil
+}
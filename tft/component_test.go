@@ -0,0 +1,111 @@
+package tft_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/blorticus-go/gtp/tft"
+	"github.com/go-test/deep"
+)
+
+func TestComponentEncoding(t *testing.T) {
+	testCases := []struct {
+		component     tft.Component
+		encoded       []byte
+		decodedAsSame tft.Component
+	}{
+		{
+			component: &tft.IPv4LocalAddress{Address: net.ParseIP("192.168.1.1")},
+			encoded:   []byte{0x11, 192, 168, 1, 1, 0xff, 0xff, 0xff, 0xff},
+			decodedAsSame: &tft.IPv4LocalAddress{
+				Address: net.IP{192, 168, 1, 1},
+				Mask:    net.IPMask{0xff, 0xff, 0xff, 0xff},
+			},
+		},
+		{
+			component: &tft.IPv6RemoteAddress{Address: net.ParseIP("2001:db8::1"), Mask: net.CIDRMask(64, 128)},
+			encoded: []byte{
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			component: &tft.IPv6LocalAddressPrefixLength{Address: net.ParseIP("2001:db8::"), PrefixLength: 64},
+			encoded:   []byte{0x23, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 64},
+		},
+		{
+			component: &tft.SingleLocalPort{Port: 443},
+			encoded:   []byte{0x40, 0x01, 0xbb},
+		},
+		{
+			component: &tft.RemotePortRange{Low: 1, High: 1024},
+			encoded:   []byte{0x51, 0x00, 0x01, 0x04, 0x00},
+		},
+		{
+			component: &tft.SecurityParameterIndex{SPI: 0x01020304},
+			encoded:   []byte{0x60, 0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			component: &tft.TypeOfService{Value: 0xb8, Mask: 0xfc},
+			encoded:   []byte{0x70, 0xb8, 0xfc},
+		},
+		{
+			component: &tft.FlowLabel{Label: 0xabcde},
+			encoded:   []byte{0x80, 0x0a, 0xbc, 0xde},
+		},
+	}
+
+	for _, testCase := range testCases {
+		filterTFT := &tft.TFT{
+			Operation:     tft.OperationCreateNewTFT,
+			PacketFilters: []*tft.PacketFilter{{Identifier: 1, Direction: tft.DirectionUplinkOnly, Components: []tft.Component{testCase.component}}},
+		}
+
+		encoded, err := filterTFT.Encode()
+		if err != nil {
+			t.Errorf("[%s] on Encode expected no error, got = (%s)", testCase.component.Type(), err.Error())
+			continue
+		}
+
+		expected := append([]byte{0x21, 0x21, 0x00, byte(len(testCase.encoded))}, testCase.encoded...)
+		if diff := deep.Equal(expected, encoded); diff != nil {
+			t.Errorf("[%s] on Encode: %s", testCase.component.Type(), diff)
+		}
+
+		decoded, err := tft.Decode(encoded)
+		if err != nil {
+			t.Errorf("[%s] on Decode expected no error, got = (%s)", testCase.component.Type(), err.Error())
+			continue
+		}
+
+		expectedComponent := testCase.component
+		if testCase.decodedAsSame != nil {
+			expectedComponent = testCase.decodedAsSame
+		}
+
+		if diff := deep.Equal(expectedComponent, decoded.PacketFilters[0].Components[0]); diff != nil {
+			t.Errorf("[%s] on Decode: %s", testCase.component.Type(), diff)
+		}
+	}
+}
+
+func TestInvalidComponents(t *testing.T) {
+	for _, invalid := range []tft.Component{
+		&tft.IPv4RemoteAddress{Address: net.ParseIP("2001:db8::1")},
+		&tft.IPv4RemoteAddress{Address: net.ParseIP("10.0.0.1"), Mask: net.CIDRMask(64, 128)},
+		&tft.IPv6RemoteAddress{Address: net.ParseIP("10.0.0.1")},
+		&tft.IPv6RemoteAddressPrefixLength{Address: net.ParseIP("2001:db8::"), PrefixLength: 129},
+		&tft.LocalPortRange{Low: 100, High: 99},
+		&tft.FlowLabel{Label: 0x100000},
+	} {
+		filterTFT := &tft.TFT{
+			Operation:     tft.OperationCreateNewTFT,
+			PacketFilters: []*tft.PacketFilter{{Identifier: 1, Components: []tft.Component{invalid}}},
+		}
+
+		if _, err := filterTFT.Encode(); err == nil {
+			t.Errorf("[%s] expected error on Encode for (%+v), got none", invalid.Type(), invalid)
+		}
+	}
+}
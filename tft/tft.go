@@ -0,0 +1,322 @@
+// Package tft encodes, decodes and validates the Traffic Flow Template
+// (TS 24.008 section 10.5.6.12), as carried in the GTPv2 Bearer TFT IE and
+// the GTPv1 TFT IE.
+//
+// A TFT has an Operation, a list of PacketFilters and an optional list of
+// Parameters.  Each PacketFilter has a list of Components, with one struct
+// type for each component type (e.g., IPv4RemoteAddress, RemotePortRange).
+// Which of the fields of a TFT may be used depends on the Operation.  Validate
+// applies those rules, and Encode will not encode a TFT that breaks them.
+package tft
+
+import (
+	"fmt"
+)
+
+// Operation is a TFT operation code
+type Operation uint8
+
+// TFT operation codes (TS 24.008 Table 10.5.162)
+const (
+	OperationIgnore               Operation = 0
+	OperationCreateNewTFT         Operation = 1
+	OperationDeleteExistingTFT    Operation = 2
+	OperationAddPacketFilters     Operation = 3
+	OperationReplacePacketFilters Operation = 4
+	OperationDeletePacketFilters  Operation = 5
+	OperationNoTFTOperation       Operation = 6
+	operationReserved             Operation = 7
+)
+
+const maximumPacketFiltersInTFT = 15
+const maximumPacketFilterIdentifier = 15
+const maximumPacketFilterContentSize = 255
+
+var operationNames = []string{
+	"Ignore", "Create new TFT", "Delete existing TFT", "Add packet filters to existing TFT",
+	"Replace packet filters in existing TFT", "Delete packet filters from existing TFT", "No TFT operation", "Reserved",
+}
+
+// String returns the name of the operation from TS 24.008
+func (operation Operation) String() string {
+	if int(operation) < len(operationNames) {
+		return operationNames[operation]
+	}
+
+	return fmt.Sprintf("Operation(%d)", uint8(operation))
+}
+
+// Direction is a packet filter direction
+type Direction uint8
+
+// Packet filter directions
+const (
+	DirectionPreRelease7   Direction = 0
+	DirectionDownlinkOnly  Direction = 1
+	DirectionUplinkOnly    Direction = 2
+	DirectionBidirectional Direction = 3
+)
+
+// PacketFilter is a single packet filter.  Identifier is actually a uint4
+// value.  When the TFT Operation is OperationDeletePacketFilters, only the
+// Identifier is used, and the other fields must be zero-valued.
+type PacketFilter struct {
+	Identifier uint8
+	Direction  Direction
+	Precedence uint8
+	Components []Component
+}
+
+// ParameterID is a TFT parameter identifier
+type ParameterID uint8
+
+// TFT parameter identifiers (TS 24.008 section 10.5.6.12)
+const (
+	ParameterIDAuthorizationToken     ParameterID = 1
+	ParameterIDFlowIdentifier         ParameterID = 2
+	ParameterIDPacketFilterIdentifier ParameterID = 3
+)
+
+// Parameter is a single entry in the TFT parameters list.  Contents are
+// kept raw.
+type Parameter struct {
+	ID       ParameterID
+	Contents []byte
+}
+
+// TFT is a Traffic Flow Template
+type TFT struct {
+	Operation     Operation
+	PacketFilters []*PacketFilter
+	Parameters    []*Parameter
+}
+
+// Validate applies the syntactic rules from TS 24.008 section 10.5.6.12 and
+// the operation-specific rules from section 6.1.3.3.4:
+//   - the operation is not the reserved value
+//   - OperationDeleteExistingTFT and OperationNoTFTOperation have no packet
+//     filters, and OperationNoTFTOperation has parameters
+//   - the other operations (except OperationIgnore) have at least one, and no
+//     more than 15, packet filters
+//   - packet filter identifiers are at most 15, and are unique in the TFT
+//   - for OperationDeletePacketFilters, each packet filter has only an identifier
+//   - for operations that create or modify packet filters, precedence values are
+//     unique in the TFT, direction is valid, there is at least one component, and
+//     no component type is repeated or combined with a conflicting type
+func (tft *TFT) Validate() error {
+	switch tft.Operation {
+	case operationReserved:
+		return fmt.Errorf("TFT operation (%d) is reserved", tft.Operation)
+
+	case OperationDeleteExistingTFT, OperationNoTFTOperation:
+		if len(tft.PacketFilters) > 0 {
+			return fmt.Errorf("TFT operation (%s) must have an empty packet filter list", tft.Operation)
+		}
+
+		if tft.Operation == OperationNoTFTOperation && len(tft.Parameters) == 0 {
+			return fmt.Errorf("TFT operation (%s) must have a parameters list", tft.Operation)
+		}
+
+	case OperationCreateNewTFT, OperationAddPacketFilters, OperationReplacePacketFilters, OperationDeletePacketFilters:
+		if len(tft.PacketFilters) == 0 {
+			return fmt.Errorf("TFT operation (%s) must have a non-empty packet filter list", tft.Operation)
+		}
+
+	case OperationIgnore:
+		// packet filters may be present, so they are validated below
+
+	default:
+		return fmt.Errorf("TFT operation (%d) is not valid", tft.Operation)
+	}
+
+	if len(tft.PacketFilters) > maximumPacketFiltersInTFT {
+		return fmt.Errorf("TFT has (%d) packet filters, but the maximum is (%d)", len(tft.PacketFilters), maximumPacketFiltersInTFT)
+	}
+
+	identifiers := make(map[uint8]bool)
+	precedences := make(map[uint8]bool)
+
+	for _, filter := range tft.PacketFilters {
+		if filter == nil {
+			return fmt.Errorf("packet filter is nil")
+		}
+
+		if filter.Identifier > maximumPacketFilterIdentifier {
+			return fmt.Errorf("packet filter identifier (%d) exceeds maximum 4-bit value", filter.Identifier)
+		}
+
+		if identifiers[filter.Identifier] {
+			return fmt.Errorf("packet filter identifier (%d) appears more than once", filter.Identifier)
+		}
+		identifiers[filter.Identifier] = true
+
+		if tft.Operation == OperationDeletePacketFilters {
+			if filter.Direction != 0 || filter.Precedence != 0 || len(filter.Components) > 0 {
+				return fmt.Errorf("packet filter (%d) for TFT operation (%s) must include only the identifier", filter.Identifier, tft.Operation)
+			}
+			continue
+		}
+
+		if filter.Direction > DirectionBidirectional {
+			return fmt.Errorf("packet filter (%d) direction (%d) is not valid", filter.Identifier, filter.Direction)
+		}
+
+		if precedences[filter.Precedence] {
+			return fmt.Errorf("packet filter (%d) precedence (%d) is used by another packet filter", filter.Identifier, filter.Precedence)
+		}
+		precedences[filter.Precedence] = true
+
+		if len(filter.Components) == 0 {
+			return fmt.Errorf("packet filter (%d) has no components", filter.Identifier)
+		}
+
+		if err := validateComponentCombination(filter.Components); err != nil {
+			return fmt.Errorf("on packet filter (%d): %s", filter.Identifier, err)
+		}
+	}
+
+	for _, parameter := range tft.Parameters {
+		if parameter == nil {
+			return fmt.Errorf("parameter is nil")
+		}
+
+		if len(parameter.Contents) > 0xff {
+			return fmt.Errorf("parameter (%d) contents length (%d) exceeds maximum (255)", parameter.ID, len(parameter.Contents))
+		}
+	}
+
+	return nil
+}
+
+// Encode validates the TFT (see Validate), then converts it to its
+// wire encoding
+func (tft *TFT) Encode() ([]byte, error) {
+	if err := tft.Validate(); err != nil {
+		return nil, err
+	}
+
+	firstOctet := byte(tft.Operation)<<5 | byte(len(tft.PacketFilters))
+	if len(tft.Parameters) > 0 {
+		firstOctet |= 0x10
+	}
+
+	encoded := []byte{firstOctet}
+
+	for _, filter := range tft.PacketFilters {
+		if tft.Operation == OperationDeletePacketFilters {
+			encoded = append(encoded, filter.Identifier)
+			continue
+		}
+
+		contents := make([]byte, 0, 32)
+		for _, component := range filter.Components {
+			value, err := component.encodeValue()
+			if err != nil {
+				return nil, fmt.Errorf("on packet filter (%d) component (%s): %s", filter.Identifier, component.Type(), err)
+			}
+
+			contents = append(contents, byte(component.Type()))
+			contents = append(contents, value...)
+		}
+
+		if len(contents) > maximumPacketFilterContentSize {
+			return nil, fmt.Errorf("packet filter (%d) contents length (%d) exceeds maximum (%d)", filter.Identifier, len(contents), maximumPacketFilterContentSize)
+		}
+
+		encoded = append(encoded, byte(filter.Direction)<<4|filter.Identifier, filter.Precedence, byte(len(contents)))
+		encoded = append(encoded, contents...)
+	}
+
+	for _, parameter := range tft.Parameters {
+		encoded = append(encoded, byte(parameter.ID), byte(len(parameter.Contents)))
+		encoded = append(encoded, parameter.Contents...)
+	}
+
+	return encoded, nil
+}
+
+// Decode converts the wire encoding of a TFT to a TFT.  It returns an error
+// if the encoding is structurally invalid (e.g., it is truncated, or has a
+// packet filter component of an unknown type), but it does not apply the
+// rules in Validate, so that a receiver may decide how to respond to a TFT
+// that breaks them.
+func Decode(encoded []byte) (*TFT, error) {
+	if len(encoded) < 1 {
+		return nil, fmt.Errorf("insufficient octets for TFT operation")
+	}
+
+	tft := &TFT{
+		Operation:     Operation(encoded[0] >> 5),
+		PacketFilters: make([]*PacketFilter, 0),
+		Parameters:    make([]*Parameter, 0),
+	}
+
+	parametersArePresent := encoded[0]&0x10 != 0
+	numberOfPacketFilters := int(encoded[0] & 0x0f)
+	remaining := encoded[1:]
+
+	for i := 0; i < numberOfPacketFilters; i++ {
+		if tft.Operation == OperationDeletePacketFilters {
+			if len(remaining) < 1 {
+				return nil, fmt.Errorf("insufficient octets for packet filter identifier (%d of %d)", i+1, numberOfPacketFilters)
+			}
+
+			tft.PacketFilters = append(tft.PacketFilters, &PacketFilter{Identifier: remaining[0] & 0x0f})
+			remaining = remaining[1:]
+			continue
+		}
+
+		if len(remaining) < 3 {
+			return nil, fmt.Errorf("insufficient octets for packet filter header (%d of %d)", i+1, numberOfPacketFilters)
+		}
+
+		filter := &PacketFilter{
+			Identifier: remaining[0] & 0x0f,
+			Direction:  Direction((remaining[0] >> 4) & 0x03),
+			Precedence: remaining[1],
+		}
+
+		contentsLength := int(remaining[2])
+		remaining = remaining[3:]
+
+		if len(remaining) < contentsLength {
+			return nil, fmt.Errorf("packet filter (%d) contents length (%d) exceeds remaining octets (%d)", filter.Identifier, contentsLength, len(remaining))
+		}
+
+		components, err := decodeComponents(remaining[:contentsLength])
+		if err != nil {
+			return nil, fmt.Errorf("on packet filter (%d): %s", filter.Identifier, err)
+		}
+
+		filter.Components = components
+		tft.PacketFilters = append(tft.PacketFilters, filter)
+		remaining = remaining[contentsLength:]
+	}
+
+	if !parametersArePresent {
+		if len(remaining) > 0 {
+			return nil, fmt.Errorf("(%d) octets follow the packet filter list, but the parameters list is not present", len(remaining))
+		}
+
+		return tft, nil
+	}
+
+	for len(remaining) > 0 {
+		if len(remaining) < 2 {
+			return nil, fmt.Errorf("insufficient octets for parameter header")
+		}
+
+		contentsLength := int(remaining[1])
+		if len(remaining)-2 < contentsLength {
+			return nil, fmt.Errorf("parameter (%d) contents length (%d) exceeds remaining octets (%d)", remaining[0], contentsLength, len(remaining)-2)
+		}
+
+		contents := make([]byte, contentsLength)
+		copy(contents, remaining[2:2+contentsLength])
+
+		tft.Parameters = append(tft.Parameters, &Parameter{ID: ParameterID(remaining[0]), Contents: contents})
+		remaining = remaining[2+contentsLength:]
+	}
+
+	return tft, nil
+}
@@ -0,0 +1,231 @@
+package tft_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/gtp/tft"
+	"github.com/go-test/deep"
+)
+
+func TestEncodeAndDecode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tft     *tft.TFT
+		encoded []byte
+	}{
+		{
+			name: "create new TFT",
+			tft: &tft.TFT{
+				Operation: tft.OperationCreateNewTFT,
+				PacketFilters: []*tft.PacketFilter{
+					{
+						Identifier: 1,
+						Direction:  tft.DirectionBidirectional,
+						Precedence: 10,
+						Components: []tft.Component{
+							&tft.IPv4RemoteAddress{Address: net.IPv4(10, 1, 0, 0).To4(), Mask: net.CIDRMask(16, 32)},
+							&tft.ProtocolIdentifier{Protocol: 17},
+							&tft.SingleRemotePort{Port: 5060},
+						},
+					},
+					{
+						Identifier: 2,
+						Direction:  tft.DirectionDownlinkOnly,
+						Precedence: 11,
+						Components: []tft.Component{
+							&tft.IPv6RemoteAddressPrefixLength{Address: net.ParseIP("2001:db8::"), PrefixLength: 32},
+							&tft.LocalPortRange{Low: 10000, High: 10100},
+						},
+					},
+				},
+				Parameters: []*tft.Parameter{},
+			},
+			encoded: []byte{
+				0x22,
+				0x31, 0x0a, 0x0e, 0x10, 0x0a, 0x01, 0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0x30, 0x11, 0x50, 0x13, 0xc4,
+				0x12, 0x0b, 0x17, 0x21, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20,
+				0x41, 0x27, 0x10, 0x27, 0x74,
+			},
+		},
+		{
+			name: "delete packet filters",
+			tft: &tft.TFT{
+				Operation:     tft.OperationDeletePacketFilters,
+				PacketFilters: []*tft.PacketFilter{{Identifier: 1}, {Identifier: 15}},
+				Parameters:    []*tft.Parameter{},
+			},
+			encoded: []byte{0xa2, 0x01, 0x0f},
+		},
+		{
+			name: "delete existing TFT",
+			tft: &tft.TFT{
+				Operation:     tft.OperationDeleteExistingTFT,
+				PacketFilters: []*tft.PacketFilter{},
+				Parameters:    []*tft.Parameter{},
+			},
+			encoded: []byte{0x40},
+		},
+		{
+			name: "no TFT operation with parameters",
+			tft: &tft.TFT{
+				Operation:     tft.OperationNoTFTOperation,
+				PacketFilters: []*tft.PacketFilter{},
+				Parameters:    []*tft.Parameter{{ID: tft.ParameterIDPacketFilterIdentifier, Contents: []byte{0x03}}},
+			},
+			encoded: []byte{0xd0, 0x03, 0x01, 0x03},
+		},
+	}
+
+	for _, testCase := range testCases {
+		encoded, err := testCase.tft.Encode()
+		if err != nil {
+			t.Errorf("[%s] on Encode expected no error, got = (%s)", testCase.name, err.Error())
+		} else if diff := deep.Equal(testCase.encoded, encoded); diff != nil {
+			t.Errorf("[%s] on Encode: %s", testCase.name, diff)
+		}
+
+		decoded, err := tft.Decode(testCase.encoded)
+		if err != nil {
+			t.Errorf("[%s] on Decode expected no error, got = (%s)", testCase.name, err.Error())
+		} else if diff := deep.Equal(testCase.tft, decoded); diff != nil {
+			t.Errorf("[%s] on Decode: %s", testCase.name, diff)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	remotePort := func(port uint16) []tft.Component {
+		return []tft.Component{&tft.SingleRemotePort{Port: port}}
+	}
+
+	testCases := []struct {
+		name          string
+		tft           *tft.TFT
+		errorContains string
+	}{
+		{
+			name:          "create with no packet filters",
+			tft:           &tft.TFT{Operation: tft.OperationCreateNewTFT},
+			errorContains: "non-empty packet filter list",
+		},
+		{
+			name:          "delete existing TFT with packet filters",
+			tft:           &tft.TFT{Operation: tft.OperationDeleteExistingTFT, PacketFilters: []*tft.PacketFilter{{Identifier: 1}}},
+			errorContains: "empty packet filter list",
+		},
+		{
+			name:          "no TFT operation without parameters",
+			tft:           &tft.TFT{Operation: tft.OperationNoTFTOperation},
+			errorContains: "parameters list",
+		},
+		{
+			name:          "reserved operation",
+			tft:           &tft.TFT{Operation: 7},
+			errorContains: "reserved",
+		},
+		{
+			name: "duplicate identifier",
+			tft: &tft.TFT{Operation: tft.OperationAddPacketFilters, PacketFilters: []*tft.PacketFilter{
+				{Identifier: 1, Precedence: 1, Components: remotePort(1)},
+				{Identifier: 1, Precedence: 2, Components: remotePort(2)},
+			}},
+			errorContains: "identifier (1) appears more than once",
+		},
+		{
+			name: "duplicate precedence",
+			tft: &tft.TFT{Operation: tft.OperationReplacePacketFilters, PacketFilters: []*tft.PacketFilter{
+				{Identifier: 1, Precedence: 5, Components: remotePort(1)},
+				{Identifier: 2, Precedence: 5, Components: remotePort(2)},
+			}},
+			errorContains: "precedence (5)",
+		},
+		{
+			name:          "identifier too large",
+			tft:           &tft.TFT{Operation: tft.OperationDeletePacketFilters, PacketFilters: []*tft.PacketFilter{{Identifier: 16}}},
+			errorContains: "4-bit",
+		},
+		{
+			name:          "delete packet filters with components",
+			tft:           &tft.TFT{Operation: tft.OperationDeletePacketFilters, PacketFilters: []*tft.PacketFilter{{Identifier: 1, Components: remotePort(1)}}},
+			errorContains: "only the identifier",
+		},
+		{
+			name:          "packet filter with no components",
+			tft:           &tft.TFT{Operation: tft.OperationCreateNewTFT, PacketFilters: []*tft.PacketFilter{{Identifier: 1}}},
+			errorContains: "no components",
+		},
+		{
+			name: "conflicting components",
+			tft: &tft.TFT{Operation: tft.OperationCreateNewTFT, PacketFilters: []*tft.PacketFilter{{Identifier: 1, Components: []tft.Component{
+				&tft.SingleRemotePort{Port: 80},
+				&tft.RemotePortRange{Low: 80, High: 90},
+			}}}},
+			errorContains: "may not be used together",
+		},
+		{
+			name: "repeated component",
+			tft: &tft.TFT{Operation: tft.OperationCreateNewTFT, PacketFilters: []*tft.PacketFilter{{Identifier: 1, Components: []tft.Component{
+				&tft.ProtocolIdentifier{Protocol: 6},
+				&tft.ProtocolIdentifier{Protocol: 17},
+			}}}},
+			errorContains: "more than once",
+		},
+		{
+			name: "flow label with IPv4 remote address",
+			tft: &tft.TFT{Operation: tft.OperationCreateNewTFT, PacketFilters: []*tft.PacketFilter{{Identifier: 1, Components: []tft.Component{
+				&tft.IPv4RemoteAddress{Address: net.IPv4(10, 0, 0, 1)},
+				&tft.FlowLabel{Label: 1},
+			}}}},
+			errorContains: "may not be used together",
+		},
+		{
+			name:          "invalid direction",
+			tft:           &tft.TFT{Operation: tft.OperationCreateNewTFT, PacketFilters: []*tft.PacketFilter{{Identifier: 1, Direction: 4, Components: remotePort(1)}}},
+			errorContains: "direction (4)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.tft.Validate()
+		if err == nil {
+			t.Errorf("[%s] expected error on Validate, got none", testCase.name)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), testCase.errorContains) {
+			t.Errorf("[%s] expected error on Validate to contain (%s), got (%s)", testCase.name, testCase.errorContains, err.Error())
+		}
+
+		if _, err := testCase.tft.Encode(); err == nil {
+			t.Errorf("[%s] expected error on Encode, got none", testCase.name)
+		}
+	}
+}
+
+func TestDecodeInvalidCases(t *testing.T) {
+	for _, invalid := range [][]byte{
+		{},
+		{0x21},
+		{0x21, 0x31, 0x0a},
+		{0x21, 0x31, 0x0a, 0x03, 0x30, 0x11},
+		{0x21, 0x31, 0x0a, 0x02, 0x99, 0x00},
+		{0x21, 0x31, 0x0a, 0x02, 0x30, 0x11, 0x00},
+		{0xa2, 0x01},
+		{0xd0, 0x03, 0x02, 0x01},
+	} {
+		if _, err := tft.Decode(invalid); err == nil {
+			t.Errorf("[% x] expected error on Decode, got none", invalid)
+		}
+	}
+
+	decoded, err := tft.Decode([]byte{0x20})
+	if err != nil {
+		t.Fatalf("expected no error on Decode for create with no packet filters, got = (%s)", err.Error())
+	}
+
+	if err := decoded.Validate(); err == nil {
+		t.Errorf("expected error on Validate for decoded create with no packet filters, got none")
+	}
+}